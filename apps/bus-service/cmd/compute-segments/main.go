@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/app"
+	"github.com/perkzen/mbus/apps/bus-service/internal/config"
+	"github.com/perkzen/mbus/apps/bus-service/internal/db"
+	"github.com/perkzen/mbus/apps/bus-service/internal/provider/routing"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/migrations"
+)
+
+// lineDirection groups the stops of one line in one direction.
+type lineDirection struct {
+	lineID      int
+	line        string
+	directionID int
+	direction   string
+	stops       []store.LineStop
+}
+
+// compute-segments routes every line and direction stop by stop with the
+// configured routing provider and stores the results in the segments table.
+// An optional argument limits the run to one line.
+func main() {
+	env, err := config.LoadEnvironment()
+	if err != nil {
+		log.Fatalf("❌ Failed to load environment: %v", err)
+	}
+
+	if env.SegmentBatchSize < 2 {
+		log.Fatalf("❌ SEGMENT_BATCH_SIZE must be at least 2, got %d", env.SegmentBatchSize)
+	}
+
+	pgDb, err := db.NewPostgresDB(env.PostgresURL).Open()
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to database: %v", err)
+	}
+	defer pgDb.Close()
+
+	if err := db.MigrateFS(pgDb, migrations.FS, "."); err != nil {
+		log.Fatalf("❌ Failed to run DB migrations: %v", err)
+	}
+
+	router, err := app.NewRouter(env)
+	if err != nil {
+		log.Fatalf("❌ Failed to create routing provider: %v", err)
+	}
+
+	var onlyLine string
+	if len(os.Args) > 1 {
+		onlyLine = os.Args[1]
+	}
+
+	ctx := context.Background()
+	segmentStore := store.NewPostgresSegmentStore(pgDb)
+
+	stops, err := segmentStore.ListLineStops(ctx, store.ScheduleTypeWeekday)
+	if err != nil {
+		log.Fatalf("❌ list line stops: %v", err)
+	}
+
+	var total int
+	for _, group := range groupStops(stops) {
+		if onlyLine != "" && group.line != onlyLine {
+			continue
+		}
+
+		segments, err := computeSegments(ctx, router, group, env.SegmentBatchSize, env.SegmentRequestInterval)
+		if err != nil {
+			log.Printf("⚠️ line %s (%s): %v", group.line, group.direction, err)
+			continue
+		}
+
+		if err := segmentStore.ReplaceSegments(ctx, group.lineID, group.directionID, segments); err != nil {
+			log.Fatalf("❌ store segments for line %s (%s): %v", group.line, group.direction, err)
+		}
+
+		total += len(segments)
+		log.Printf("✅ line %s (%s): %d segments", group.line, group.direction, len(segments))
+	}
+
+	version, err := store.NewPostgresDataVersionStore(pgDb).BumpDataVersion(ctx, "segments")
+	if err != nil {
+		log.Fatalf("❌ bump data version: %v", err)
+	}
+	log.Printf("✅ Stored %d segments using %s, data generation bumped to %d.", total, router.Name(), version.Generation)
+}
+
+func groupStops(stops []store.LineStop) []lineDirection {
+	var groups []lineDirection
+	for _, s := range stops {
+		n := len(groups)
+		if n == 0 || groups[n-1].lineID != s.LineID || groups[n-1].directionID != s.DirectionID {
+			groups = append(groups, lineDirection{
+				lineID:      s.LineID,
+				line:        s.Line,
+				directionID: s.DirectionID,
+				direction:   s.Direction,
+			})
+			n++
+		}
+		groups[n-1].stops = append(groups[n-1].stops, s)
+	}
+	return groups
+}
+
+// computeSegments routes consecutive stops in windows of batchSize stops.
+// Windows overlap by one stop so that every consecutive pair lands in exactly
+// one matrix request.
+func computeSegments(ctx context.Context, router routing.Provider, group lineDirection, batchSize int, interval time.Duration) ([]store.Segment, error) {
+	stops := group.stops
+	segments := make([]store.Segment, 0, max(len(stops)-1, 0))
+
+	for start := 0; start < len(stops)-1; start += batchSize - 1 {
+		end := min(start+batchSize, len(stops))
+		window := stops[start:end]
+
+		points := make([]routing.Point, len(window))
+		for i, s := range window {
+			points[i] = routing.Point{Lat: s.Lat, Lon: s.Lon}
+		}
+
+		matrix, err := router.Matrix(ctx, points)
+		if err != nil {
+			return nil, err
+		}
+
+		// Only the consecutive pairs are read, so an unreachable pair of
+		// unrelated stops in the window does not fail the line.
+		for i := 0; i < len(window)-1; i++ {
+			distance, duration, err := matrix.Cell(i, i+1)
+			if err != nil {
+				return nil, fmt.Errorf("segment %d from stop %d to %d: %w", start+i, window[i].Code, window[i+1].Code, err)
+			}
+			segments = append(segments, store.Segment{
+				Sequence:    start + i,
+				FromCodeID:  window[i].CodeID,
+				ToCodeID:    window[i+1].CodeID,
+				DistanceKm:  distance,
+				DurationMin: duration,
+				Provider:    router.Name(),
+			})
+		}
+
+		// Stay below the request rate limits of hosted providers.
+		time.Sleep(interval)
+	}
+
+	return segments, nil
+}
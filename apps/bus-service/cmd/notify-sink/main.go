@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/notify/sink"
+)
+
+// notify-sink stands in for the mail server and webhook endpoints reminders
+// are delivered to and prints everything it receives. Point SMTP_HOST and
+// SMTP_PORT at its SMTP address and subscribe with a webhook target of
+// http://<http address>/, which needs WEBHOOK_ALLOW_PRIVATE_TARGETS=true.
+func main() {
+	smtpAddr := flag.String("smtp", "localhost:2525", "SMTP listen address")
+	httpAddr := flag.String("http", "localhost:8025", "webhook listen address")
+	flag.Parse()
+
+	smtpServer, err := sink.ListenSMTP(*smtpAddr, func(m sink.Mail) {
+		log.Printf("📧 mail from %s to %v\n%s", m.From, m.To, m.Data)
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to start SMTP sink: %v", err)
+	}
+	defer smtpServer.Close()
+	log.Printf("✅ SMTP sink is running at %s", smtpServer.Addr())
+
+	receiver := sink.NewWebhookReceiver(os.Getenv("WEBHOOK_SECRET"), func(w sink.Webhook) {
+		log.Printf("🔔 webhook (verified: %t): %+v", w.Verified, w.Reminder)
+	})
+	log.Printf("✅ Webhook sink is running at http://%s/", *httpAddr)
+	if err := http.ListenAndServe(*httpAddr, receiver); err != nil {
+		log.Fatalf("❌ Webhook sink error: %v", err)
+	}
+}
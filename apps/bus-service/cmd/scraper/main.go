@@ -1,6 +1,7 @@
 package main
 
 import (
+	"github.com/perkzen/mbus/apps/bus-service/internal/config"
 	"github.com/perkzen/mbus/apps/bus-service/internal/provider/marprom"
 	"log"
 	"os"
@@ -16,6 +17,17 @@ type dayOption struct {
 }
 
 func main() {
+	env, err := config.LoadEnvironment()
+	if err != nil {
+		log.Fatalf("Failed to load environment: %v", err)
+	}
+	// Dates are picked in the agency timezone, as the server reads them.
+	loc, err := time.LoadLocation(env.Timezone)
+	if err != nil {
+		log.Fatalf("Failed to load timezone %q: %v", env.Timezone, err)
+	}
+	utils.SetLocation(loc)
+
 	dayOptions := map[string]dayOption{
 		"weekday":  {utils.Weekday, "data/seed-weekday.json"},
 		"saturday": {utils.Saturday, "data/seed-saturday.json"},
@@ -37,7 +49,7 @@ func main() {
 			os.Exit(1)
 		}
 	} else {
-		date = time.Now().Format("2006-01-02")
+		date = utils.Today()
 		filename = "data/seed-today.json"
 	}
 
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"hash/fnv"
 	"log"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
@@ -35,14 +40,18 @@ func main() {
 		log.Fatalf("❌ COUNT(bus_stations) failed: %v", err)
 	}
 
+	stations := loadSeedData("seed-weekday.json")
 	if have < expectedStations {
-		stations := loadSeedData("seed-weekday.json")
 		lineIDs := upsertBusLines(stations)
 		stationIDs, codeIDs := upsertBusStations(stations, lineIDs)
 		log.Printf("✅ Inserted %d bus stations, %d station codes, %d lines.", len(stationIDs), len(codeIDs), len(lineIDs))
 	} else {
 		log.Printf("✅ DB already seeded with %d stations – skipping.", have)
 	}
+	updateStops(stations)
+	log.Println("✅ Stop positions and platforms updated.")
+	updateLineMetadata(stations)
+	log.Println("✅ Line metadata updated.")
 
 	lineIDs := loadLineIDs()
 	codeIDs := loadStationCodeIDs()
@@ -53,6 +62,12 @@ func main() {
 		insertDepartures(day, seedData, lineIDs, codeIDs, directionIDs)
 	}
 	log.Println("✅ All departures inserted.")
+
+	version, err := store.NewPostgresDataVersionStore(pgDb).BumpDataVersion(context.Background(), "seed")
+	if err != nil {
+		log.Fatalf("❌ bump data version: %v", err)
+	}
+	log.Printf("✅ Data generation bumped to %d.", version.Generation)
 }
 
 func initDB() error {
@@ -146,12 +161,12 @@ func upsertBusStations(stations []marprom.BusStationWithDetails, lineIDs map[str
 	}
 
 	qbCodes := qb.Insert("station_codes").
-		Columns("station_id", "code").
+		Columns("station_id", "code", "lat", "lng").
 		Suffix("ON CONFLICT (code) DO NOTHING")
 	for _, s := range stations {
 		codeInt, _ := strconv.Atoi(s.Code)
 		stationID := stationNameToID[s.Name]
-		qbCodes = qbCodes.Values(stationID, codeInt)
+		qbCodes = qbCodes.Values(stationID, codeInt, s.Lat, s.Lon)
 	}
 	query, args, err = qbCodes.ToSql()
 	if err != nil {
@@ -188,6 +203,202 @@ func upsertBusStations(stations []marprom.BusStationWithDetails, lineIDs map[str
 	return stationIDs, codeIDs
 }
 
+// updateStops moves every stop to its own position from the seed data, letters
+// the platforms of each station in code order and centres each station on
+// its stops. The seed data lists one entry per stop, so a station's first
+// entry is not representative of where the station is.
+func updateStops(stations []marprom.BusStationWithDetails) {
+	for _, s := range stations {
+		code, err := strconv.Atoi(s.Code)
+		if err != nil {
+			log.Fatalf("❌ invalid stop code %q: %v", s.Code, err)
+		}
+		q, a, _ := qb.Update("station_codes").
+			Set("lat", s.Lat).
+			Set("lng", s.Lon).
+			Where(sq.Eq{"code": code}).
+			ToSql()
+		if _, err := pgDb.Exec(q, a...); err != nil {
+			log.Fatalf("❌ update position of stop %d: %v", code, err)
+		}
+	}
+
+	if _, err := pgDb.Exec(`
+		UPDATE station_codes sc
+		SET platform = p.platform
+		FROM (SELECT id, CASE WHEN n <= 26 THEN chr(64 + n::int) ELSE n::text END AS platform
+		      FROM (SELECT id, row_number() OVER (PARTITION BY station_id ORDER BY code) AS n
+		            FROM station_codes) numbered) p
+		WHERE p.id = sc.id`); err != nil {
+		log.Fatalf("❌ assign platforms: %v", err)
+	}
+
+	if _, err := pgDb.Exec(`
+		UPDATE bus_stations bs
+		SET lat = c.lat, lng = c.lng
+		FROM (SELECT station_id, AVG(lat) AS lat, AVG(lng) AS lng
+		      FROM station_codes
+		      GROUP BY station_id) c
+		WHERE c.station_id = bs.id`); err != nil {
+		log.Fatalf("❌ centre stations on their stops: %v", err)
+	}
+}
+
+// lineOperator runs all lines in the seed data.
+const lineOperator = "Marprom"
+
+// linePalettes hold the badge colours of each category. A line's colour is
+// picked from its palette by the hash of its name, see lineColor.
+var linePalettes = map[string][]string{
+	store.LineCategoryCity: {"#E4002B", "#0072CE", "#00965E", "#FF8200", "#7A3E9D", "#00A3AD"},
+	store.LineCategorySuburban: {
+		"#6D2077", "#B5BD00", "#C6007E", "#005EB8", "#DA291C", "#8A8D8F", "#009639",
+		"#ED8B00", "#00857D", "#A4343A", "#41748D", "#CE0F69", "#658D1B",
+	},
+	store.LineCategoryOther: {"#53565A"},
+}
+
+// updateLineMetadata derives the category, colours, long name, operator and
+// sort order of every line. Admin overrides live in their own table and are
+// left alone.
+func updateLineMetadata(stations []marprom.BusStationWithDetails) {
+	// Departures per direction of each line; the busiest route names the
+	// termini.
+	trips := map[string]map[string]int{}
+	for _, s := range stations {
+		for _, l := range s.Lines {
+			if trips[l] == nil {
+				trips[l] = map[string]int{}
+			}
+		}
+		for _, d := range s.Departures {
+			if trips[d.Line] == nil {
+				trips[d.Line] = map[string]int{}
+			}
+			trips[d.Line][d.Direction] += len(d.Times)
+		}
+	}
+
+	hubs := map[string]int{}
+	lines := make([]string, 0, len(trips))
+	for l, directions := range trips {
+		lines = append(lines, l)
+		served := map[string]bool{}
+		for d := range directions {
+			a, b := termini(d)
+			served[a], served[b] = true, true
+		}
+		for t := range served {
+			hubs[t]++
+		}
+	}
+	slices.SortFunc(lines, func(a, b string) int {
+		if c := lineNumber(a) - lineNumber(b); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+
+	for i, line := range lines {
+		category := lineCategory(line)
+		color := lineColor(line, category)
+
+		q, a, _ := qb.Update("bus_lines").
+			Set("category", category).
+			Set("color", color).
+			Set("text_color", textColor(color)).
+			Set("long_name", longName(trips[line], hubs)).
+			Set("operator", lineOperator).
+			Set("sort_order", i+1).
+			Where(sq.Eq{"name": line}).
+			ToSql()
+		if _, err := pgDb.Exec(q, a...); err != nil {
+			log.Fatalf("❌ update metadata of line %s: %v", line, err)
+		}
+	}
+}
+
+// lineCategory tells city lines (G1, G2, ...) from suburban ones (P7, ...).
+func lineCategory(line string) string {
+	switch {
+	case strings.HasPrefix(line, "G"):
+		return store.LineCategoryCity
+	case strings.HasPrefix(line, "P"):
+		return store.LineCategorySuburban
+	default:
+		return store.LineCategoryOther
+	}
+}
+
+// lineColor picks the colour of a line from the palette of its category by
+// the hash of its name, so a line keeps its colour when lines are added or
+// removed and the data is seeded again.
+func lineColor(line, category string) string {
+	palette := linePalettes[category]
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(line))
+	return palette[h.Sum32()%uint32(len(palette))]
+}
+
+// lineNumber is the number in a line name, or 0 without one.
+func lineNumber(line string) int {
+	n, _ := strconv.Atoi(strings.TrimLeftFunc(line, func(r rune) bool { return r < '0' || r > '9' }))
+	return n
+}
+
+// termini returns the first and last stop of a direction, e.g. "Avtobusna
+// postaja" and "Tezno" for "Avtobusna postaja - Ljubljanska - Tezno". Stops
+// are separated by " - ", as stop names may contain hyphens themselves.
+func termini(direction string) (string, string) {
+	stops := strings.Split(direction, " - ")
+	return strings.TrimSpace(stops[0]), strings.TrimSpace(stops[len(stops)-1])
+}
+
+// longName names the termini of the busiest route of a line in both
+// directions, starting from the one more lines serve, so the bus station
+// comes first: "Avtobusna postaja - Tezno". hubs counts the lines serving
+// each terminus.
+func longName(directions map[string]int, hubs map[string]int) string {
+	routes := map[[2]string]int{}
+	for d, n := range directions {
+		a, b := termini(d)
+		if b < a {
+			a, b = b, a
+		}
+		routes[[2]string{a, b}] += n
+	}
+
+	var main [2]string
+	for r, n := range routes {
+		if main[0] == "" || n > routes[main] || n == routes[main] && (r[0] < main[0] || r[0] == main[0] && r[1] < main[1]) {
+			main = r
+		}
+	}
+	switch {
+	case main[0] == "":
+		return ""
+	case main[0] == main[1]:
+		return main[0]
+	case hubs[main[1]] > hubs[main[0]]:
+		return main[1] + " - " + main[0]
+	default:
+		return main[0] + " - " + main[1]
+	}
+}
+
+// textColor picks black or white, whichever reads better on the #RRGGBB
+// background color.
+func textColor(color string) string {
+	var r, g, b int
+	if _, err := fmt.Sscanf(color, "#%02x%02x%02x", &r, &g, &b); err != nil {
+		log.Fatalf("❌ invalid line colour %q: %v", color, err)
+	}
+	if (r*299+g*587+b*114)/1000 >= 150 {
+		return "#000000"
+	}
+	return "#FFFFFF"
+}
+
 func upsertDirections(stations []marprom.BusStationWithDetails) map[string]int {
 	directionIDs := make(map[string]int)
 	unique := map[string]struct{}{}
@@ -221,7 +432,7 @@ func insertDepartures(schedule string, stations []marprom.BusStationWithDetails,
 		CodeID        int
 		LineID        int
 		DirectionID   int
-		DepartureTime string
+		DepartureTime utils.ServiceTime
 		ScheduleType  string
 	}
 
@@ -253,11 +464,15 @@ func insertDepartures(schedule string, stations []marprom.BusStationWithDetails,
 				log.Fatalf("❌ unknown direction: %s", d.Direction)
 			}
 			for _, t := range d.Times {
+				departureTime, err := utils.ServiceTimeFromClock(t)
+				if err != nil {
+					log.Fatalf("❌ departure of line %s at %s: %v", d.Line, s.Code, err)
+				}
 				buffer = append(buffer, row{
 					CodeID:        codeID,
 					LineID:        lineID,
 					DirectionID:   dirID,
-					DepartureTime: t,
+					DepartureTime: departureTime,
 					ScheduleType:  schedule,
 				})
 				if len(buffer) >= batchSize {
@@ -1,26 +1,53 @@
 package main
 
 import (
+	"context"
 	"errors"
-	_ "github.com/perkzen/mbus/apps/bus-service/docs"
+	"fmt"
+	_ "github.com/perkzen/mbus/apps/bus-service/docs/v1"
+	_ "github.com/perkzen/mbus/apps/bus-service/docs/v2"
 	"github.com/perkzen/mbus/apps/bus-service/internal/app"
 	"github.com/perkzen/mbus/apps/bus-service/internal/config"
 	"github.com/perkzen/mbus/apps/bus-service/internal/server"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
 	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
 	"log"
+	"net"
 	"net/http"
 	"time"
 )
 
 // @title mubs Bus Service API
 // @version 1.0
-// @description This is the API documentation for the mubs Bus Service.
+// @description This is the API documentation for the mubs Bus Service. The unversioned public endpoints are deprecated in favour of /api/v2 and answer with Deprecation and Sunset headers.
+// @securityDefinitions.apikey AdminToken
+// @in header
+// @name Authorization
+// @description Admin token as "Bearer <ADMIN_TOKEN>"
 func main() {
 	env, err := config.LoadEnvironment()
 	if err != nil {
 		log.Fatalf("❌ Failed to load environment variables: %v", err)
 	}
 
+	shutdownTracing, err := telemetry.Init(context.Background(), telemetry.Options{
+		ServiceName:  env.TracingServiceName,
+		Exporter:     env.TracingExporter,
+		OTLPEndpoint: env.OTLPEndpoint,
+		OTLPInsecure: env.OTLPInsecure,
+		SampleRatio:  env.TracingSampleRatio,
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize tracing: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Printf("Failed to flush traces: %v", err)
+		}
+	}()
+
 	var restApp *app.Application
 
 	err = utils.Retry("Initialize application (DB + Redis)", 10, 3*time.Second, func() error {
@@ -35,13 +62,33 @@ func main() {
 
 	httpServer := server.NewHttpServer(restApp)
 	log.Printf("✅ Server is running at http://localhost%s\n", httpServer.Addr)
-	log.Printf("Swagger documentation is available at http://localhost%s/swagger/index.html\n", httpServer.Addr)
+	log.Printf("Swagger documentation is available at http://localhost%s/swagger/v2/index.html (v1: /swagger/v1/index.html)\n", httpServer.Addr)
 
 	defer restApp.DB.Close()
 	defer restApp.Cache.Close()
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	grpcServer := server.NewGrpcServer(ctx, restApp)
+	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", env.GRPCPort))
+	if err != nil {
+		log.Fatalf("❌ Failed to listen for gRPC: %v", err)
+	}
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatalf("❌ gRPC server error: %v", err)
+		}
+	}()
+	log.Printf("✅ gRPC server is running at localhost:%d\n", env.GRPCPort)
+
+	if env.RemindersEnabled {
+		go restApp.ReminderScheduler.Run(ctx)
+		log.Printf("✅ Reminder scheduler is running every %s\n", env.ReminderInterval)
+	}
+
 	done := make(chan bool, 1)
-	go server.GracefulShutdown(httpServer, done)
+	go server.GracefulShutdown(httpServer, grpcServer, done)
 
 	err = httpServer.ListenAndServe()
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -0,0 +1,2168 @@
+// Package v1 Code generated by swaggo/swag. DO NOT EDIT
+package v1
+
+import "github.com/swaggo/swag"
+
+const docTemplatev1 = `{
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "contact": {},
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/api/admin/alerts": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Publish a service notice, e.g. a detour or a stop closure, for some lines or, without lines, for the whole network. Title and body are the Slovenian source text; translate them with PUT /api/admin/translations.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Create an alert",
+                "parameters": [
+                    {
+                        "description": "Alert",
+                        "name": "alert",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AlertRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created alert",
+                        "schema": {
+                            "$ref": "#/definitions/Alert"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid alert",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/alerts/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Remove an alert and its translations",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete an alert",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Alert id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Deleted"
+                    },
+                    "404": {
+                        "description": "No such alert",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/bus-lines/{id}/metadata": {
+            "put": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Replace the metadata an admin set for a bus line. Omitted or null fields fall back to the values derived by the seeder, which never overwrites these.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Override bus line metadata",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bus line id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Metadata",
+                        "name": "metadata",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/LineMetadataRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Bus line with its resulting metadata",
+                        "schema": {
+                            "$ref": "#/definitions/BusLine"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid metadata",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bus line not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/bus-stations/attributes/import": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Set station attributes from CSV. The header row names a stationId column and one or more attribute columns (wheelchairAccessible, shelter, bench, realtimeDisplay, ticketMachine, tactilePaving; snake_case works as well). Values are yes/no, true/false or 1/0; an empty value marks the attribute as unknown. Attributes without a column keep their values. Nothing is imported if any row is invalid.",
+                "consumes": [
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Import station attributes",
+                "parameters": [
+                    {
+                        "description": "CSV with a header row",
+                        "name": "csv",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of imported stations",
+                        "schema": {
+                            "$ref": "#/definitions/StationAttributesImport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid CSV",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/bus-stations/{id}/attributes": {
+            "put": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Replace the accessibility and amenity attributes of a bus station. Omitted or null attributes become unknown.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Set station attributes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bus station id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Attributes",
+                        "name": "attributes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/StationAttributesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored attributes",
+                        "schema": {
+                            "$ref": "#/definitions/StationAttributes"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid attributes",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bus station not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/cache/invalidate": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Drop cached timetables touching a bus station or any station of a bus line",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Invalidate cached timetables",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bus station id",
+                        "name": "station",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bus line name",
+                        "name": "line",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of deleted keys",
+                        "schema": {
+                            "$ref": "#/definitions/CachePurgeResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/cache/keys": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "List cached keys matching a glob pattern. Patterns are always scoped to the service prefix.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List cache keys",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "*",
+                        "description": "Glob pattern, e.g. g3:timetable:*",
+                        "name": "pattern",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matching keys",
+                        "schema": {
+                            "$ref": "#/definitions/CacheKeyList"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Delete cached keys matching a glob pattern",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Purge cache keys",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Glob pattern, e.g. g3:timetable:*",
+                        "name": "pattern",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of deleted keys",
+                        "schema": {
+                            "$ref": "#/definitions/CachePurgeResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/data-version": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Current data generation used to namespace cache keys",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get data version",
+                "responses": {
+                    "200": {
+                        "description": "Current data version",
+                        "schema": {
+                            "$ref": "#/definitions/DataVersion"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Start a new data generation, invalidating every versioned cache entry",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Bump data version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "admin",
+                        "description": "Reason recorded with the new generation",
+                        "name": "reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New data version",
+                        "schema": {
+                            "$ref": "#/definitions/DataVersion"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/images/sync": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Report whether an image sync is running and the outcome of the last one started on this instance",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get the image sync status",
+                "responses": {
+                    "200": {
+                        "description": "Running or last sync",
+                        "schema": {
+                            "$ref": "#/definitions/ImageSyncStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "No sync has run yet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Start fetching the photos of all stations that are not stored yet, or of all stations with force, from the operator. The sync runs in the background; poll GET /api/admin/images/sync for its outcome. Stations whose photo is missing get no image URL.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Copy station images",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Fetch stored images again",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Sync started",
+                        "schema": {
+                            "$ref": "#/definitions/ImageSyncStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "A sync is already running",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/translations": {
+            "put": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Create or replace the localized value of a translatable field, e.g. a bus line description. The 'sl' value is the source text other languages fall back to.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Set a translation",
+                "parameters": [
+                    {
+                        "description": "Translation",
+                        "name": "translation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/TranslationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored translation",
+                        "schema": {
+                            "$ref": "#/definitions/Translation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid translation",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Remove the localized value of a field in one language",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete a translation",
+                "parameters": [
+                    {
+                        "enum": [
+                            "bus_line",
+                            "alert"
+                        ],
+                        "type": "string",
+                        "description": "Entity type",
+                        "name": "entityType",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entity id",
+                        "name": "entityId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field name, e.g. description or title",
+                        "name": "field",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Language",
+                        "name": "lang",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Deleted"
+                    },
+                    "404": {
+                        "description": "No such translation",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/bus-lines": {
+            "get": {
+                "description": "Retrieve a list of bus lines. Without a limit all lines are returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bus Lines"
+                ],
+                "summary": "Get bus lines",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Limit the number of results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination, ignored with a cursor",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from the X-Next-Cursor header or Link header of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "name",
+                            "-name",
+                            "distance",
+                            "-distance",
+                            "stationCount",
+                            "-stationCount"
+                        ],
+                        "type": "string",
+                        "default": "name",
+                        "description": "Sort field, prefix with - for descending; name follows the sort order of the lines, then G1, G2, ..., P19",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the total count in the X-Total-Count header",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude of the origin for distances",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude of the origin for distances",
+                        "name": "lon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by bus line name prefix",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only lines serving this bus station id",
+                        "name": "station",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response language, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of bus lines",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/BusLine"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Next page, rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor of the next page"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of matching lines"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/bus-stations": {
+            "get": {
+                "description": "Retrieve a list of bus stations with optional filters",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bus Stations"
+                ],
+                "summary": "Get bus stations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit the number of results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination, ignored with a cursor",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from the X-Next-Cursor header or Link header of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "name",
+                            "-name",
+                            "distance",
+                            "-distance",
+                            "lineCount",
+                            "-lineCount"
+                        ],
+                        "type": "string",
+                        "default": "name",
+                        "description": "Sort field, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the total count in the X-Total-Count header",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude of the origin for distances",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude of the origin for distances",
+                        "name": "lon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by bus station name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by bus line",
+                        "name": "line",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "wheelchairAccessible",
+                                "shelter",
+                                "bench",
+                                "realtimeDisplay",
+                                "ticketMachine",
+                                "tactilePaving"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only stations known to have all of these attributes",
+                        "name": "has",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response language, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of bus stations",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/BusStation"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Next page, rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor of the next page"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of matching stations"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/bus-stations/{id}": {
+            "get": {
+                "description": "Retrieve a bus station by its id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bus Stations"
+                ],
+                "summary": "Get bus station by id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bus station id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response language, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Bus station details",
+                        "schema": {
+                            "$ref": "#/definitions/BusStation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid id",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bus station not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/bus-stations/{id}/stops": {
+            "get": {
+                "description": "Retrieve the stops of a bus station, one per stop code, with their platform, position and the lines and directions departing from them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bus Stations"
+                ],
+                "summary": "Get the stops of a bus station",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bus station id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response language, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stops of the bus station in code order",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Stop"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid id",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bus station not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/departures": {
+            "get": {
+                "description": "Retrieve departures between two bus stations on a specific date",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Departures"
+                ],
+                "summary": "Get departures",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Departure station code",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Arrival station code",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date in YYYY-MM-DD format, defaults to the current service day, which runs until 03:00 the next morning",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only departures at or after this time (HH:MM or 'now')",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only departures at or before this time (HH:MM)",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of departures",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only these bus lines",
+                        "name": "line",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only directions containing this text",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only journeys between wheelchair accessible stations",
+                        "name": "stepFree",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response language, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of departures",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/TimetableRow"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bus station not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/geojson/bus-lines": {
+            "get": {
+                "description": "Retrieve one LineString per bus line and direction, built from the ordered stops or from routing provider geometry",
+                "produces": [
+                    "application/geo+json"
+                ],
+                "tags": [
+                    "GeoJSON"
+                ],
+                "summary": "Get bus line shapes as GeoJSON",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only this bus line",
+                        "name": "line",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bounding box as minLon,minLat,maxLon,maxLat",
+                        "name": "bbox",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "stops",
+                            "routed"
+                        ],
+                        "type": "string",
+                        "default": "stops",
+                        "description": "Geometry source",
+                        "name": "geometry",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response language, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Bus line shapes",
+                        "schema": {
+                            "$ref": "#/definitions/FeatureCollection"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/geojson/bus-stations": {
+            "get": {
+                "description": "Retrieve all bus stations as a GeoJSON FeatureCollection of points with codes, lines and image",
+                "produces": [
+                    "application/geo+json"
+                ],
+                "tags": [
+                    "GeoJSON"
+                ],
+                "summary": "Get bus stations as GeoJSON",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only stations served by this bus line",
+                        "name": "line",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bounding box as minLon,minLat,maxLon,maxLat",
+                        "name": "bbox",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response language, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Bus stations",
+                        "schema": {
+                            "$ref": "#/definitions/FeatureCollection"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/graphql": {
+            "post": {
+                "description": "Query bus stations, station codes, lines, directions, departures and timetables in one request. Nested fields are batched per level. Queries whose estimated number of resolved objects exceeds the limit are rejected with the error code query_too_complex. Resolver errors carry the problem code in extensions.code.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GraphQL"
+                ],
+                "summary": "Run a GraphQL query",
+                "parameters": [
+                    {
+                        "description": "GraphQL request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/GraphQLRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response language, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Result with data and errors",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed request body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/images/stations/{id}": {
+            "get": {
+                "description": "Redirect to the stored copy of a bus station's photo, fetching it from the operator on first use. Stations whose photo is missing answer 404.",
+                "tags": [
+                    "Images"
+                ],
+                "summary": "Get the image of a bus station",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bus station id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "thumb",
+                            "small",
+                            "medium"
+                        ],
+                        "type": "string",
+                        "description": "Thumbnail size, omit for the original",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirect to the content-addressed image",
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "/api/images/{hash}"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bus station not found or without an image",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "The operator's server could not be reached",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/images/{hash}": {
+            "get": {
+                "description": "Serve a stored image or a JPEG thumbnail of it by content hash. The response never changes and may be cached indefinitely.",
+                "produces": [
+                    "image/jpeg",
+                    "image/png",
+                    "image/gif"
+                ],
+                "tags": [
+                    "Images"
+                ],
+                "summary": "Get an image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SHA-256 of the original image",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "thumb",
+                            "small",
+                            "medium"
+                        ],
+                        "type": "string",
+                        "description": "Thumbnail size, omit for the original",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Image",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash and size of the image"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Image not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/live": {
+            "get": {
+                "description": "Reports whether the process is up. Does not check dependencies.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "Process is alive",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/health/ready": {
+            "get": {
+                "description": "Checks every dependency (Postgres, migrations, Redis, routing provider, data freshness) and reports per-check status and latency.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "Service is ready (possibly degraded)",
+                        "schema": {
+                            "$ref": "#/definitions/HealthReport"
+                        }
+                    },
+                    "503": {
+                        "description": "A critical dependency is down",
+                        "schema": {
+                            "$ref": "#/definitions/HealthReport"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "Alert": {
+            "type": "object",
+            "properties": {
+                "activeFrom": {
+                    "type": "string"
+                },
+                "activeUntil": {
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Zapora Glavnega trga"
+                }
+            }
+        },
+        "AlertRequest": {
+            "type": "object",
+            "properties": {
+                "activeFrom": {
+                    "description": "ActiveFrom defaults to now; without ActiveUntil the alert stays active\nuntil it is deleted.",
+                    "type": "string"
+                },
+                "activeUntil": {
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "G6"
+                    ]
+                },
+                "title": {
+                    "description": "Title and Body are the Slovenian source text. Add other languages with\nPUT /api/admin/translations and entityType alert.",
+                    "type": "string",
+                    "example": "Zapora Glavnega trga"
+                }
+            }
+        },
+        "BusLine": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "enum": [
+                        "city",
+                        "suburban",
+                        "other"
+                    ]
+                },
+                "color": {
+                    "description": "Color is the #RRGGBB background of the line badge and TextColor the\ncolour legible on it.",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "distance": {
+                    "description": "Distance in kilometres from the requested origin to the closest station\nof the line, if an origin was given.",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "longName": {
+                    "description": "LongName names the termini, e.g. \"Avtobusna postaja - Tezno\".",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "sortOrder": {
+                    "description": "SortOrder ranks lines in lists; lines of equal rank keep their\nnatural order.",
+                    "type": "integer"
+                },
+                "textColor": {
+                    "type": "string"
+                }
+            }
+        },
+        "BusStation": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "Attributes describe accessibility and amenities; null fields are\nunknown.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/StationAttributes"
+                        }
+                    ]
+                },
+                "codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "distance": {
+                    "description": "Distance in kilometres from the requested origin, if one was given.",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imageUrl": {
+                    "description": "ImageURL is our copy of the station photo; append ?size= for a\nthumbnail. It is empty when the station has no usable photo.",
+                    "type": "string"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lineDetails": {
+                    "description": "LineDetails are the lines serving the station with their metadata, in\nline order.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/LineRef"
+                    }
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "lon": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "CacheKeyList": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "truncated": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "CachePurgeResult": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "integer"
+                },
+                "pattern": {
+                    "type": "string"
+                }
+            }
+        },
+        "DataVersion": {
+            "type": "object",
+            "properties": {
+                "generation": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "Feature": {
+            "type": "object",
+            "properties": {
+                "geometry": {
+                    "$ref": "#/definitions/Geometry"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "properties": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "type": {
+                    "type": "string",
+                    "example": "Feature"
+                }
+            }
+        },
+        "FeatureCollection": {
+            "type": "object",
+            "properties": {
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Feature"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "FeatureCollection"
+                }
+            }
+        },
+        "Geometry": {
+            "type": "object",
+            "properties": {
+                "coordinates": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "Point"
+                }
+            }
+        },
+        "GraphQLRequest": {
+            "type": "object",
+            "properties": {
+                "extensions": {
+                    "description": "Extensions are accepted for client compatibility and ignored.",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "HealthCheckResult": {
+            "type": "object",
+            "properties": {
+                "critical": {
+                    "type": "boolean"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "latencyMs": {
+                    "type": "number"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/github_com_perkzen_mbus_apps_bus-service_internal_health.Status"
+                }
+            }
+        },
+        "HealthReport": {
+            "type": "object",
+            "properties": {
+                "checkedAt": {
+                    "type": "string"
+                },
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/HealthCheckResult"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/github_com_perkzen_mbus_apps_bus-service_internal_health.Status"
+                }
+            }
+        },
+        "ImageSyncResult": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "description": "to be retried",
+                    "type": "integer"
+                },
+                "fetched": {
+                    "type": "integer"
+                },
+                "missing": {
+                    "description": "not an image or gone at the operator",
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "already stored",
+                    "type": "integer"
+                },
+                "stations": {
+                    "description": "stations with a source image",
+                    "type": "integer"
+                }
+            }
+        },
+        "ImageSyncStatus": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "force": {
+                    "type": "boolean"
+                },
+                "result": {
+                    "description": "counts so far once finished, also after an error",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ImageSyncResult"
+                        }
+                    ]
+                },
+                "running": {
+                    "type": "boolean"
+                },
+                "startedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "LineMetadataRequest": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "enum": [
+                        "city",
+                        "suburban",
+                        "other"
+                    ]
+                },
+                "color": {
+                    "type": "string"
+                },
+                "longName": {
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "sortOrder": {
+                    "type": "integer"
+                },
+                "textColor": {
+                    "type": "string"
+                }
+            }
+        },
+        "LineRef": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "enum": [
+                        "city",
+                        "suburban",
+                        "other"
+                    ]
+                },
+                "color": {
+                    "description": "Color is the #RRGGBB background of the line badge and TextColor the\ncolour legible on it.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "longName": {
+                    "description": "LongName names the termini, e.g. \"Avtobusna postaja - Tezno\".",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "sortOrder": {
+                    "description": "SortOrder ranks lines in lists; lines of equal rank keep their\nnatural order.",
+                    "type": "integer"
+                },
+                "textColor": {
+                    "type": "string"
+                }
+            }
+        },
+        "StationAttributes": {
+            "type": "object",
+            "properties": {
+                "bench": {
+                    "type": "boolean"
+                },
+                "realtimeDisplay": {
+                    "type": "boolean"
+                },
+                "shelter": {
+                    "type": "boolean"
+                },
+                "tactilePaving": {
+                    "type": "boolean"
+                },
+                "ticketMachine": {
+                    "type": "boolean"
+                },
+                "wheelchairAccessible": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "StationAttributesImport": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "imported": {
+                    "type": "integer"
+                }
+            }
+        },
+        "StationAttributesRequest": {
+            "type": "object",
+            "properties": {
+                "bench": {
+                    "type": "boolean"
+                },
+                "realtimeDisplay": {
+                    "type": "boolean"
+                },
+                "shelter": {
+                    "type": "boolean"
+                },
+                "tactilePaving": {
+                    "type": "boolean"
+                },
+                "ticketMachine": {
+                    "type": "boolean"
+                },
+                "wheelchairAccessible": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "StationCode": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lon": {
+                    "type": "number"
+                },
+                "platform": {
+                    "description": "Platform letters the stops of a station in code order.",
+                    "type": "string"
+                },
+                "stationId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "Stop": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "directions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "lon": {
+                    "type": "number"
+                },
+                "platform": {
+                    "description": "Platform letters the stops of a station in code order.",
+                    "type": "string"
+                },
+                "stationId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "TimetableRow": {
+            "type": "object",
+            "properties": {
+                "arriveAt": {
+                    "type": "string"
+                },
+                "arriveDayOffset": {
+                    "type": "integer"
+                },
+                "departureAt": {
+                    "description": "DepartureAt and ArriveAt are wall-clock times. The day offsets count the\nmidnights passed since the start of the requested service day, e.g. a\nnight bus arriving at 00:10 has an arriveDayOffset of 1.",
+                    "type": "string"
+                },
+                "departureDayOffset": {
+                    "type": "integer"
+                },
+                "direction": {
+                    "type": "string"
+                },
+                "distance": {
+                    "type": "number"
+                },
+                "duration": {
+                    "type": "string"
+                },
+                "estimated": {
+                    "description": "Estimated is set when distance and travel time come from the offline\nestimator instead of the routing provider.",
+                    "type": "boolean"
+                },
+                "fromStation": {
+                    "$ref": "#/definitions/TimetableRow.Station"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "line": {
+                    "type": "string"
+                },
+                "lineDetails": {
+                    "description": "LineDetails is the line with its metadata.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/LineRef"
+                        }
+                    ]
+                },
+                "stepFree": {
+                    "description": "StepFree is set when both stations are known to be wheelchair\naccessible.",
+                    "type": "boolean"
+                },
+                "toStation": {
+                    "$ref": "#/definitions/TimetableRow.Station"
+                }
+            }
+        },
+        "TimetableRow.Station": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "stop": {
+                    "description": "Stop is the platform to board at, or to get off at for the\ndestination.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/StationCode"
+                        }
+                    ]
+                }
+            }
+        },
+        "Translation": {
+            "type": "object",
+            "properties": {
+                "entityId": {
+                    "type": "integer"
+                },
+                "entityType": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "lang": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "TranslationRequest": {
+            "type": "object",
+            "properties": {
+                "entityId": {
+                    "type": "integer"
+                },
+                "entityType": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "lang": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_perkzen_mbus_apps_bus-service_internal_errs.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "in": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_perkzen_mbus_apps_bus-service_internal_health.Status": {
+            "type": "string",
+            "enum": [
+                "ok",
+                "degraded",
+                "down"
+            ],
+            "x-enum-varnames": [
+                "StatusOK",
+                "StatusDegraded",
+                "StatusDown"
+            ]
+        },
+        "internal_api.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_perkzen_mbus_apps_bus-service_internal_errs.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "statusCode": {
+                    "description": "LegacyStatusCode and LegacyMessage repeat status and detail under the\nkeys of the original error body, which v1 clients still read.",
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "AdminToken": {
+            "description": "Admin token as \"Bearer \u003cADMIN_TOKEN\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
+    }
+}`
+
+// SwaggerInfov1 holds exported Swagger Info so clients can modify it
+var SwaggerInfov1 = &swag.Spec{
+	Version:          "1.0",
+	Host:             "",
+	BasePath:         "",
+	Schemes:          []string{},
+	Title:            "mubs Bus Service API",
+	Description:      "This is the API documentation for the mubs Bus Service. The unversioned public endpoints are deprecated in favour of /api/v2 and answer with Deprecation and Sunset headers.",
+	InfoInstanceName: "v1",
+	SwaggerTemplate:  docTemplatev1,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
+}
+
+func init() {
+	swag.Register(SwaggerInfov1.InstanceName(), SwaggerInfov1)
+}
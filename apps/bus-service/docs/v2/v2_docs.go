@@ -0,0 +1,1428 @@
+// Package v2 Code generated by swaggo/swag. DO NOT EDIT
+package v2
+
+import "github.com/swaggo/swag"
+
+const docTemplatev2 = `{
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "contact": {},
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/api/v2/alerts": {
+            "get": {
+                "description": "Retrieve the service notices active now, newest first, with their title and body in the response language. Alerts for the whole network are included with every line filter.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "Get active alerts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only alerts affecting this bus line, e.g. G6",
+                        "name": "line",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response language, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Active alerts",
+                        "schema": {
+                            "$ref": "#/definitions/v2.Envelope-array_Alert"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/bus-lines": {
+            "get": {
+                "description": "Retrieve bus lines. Without a limit all lines are returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bus Lines"
+                ],
+                "summary": "Get bus lines",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Limit the number of results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination, ignored with a cursor",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from meta.nextCursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "name",
+                            "-name",
+                            "distance",
+                            "-distance",
+                            "stationCount",
+                            "-stationCount"
+                        ],
+                        "type": "string",
+                        "default": "name",
+                        "description": "Sort field, prefix with - for descending; name follows the sort order of the lines, then G1, G2, ..., P19",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the total count in meta.total",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude of the origin for distances",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude of the origin for distances",
+                        "name": "lon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by bus line name prefix",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only lines serving this bus station id",
+                        "name": "station",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response language, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of bus lines",
+                        "schema": {
+                            "$ref": "#/definitions/v2.Envelope-array_Line"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/bus-stations": {
+            "get": {
+                "description": "Retrieve a page of bus stations with optional filters",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bus Stations"
+                ],
+                "summary": "Get bus stations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit the number of results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination, ignored with a cursor",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from meta.nextCursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "name",
+                            "-name",
+                            "distance",
+                            "-distance",
+                            "lineCount",
+                            "-lineCount"
+                        ],
+                        "type": "string",
+                        "default": "name",
+                        "description": "Sort field, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the total count in meta.total",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude of the origin for distances",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude of the origin for distances",
+                        "name": "lon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by bus station name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by bus line",
+                        "name": "line",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "wheelchairAccessible",
+                                "shelter",
+                                "bench",
+                                "realtimeDisplay",
+                                "ticketMachine",
+                                "tactilePaving"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only stations known to have all of these attributes",
+                        "name": "has",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response language, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of bus stations",
+                        "schema": {
+                            "$ref": "#/definitions/v2.Envelope-array_Station"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/bus-stations/{id}": {
+            "get": {
+                "description": "Retrieve a bus station by its id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bus Stations"
+                ],
+                "summary": "Get bus station by id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bus station id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response language, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Bus station details",
+                        "schema": {
+                            "$ref": "#/definitions/v2.Envelope-Station"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid id",
+                        "schema": {
+                            "$ref": "#/definitions/api.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bus station not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/bus-stations/{id}/stops": {
+            "get": {
+                "description": "Retrieve the stops of a bus station, one per stop code, with their platform, position and the lines and directions departing from them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bus Stations"
+                ],
+                "summary": "Get the stops of a bus station",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bus station id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response language, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stops of the bus station in code order",
+                        "schema": {
+                            "$ref": "#/definitions/v2.Envelope-array_Stop"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid id",
+                        "schema": {
+                            "$ref": "#/definitions/api.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bus station not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/departures": {
+            "get": {
+                "description": "Retrieve departures between two bus stations on a service date. Times are RFC 3339 timestamps and durations ISO 8601.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Departures"
+                ],
+                "summary": "Get departures",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Departure station id",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Arrival station id",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Service date in YYYY-MM-DD format, defaults to the current service day, which runs until 03:00 the next morning",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only departures at or after this time (HH:MM or 'now')",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only departures at or before this time (HH:MM)",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of departures",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only these bus lines",
+                        "name": "line",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only directions containing this text",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only journeys between wheelchair accessible stations",
+                        "name": "stepFree",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response language, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Departures",
+                        "schema": {
+                            "$ref": "#/definitions/v2.Envelope-array_Departure"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/api.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bus station not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/subscriptions": {
+            "post": {
+                "description": "Save a trip and get reminded of its departures. Every departure from the origin to the destination that leaves inside the time window on one of the weekdays is announced leadMinutes ahead through the channel. The response contains a token that is required to read, confirm or delete the subscription; it is not shown again. Email subscriptions are sent a confirmation code and get no reminders until it is posted to /api/v2/subscriptions/{id}/confirm. Webhook targets must be public http or https URLs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Create a subscription",
+                "parameters": [
+                    {
+                        "description": "Subscription",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/SubscriptionRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response language, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created subscription with its token",
+                        "schema": {
+                            "$ref": "#/definitions/v2.Envelope-Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid subscription",
+                        "schema": {
+                            "$ref": "#/definitions/api.Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "Confirmation could not be sent",
+                        "schema": {
+                            "$ref": "#/definitions/api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/subscriptions/{id}": {
+            "get": {
+                "description": "Retrieve a subscription with the token returned on creation",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Get a subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token returned on creation",
+                        "name": "X-Subscription-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response language, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscription",
+                        "schema": {
+                            "$ref": "#/definitions/v2.Envelope-Subscription"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found or token does not match",
+                        "schema": {
+                            "$ref": "#/definitions/api.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Stop the reminders of a subscription and delete it",
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Delete a subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token returned on creation",
+                        "name": "X-Subscription-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Subscription deleted"
+                    },
+                    "404": {
+                        "description": "Subscription not found or token does not match",
+                        "schema": {
+                            "$ref": "#/definitions/api.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/subscriptions/{id}/confirm": {
+            "post": {
+                "description": "Opt in to the reminders of a subscription with the code sent to its target. Confirming an already confirmed subscription has no effect.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Confirm a subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token returned on creation",
+                        "name": "X-Subscription-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Confirmation code",
+                        "name": "confirmation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ConfirmationRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "sl",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response language, overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Confirmed subscription",
+                        "schema": {
+                            "$ref": "#/definitions/v2.Envelope-Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Code does not match",
+                        "schema": {
+                            "$ref": "#/definitions/api.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found or token does not match",
+                        "schema": {
+                            "$ref": "#/definitions/api.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "Alert": {
+            "type": "object",
+            "properties": {
+                "activeFrom": {
+                    "type": "string"
+                },
+                "activeUntil": {
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Zapora Glavnega trga"
+                }
+            }
+        },
+        "ConfirmationRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is the confirmation code sent to the target.",
+                    "type": "string",
+                    "example": "K7QX2M4D"
+                }
+            }
+        },
+        "Departure": {
+            "type": "object",
+            "properties": {
+                "arrivalTime": {
+                    "type": "string"
+                },
+                "departureTime": {
+                    "description": "DepartureTime and ArrivalTime are RFC 3339 timestamps with the agency's\nUTC offset, so trips past midnight need no day offsets.",
+                    "type": "string"
+                },
+                "direction": {
+                    "type": "string"
+                },
+                "distanceMeters": {
+                    "type": "integer"
+                },
+                "duration": {
+                    "description": "Duration is an ISO 8601 duration, e.g. \"PT25M\".",
+                    "type": "string",
+                    "example": "PT25M"
+                },
+                "estimated": {
+                    "description": "Estimated is set when distance and travel time come from the offline\nestimator instead of the routing provider.",
+                    "type": "boolean"
+                },
+                "from": {
+                    "$ref": "#/definitions/StationRef"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "line": {
+                    "type": "string"
+                },
+                "lineDetails": {
+                    "description": "LineDetails is the line with its metadata.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/LineRef"
+                        }
+                    ]
+                },
+                "stepFree": {
+                    "description": "StepFree is set when both stations are known to be wheelchair\naccessible.",
+                    "type": "boolean"
+                },
+                "to": {
+                    "$ref": "#/definitions/StationRef"
+                }
+            }
+        },
+        "Line": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "enum": [
+                        "city",
+                        "suburban",
+                        "other"
+                    ]
+                },
+                "color": {
+                    "description": "Color is the #RRGGBB background of the line badge and TextColor the\ncolour legible on it.",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "distanceMeters": {
+                    "description": "DistanceMeters from the requested origin to the closest station of the\nline, if an origin was given.",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "longName": {
+                    "description": "LongName names the termini, e.g. \"Avtobusna postaja - Tezno\".",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "sortOrder": {
+                    "description": "SortOrder ranks lines in lists; lines of equal rank keep their\nnatural order.",
+                    "type": "integer"
+                },
+                "textColor": {
+                    "type": "string"
+                }
+            }
+        },
+        "LineRef": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "enum": [
+                        "city",
+                        "suburban",
+                        "other"
+                    ]
+                },
+                "color": {
+                    "description": "Color is the #RRGGBB background of the line badge and TextColor the\ncolour legible on it.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "longName": {
+                    "description": "LongName names the termini, e.g. \"Avtobusna postaja - Tezno\".",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "sortOrder": {
+                    "description": "SortOrder ranks lines in lists; lines of equal rank keep their\nnatural order.",
+                    "type": "integer"
+                },
+                "textColor": {
+                    "type": "string"
+                }
+            }
+        },
+        "Links": {
+            "type": "object",
+            "properties": {
+                "next": {
+                    "type": "string"
+                },
+                "self": {
+                    "type": "string"
+                }
+            }
+        },
+        "Meta": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Count is the number of items in data, for list responses.",
+                    "type": "integer"
+                },
+                "date": {
+                    "description": "Date is the service date of timetable responses.",
+                    "type": "string"
+                },
+                "generatedAt": {
+                    "description": "GeneratedAt is when the response was built, in the agency timezone.",
+                    "type": "string"
+                },
+                "lang": {
+                    "description": "Lang is the language of messages and localized fields.",
+                    "type": "string"
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "schedule": {
+                    "description": "Schedule is the timetable that applies on Date.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Schedule"
+                        }
+                    ]
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Total is the number of matching items across all pages, if requested.",
+                    "type": "integer"
+                }
+            }
+        },
+        "Schedule": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string",
+                    "example": "Weekday"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "weekday",
+                        "saturday",
+                        "sunday"
+                    ]
+                }
+            }
+        },
+        "Station": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "Attributes describe accessibility and amenities; null fields are\nunknown.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/StationAttributes"
+                        }
+                    ]
+                },
+                "codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "distanceMeters": {
+                    "description": "DistanceMeters from the requested origin, if one was given.",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imageUrl": {
+                    "description": "ImageURL is the station photo; append ?size=thumb, small or medium\nfor a JPEG thumbnail.",
+                    "type": "string"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lineDetails": {
+                    "description": "LineDetails are the lines serving the station with their metadata, in\nline order.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/LineRef"
+                    }
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "lon": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "thumbnailUrl": {
+                    "type": "string"
+                }
+            }
+        },
+        "StationAttributes": {
+            "type": "object",
+            "properties": {
+                "bench": {
+                    "type": "boolean"
+                },
+                "realtimeDisplay": {
+                    "type": "boolean"
+                },
+                "shelter": {
+                    "type": "boolean"
+                },
+                "tactilePaving": {
+                    "type": "boolean"
+                },
+                "ticketMachine": {
+                    "type": "boolean"
+                },
+                "wheelchairAccessible": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "StationRef": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "stop": {
+                    "description": "Stop is the platform to board at, or to get off at for the\ndestination.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/StopRef"
+                        }
+                    ]
+                }
+            }
+        },
+        "Stop": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "directions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lines": {
+                    "description": "Lines and Directions departing from the stop.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "lon": {
+                    "type": "number"
+                },
+                "platform": {
+                    "type": "string"
+                }
+            }
+        },
+        "StopRef": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lon": {
+                    "type": "number"
+                },
+                "platform": {
+                    "type": "string"
+                }
+            }
+        },
+        "Subscription": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string",
+                    "enum": [
+                        "webhook",
+                        "email"
+                    ]
+                },
+                "confirmed": {
+                    "description": "Confirmed is false until the target opted in with the code sent to\nit. Unconfirmed subscriptions get no reminders.",
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "from": {
+                    "$ref": "#/definitions/StationRef"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lang": {
+                    "type": "string",
+                    "enum": [
+                        "sl",
+                        "en"
+                    ]
+                },
+                "leadMinutes": {
+                    "description": "LeadMinutes is how long before a departure the reminder is sent.",
+                    "type": "integer",
+                    "example": 5
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/mbus"
+                },
+                "to": {
+                    "$ref": "#/definitions/StationRef"
+                },
+                "token": {
+                    "description": "Token is only returned on creation. Send it in the X-Subscription-Token\nheader to read or delete the subscription.",
+                    "type": "string"
+                },
+                "weekdays": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "mon",
+                            "tue",
+                            "wed",
+                            "thu",
+                            "fri",
+                            "sat",
+                            "sun"
+                        ]
+                    }
+                },
+                "windowEnd": {
+                    "type": "string",
+                    "example": "08:30"
+                },
+                "windowStart": {
+                    "description": "WindowStart and WindowEnd bound the departures that are reminded of,\nas HH:MM wall-clock times.",
+                    "type": "string",
+                    "example": "07:00"
+                }
+            }
+        },
+        "SubscriptionRequest": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string",
+                    "enum": [
+                        "webhook",
+                        "email"
+                    ]
+                },
+                "fromStationId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "lang": {
+                    "description": "Lang of the reminders, defaults to the language of the request.",
+                    "type": "string",
+                    "enum": [
+                        "sl",
+                        "en"
+                    ]
+                },
+                "leadMinutes": {
+                    "description": "LeadMinutes defaults to 5.",
+                    "type": "integer",
+                    "example": 5
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "To work"
+                },
+                "target": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/mbus"
+                },
+                "toStationId": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "weekdays": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "mon",
+                            "tue",
+                            "wed",
+                            "thu",
+                            "fri",
+                            "sat",
+                            "sun"
+                        ]
+                    }
+                },
+                "windowEnd": {
+                    "type": "string",
+                    "example": "08:30"
+                },
+                "windowStart": {
+                    "type": "string",
+                    "example": "07:00"
+                }
+            }
+        },
+        "api.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/errs.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "statusCode": {
+                    "description": "LegacyStatusCode and LegacyMessage repeat status and detail under the\nkeys of the original error body, which v1 clients still read.",
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "errs.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "in": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "v2.Envelope-Station": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/Station"
+                },
+                "links": {
+                    "$ref": "#/definitions/Links"
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
+                }
+            }
+        },
+        "v2.Envelope-Subscription": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/Subscription"
+                },
+                "links": {
+                    "$ref": "#/definitions/Links"
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
+                }
+            }
+        },
+        "v2.Envelope-array_Alert": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Alert"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/Links"
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
+                }
+            }
+        },
+        "v2.Envelope-array_Departure": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Departure"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/Links"
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
+                }
+            }
+        },
+        "v2.Envelope-array_Line": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Line"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/Links"
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
+                }
+            }
+        },
+        "v2.Envelope-array_Station": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Station"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/Links"
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
+                }
+            }
+        },
+        "v2.Envelope-array_Stop": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Stop"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/Links"
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
+                }
+            }
+        }
+    }
+}`
+
+// SwaggerInfov2 holds exported Swagger Info so clients can modify it
+var SwaggerInfov2 = &swag.Spec{
+	Version:          "2.0",
+	Host:             "",
+	BasePath:         "",
+	Schemes:          []string{},
+	Title:            "mubs Bus Service API",
+	Description:      "Version 2 of the mubs Bus Service API. Responses are wrapped in data/meta/links envelopes, durations are ISO 8601 and timestamps RFC 3339 with the agency's UTC offset.",
+	InfoInstanceName: "v2",
+	SwaggerTemplate:  docTemplatev2,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
+}
+
+func init() {
+	swag.Register(SwaggerInfov2.InstanceName(), SwaggerInfov2)
+}
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/cacheadmin"
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/images"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"log/slog"
+	"net/http"
+)
+
+type AdminHandler struct {
+	cacheAdmin             *cacheadmin.Service
+	dataVersionStore       store.DataVersionStore
+	translationStore       store.TranslationStore
+	busStationStore        store.BusStationStore
+	busLineStore           store.BusLineStore
+	stationAttributesStore store.StationAttributesStore
+	alertStore             store.AlertStore
+	imageService           *images.Service
+	logger                 *slog.Logger
+}
+
+func NewAdminHandler(
+	cacheAdmin *cacheadmin.Service,
+	dataVersionStore store.DataVersionStore,
+	translationStore store.TranslationStore,
+	busStationStore store.BusStationStore,
+	busLineStore store.BusLineStore,
+	stationAttributesStore store.StationAttributesStore,
+	alertStore store.AlertStore,
+	imageService *images.Service,
+	logger *slog.Logger,
+) *AdminHandler {
+	return &AdminHandler{
+		cacheAdmin:             cacheAdmin,
+		dataVersionStore:       dataVersionStore,
+		translationStore:       translationStore,
+		busStationStore:        busStationStore,
+		busLineStore:           busLineStore,
+		stationAttributesStore: stationAttributesStore,
+		alertStore:             alertStore,
+		imageService:           imageService,
+		logger:                 logger.With(slog.String("handler", "AdminHandler")),
+	}
+}
+
+// GetCacheKeys godoc
+// @Summary List cache keys
+// @Description List cached keys matching a glob pattern. Patterns are always scoped to the service prefix.
+// @Tags Admin
+// @Produce json
+// @Security AdminToken
+// @Param pattern query string false "Glob pattern, e.g. g3:timetable:*" default(*)
+// @Success 200 {object} cacheadmin.KeyList "Matching keys"
+// @Router /api/admin/cache/keys [get]
+func (h *AdminHandler) GetCacheKeys(w http.ResponseWriter, r *http.Request) error {
+	pattern := r.URL.Query().Get("pattern")
+
+	keys, err := h.cacheAdmin.ListKeys(r.Context(), pattern)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(w, http.StatusOK, keys)
+}
+
+// PurgeCacheKeys godoc
+// @Summary Purge cache keys
+// @Description Delete cached keys matching a glob pattern
+// @Tags Admin
+// @Produce json
+// @Security AdminToken
+// @Param pattern query string true "Glob pattern, e.g. g3:timetable:*"
+// @Success 200 {object} cacheadmin.PurgeResult "Number of deleted keys"
+// @Router /api/admin/cache/keys [delete]
+func (h *AdminHandler) PurgeCacheKeys(w http.ResponseWriter, r *http.Request) error {
+	b := Bind(r)
+	pattern := b.RequiredQueryStr("pattern")
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	res, err := h.cacheAdmin.Purge(r.Context(), pattern)
+	if err != nil {
+		return err
+	}
+
+	h.logger.Info("purged cache keys", slog.String("pattern", res.Pattern), slog.Int("deleted", res.Deleted))
+	return WriteJSON(w, http.StatusOK, res)
+}
+
+// InvalidateCache godoc
+// @Summary Invalidate cached timetables
+// @Description Drop cached timetables touching a bus station or any station of a bus line
+// @Tags Admin
+// @Produce json
+// @Security AdminToken
+// @Param station query int false "Bus station id"
+// @Param line query string false "Bus line name"
+// @Success 200 {object} cacheadmin.PurgeResult "Number of deleted keys"
+// @Router /api/admin/cache/invalidate [post]
+func (h *AdminHandler) InvalidateCache(w http.ResponseWriter, r *http.Request) error {
+	b := Bind(r)
+	stationID := b.QueryInt("station", 0)
+	line := b.QueryStr("line", "")
+	if stationID == 0 && line == "" && b.Valid("station") {
+		b.Violation(errs.InQuery, "station", errs.FieldRequired, "Either 'station' or 'line' is required")
+	}
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	var (
+		res *cacheadmin.PurgeResult
+		err error
+	)
+
+	switch {
+	case stationID != 0:
+		res, err = h.cacheAdmin.InvalidateStation(r.Context(), stationID)
+	default:
+		res, err = h.cacheAdmin.InvalidateLine(r.Context(), line)
+	}
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(w, http.StatusOK, res)
+}
+
+// GetDataVersion godoc
+// @Summary Get data version
+// @Description Current data generation used to namespace cache keys
+// @Tags Admin
+// @Produce json
+// @Security AdminToken
+// @Success 200 {object} store.DataVersion "Current data version"
+// @Router /api/admin/data-version [get]
+func (h *AdminHandler) GetDataVersion(w http.ResponseWriter, r *http.Request) error {
+	version, err := h.dataVersionStore.GetDataVersion(r.Context())
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(w, http.StatusOK, version)
+}
+
+// BumpDataVersion godoc
+// @Summary Bump data version
+// @Description Start a new data generation, invalidating every versioned cache entry
+// @Tags Admin
+// @Produce json
+// @Security AdminToken
+// @Param reason query string false "Reason recorded with the new generation" default(admin)
+// @Success 200 {object} store.DataVersion "New data version"
+// @Router /api/admin/data-version [post]
+func (h *AdminHandler) BumpDataVersion(w http.ResponseWriter, r *http.Request) error {
+	reason := r.URL.Query().Get("reason")
+	if reason == "" {
+		reason = "admin"
+	}
+
+	version, err := h.cacheAdmin.BumpDataVersion(r.Context(), reason)
+	if err != nil {
+		return err
+	}
+
+	h.logger.Info("bumped data version", slog.Int64("generation", version.Generation), slog.String("reason", reason))
+	return WriteJSON(w, http.StatusOK, version)
+}
+
+type translationRequest store.Translation // @name TranslationRequest
+
+func (t *translationRequest) Validate(b *Binder) {
+	if !store.IsTranslatable(t.EntityType, t.Field) {
+		b.Violation(errs.InBody, "field", errs.FieldInvalidValue, "%s is not translatable for %s", t.Field, t.EntityType)
+	}
+	if t.EntityID <= 0 {
+		b.Violation(errs.InBody, "entityId", errs.FieldOutOfRange, "%s must be at least %d", "entityId", 1)
+	}
+	if _, ok := i18n.Parse(t.Lang); !ok || len(t.Lang) != 2 {
+		b.Violation(errs.InBody, "lang", errs.FieldInvalidValue, "%s is not a supported language", t.Lang)
+	}
+}
+
+// PutTranslation godoc
+// @Summary Set a translation
+// @Description Create or replace the localized value of a translatable field, e.g. a bus line description. The 'sl' value is the source text other languages fall back to.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param translation body translationRequest true "Translation"
+// @Success 200 {object} store.Translation "Stored translation"
+// @Failure 400 {object} Problem "Invalid translation"
+// @Router /api/admin/translations [put]
+func (h *AdminHandler) PutTranslation(w http.ResponseWriter, r *http.Request) error {
+	b := Bind(r)
+	var req translationRequest
+	b.DecodeJSON(&req)
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	if err := h.translationStore.UpsertTranslation(r.Context(), store.Translation(req)); err != nil {
+		return err
+	}
+	if _, err := h.cacheAdmin.BumpDataVersion(r.Context(), "translations"); err != nil {
+		return err
+	}
+
+	return WriteJSON(w, http.StatusOK, store.Translation(req))
+}
+
+// DeleteTranslation godoc
+// @Summary Delete a translation
+// @Description Remove the localized value of a field in one language
+// @Tags Admin
+// @Security AdminToken
+// @Param entityType query string true "Entity type" Enums(bus_line, alert)
+// @Param entityId query int true "Entity id"
+// @Param field query string true "Field name, e.g. description or title"
+// @Param lang query string true "Language" Enums(sl, en)
+// @Success 204 "Deleted"
+// @Failure 404 {object} Problem "No such translation"
+// @Router /api/admin/translations [delete]
+func (h *AdminHandler) DeleteTranslation(w http.ResponseWriter, r *http.Request) error {
+	b := Bind(r)
+	t := store.Translation{
+		EntityType: b.RequiredQueryStr("entityType"),
+		EntityID:   b.RequiredQueryInt("entityId"),
+		Field:      b.RequiredQueryStr("field"),
+		Lang:       b.RequiredQueryStr("lang"),
+	}
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	deleted, err := h.translationStore.DeleteTranslation(r.Context(), t)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errs.NotFoundError("Translation does not exist")
+	}
+	if _, err := h.cacheAdmin.BumpDataVersion(r.Context(), "translations"); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+// Alert text limits.
+const (
+	maxAlertTitle = 200
+	maxAlertBody  = 2000
+)
+
+type alertRequest struct {
+	// Title and Body are the Slovenian source text. Add other languages with
+	// PUT /api/admin/translations and entityType alert.
+	Title string   `json:"title" example:"Zapora Glavnega trga"`
+	Body  string   `json:"body"`
+	Lines []string `json:"lines" example:"G6"`
+	// ActiveFrom defaults to now; without ActiveUntil the alert stays active
+	// until it is deleted.
+	ActiveFrom  *time.Time `json:"activeFrom,omitempty"`
+	ActiveUntil *time.Time `json:"activeUntil,omitempty"`
+} // @name AlertRequest
+
+func (a *alertRequest) Validate(b *Binder) {
+	a.Title = strings.TrimSpace(a.Title)
+	if a.Title == "" {
+		b.Violation(errs.InBody, "title", errs.FieldRequired, "%s is required", "title")
+	} else if utf8.RuneCountInString(a.Title) > maxAlertTitle {
+		b.Violation(errs.InBody, "title", errs.FieldOutOfRange, "%s must be at most %d characters", "title", maxAlertTitle)
+	}
+	a.Body = strings.TrimSpace(a.Body)
+	if utf8.RuneCountInString(a.Body) > maxAlertBody {
+		b.Violation(errs.InBody, "body", errs.FieldOutOfRange, "%s must be at most %d characters", "body", maxAlertBody)
+	}
+
+	lines := make([]string, 0, len(a.Lines))
+	for _, l := range a.Lines {
+		if l = strings.ToUpper(strings.TrimSpace(l)); l != "" && !slices.Contains(lines, l) {
+			lines = append(lines, l)
+		}
+	}
+	a.Lines = lines
+
+	if a.ActiveFrom == nil {
+		now := utils.Now()
+		a.ActiveFrom = &now
+	}
+	if a.ActiveUntil != nil && !a.ActiveUntil.After(*a.ActiveFrom) {
+		b.Violation(errs.InBody, "activeUntil", errs.FieldInvalidValue, "%s must be after %s", "activeUntil", "activeFrom")
+	}
+}
+
+// CreateAlert godoc
+// @Summary Create an alert
+// @Description Publish a service notice, e.g. a detour or a stop closure, for some lines or, without lines, for the whole network. Title and body are the Slovenian source text; translate them with PUT /api/admin/translations.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param alert body alertRequest true "Alert"
+// @Success 201 {object} store.Alert "Created alert"
+// @Failure 400 {object} Problem "Invalid alert"
+// @Router /api/admin/alerts [post]
+func (h *AdminHandler) CreateAlert(w http.ResponseWriter, r *http.Request) error {
+	b := Bind(r)
+	var req alertRequest
+	b.DecodeJSON(&req)
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	alert := store.Alert{
+		Title:       req.Title,
+		Body:        req.Body,
+		Lines:       req.Lines,
+		ActiveFrom:  utils.InLocation(*req.ActiveFrom),
+		ActiveUntil: req.ActiveUntil,
+	}
+	if alert.ActiveUntil != nil {
+		until := utils.InLocation(*alert.ActiveUntil)
+		alert.ActiveUntil = &until
+	}
+	if err := h.alertStore.CreateAlert(r.Context(), &alert); err != nil {
+		return err
+	}
+
+	return WriteJSON(w, http.StatusCreated, alert)
+}
+
+// DeleteAlert godoc
+// @Summary Delete an alert
+// @Description Remove an alert and its translations
+// @Tags Admin
+// @Security AdminToken
+// @Param id path int true "Alert id"
+// @Success 204 "Deleted"
+// @Failure 404 {object} Problem "No such alert"
+// @Router /api/admin/alerts/{id} [delete]
+func (h *AdminHandler) DeleteAlert(w http.ResponseWriter, r *http.Request) error {
+	b := Bind(r)
+	id := b.PathInt("id")
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	deleted, err := h.alertStore.DeleteAlert(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errs.NotFoundError("Alert does not exist")
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
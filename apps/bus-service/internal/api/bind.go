@@ -0,0 +1,362 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"github.com/perkzen/mbus/apps/bus-service/internal/pagination"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+// maxBodyBytes bounds request bodies read by DecodeJSON.
+const maxBodyBytes = 1 << 20
+
+// Binder reads query, path and body parameters of a request. Instead of
+// falling back to defaults on bad input it records a violation per field, so a
+// client learns about every problem of a request at once. Handlers read all
+// parameters first and then return Err.
+type Binder struct {
+	r          *http.Request
+	violations []errs.FieldError
+}
+
+func Bind(r *http.Request) *Binder {
+	return &Binder{r: r}
+}
+
+// Err returns a validation problem listing all violations, or nil.
+func (b *Binder) Err() error {
+	if len(b.violations) == 0 {
+		return nil
+	}
+	return errs.ValidationError(b.violations)
+}
+
+// Violation records an invalid field. format is the English message and the
+// translation catalog key.
+func (b *Binder) Violation(in, field, code, format string, args ...any) {
+	b.violations = append(b.violations, errs.NewFieldError(in, field, code, format, args...))
+}
+
+// Valid reports whether no violation has been recorded for field.
+func (b *Binder) Valid(field string) bool {
+	return !slices.ContainsFunc(b.violations, func(v errs.FieldError) bool {
+		return v.Field == field
+	})
+}
+
+func (b *Binder) query(name string) string {
+	return strings.TrimSpace(b.r.URL.Query().Get(name))
+}
+
+// QueryStr returns a query parameter, or def when it is missing.
+func (b *Binder) QueryStr(name, def string) string {
+	if v := b.query(name); v != "" {
+		return v
+	}
+	return def
+}
+
+// RequiredQueryStr returns a query parameter that must be present.
+func (b *Binder) RequiredQueryStr(name string) string {
+	v := b.query(name)
+	if v == "" {
+		b.Violation(errs.InQuery, name, errs.FieldRequired, "%s is required", name)
+	}
+	return v
+}
+
+// QueryStrs returns all values of a repeatable parameter. Comma-separated
+// values are split as well, so line=G1&line=G6 equals line=G1,G6.
+func (b *Binder) QueryStrs(name string) []string {
+	var values []string
+	for _, v := range b.r.URL.Query()[name] {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+	}
+	return values
+}
+
+// QueryEnum returns a query parameter that must be one of allowed, or def
+// when it is missing.
+func (b *Binder) QueryEnum(name, def string, allowed ...string) string {
+	v := b.query(name)
+	if v == "" {
+		return def
+	}
+	if !slices.Contains(allowed, v) {
+		b.Violation(errs.InQuery, name, errs.FieldInvalidValue, "%s must be one of: %s", name, strings.Join(allowed, ", "))
+		return def
+	}
+	return v
+}
+
+// QueryEnums returns all values of a repeatable parameter like QueryStrs,
+// each of which must be one of allowed.
+func (b *Binder) QueryEnums(name string, allowed ...string) []string {
+	values := b.QueryStrs(name)
+	for _, v := range values {
+		if !slices.Contains(allowed, v) {
+			b.Violation(errs.InQuery, name, errs.FieldInvalidValue, "%s must be one of: %s", name, strings.Join(allowed, ", "))
+			return nil
+		}
+	}
+	return values
+}
+
+// QueryInt returns an integer query parameter, or def when it is missing.
+func (b *Binder) QueryInt(name string, def int) int {
+	v := b.query(name)
+	if v == "" {
+		return def
+	}
+	return b.parseInt(errs.InQuery, name, v, def)
+}
+
+// RequiredQueryInt returns an integer query parameter that must be present.
+func (b *Binder) RequiredQueryInt(name string) int {
+	v := b.query(name)
+	if v == "" {
+		b.Violation(errs.InQuery, name, errs.FieldRequired, "%s is required", name)
+		return 0
+	}
+	return b.parseInt(errs.InQuery, name, v, 0)
+}
+
+// PathInt returns an integer URL parameter.
+func (b *Binder) PathInt(name string) int {
+	v := chi.URLParam(b.r, name)
+	if v == "" {
+		b.Violation(errs.InPath, name, errs.FieldRequired, "%s is required", name)
+		return 0
+	}
+	return b.parseInt(errs.InPath, name, v, 0)
+}
+
+func (b *Binder) parseInt(in, name, v string, def int) int {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		b.Violation(in, name, errs.FieldInvalidFormat, "%s must be an integer", name)
+		return def
+	}
+	return n
+}
+
+// QueryBool returns a boolean query parameter, or def when it is missing.
+func (b *Binder) QueryBool(name string, def bool) bool {
+	v := b.query(name)
+	if v == "" {
+		return def
+	}
+	parsed, err := strconv.ParseBool(v)
+	if err != nil {
+		b.Violation(errs.InQuery, name, errs.FieldInvalidFormat, "%s must be true or false", name)
+		return def
+	}
+	return parsed
+}
+
+// QueryFloat returns a float query parameter within [min, max], or def when it
+// is missing.
+func (b *Binder) QueryFloat(name string, def, min, max float64) float64 {
+	v := b.query(name)
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		b.Violation(errs.InQuery, name, errs.FieldInvalidFormat, "%s must be a number", name)
+		return def
+	}
+	if f < min || f > max {
+		b.Violation(errs.InQuery, name, errs.FieldOutOfRange, "%s must be between %g and %g", name, min, max)
+		return def
+	}
+	return f
+}
+
+// QueryDate returns a date in YYYY-MM-DD format, or def when it is missing.
+func (b *Binder) QueryDate(name, def string) string {
+	v := b.query(name)
+	if v == "" {
+		return def
+	}
+	if !utils.ValidateDate(v) {
+		b.Violation(errs.InQuery, name, errs.FieldInvalidFormat, "%s must be a date in YYYY-MM-DD format", name)
+		return def
+	}
+	return v
+}
+
+// QueryServiceTime parses a time of day given as HH:MM (hours of 24 and above
+// allowed) or "now". A missing parameter yields nil.
+func (b *Binder) QueryServiceTime(name string) *utils.ServiceTime {
+	v := b.query(name)
+	if v == "" {
+		return nil
+	}
+
+	if v == "now" {
+		t := utils.NowServiceTime()
+		return &t
+	}
+
+	t, err := utils.ServiceTimeFromClock(v)
+	if err != nil {
+		// Past-midnight times such as 24:30 are taken as they are.
+		if t, err = utils.ParseServiceTime(v); err != nil {
+			b.Violation(errs.InQuery, name, errs.FieldInvalidFormat, "%s must be a time in HH:MM format or 'now'", name)
+			return nil
+		}
+	}
+	return &t
+}
+
+// QueryBBox parses a bounding box given as minLon,minLat,maxLon,maxLat. A
+// missing parameter yields nil.
+func (b *Binder) QueryBBox(name string) *store.BBox {
+	v := b.query(name)
+	if v == "" {
+		return nil
+	}
+
+	parts := strings.Split(v, ",")
+	if len(parts) != 4 {
+		b.Violation(errs.InQuery, name, errs.FieldInvalidFormat, "%s must be minLon,minLat,maxLon,maxLat", name)
+		return nil
+	}
+
+	var c [4]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			b.Violation(errs.InQuery, name, errs.FieldInvalidFormat, "%s must be minLon,minLat,maxLon,maxLat", name)
+			return nil
+		}
+		c[i] = f
+	}
+
+	bbox := &store.BBox{MinLon: c[0], MinLat: c[1], MaxLon: c[2], MaxLat: c[3]}
+	if bbox.MinLon > bbox.MaxLon || bbox.MinLat > bbox.MaxLat {
+		b.Violation(errs.InQuery, name, errs.FieldInvalidValue, "%s minimum corner must not exceed the maximum", name)
+		return nil
+	}
+	return bbox
+}
+
+// QueryOrigin parses the lat and lon parameters. Missing parameters yield nil;
+// one without the other is a violation.
+func (b *Binder) QueryOrigin() *store.Point {
+	hasLat, hasLon := b.query("lat") != "", b.query("lon") != ""
+	if !hasLat && !hasLon {
+		return nil
+	}
+	if hasLat != hasLon {
+		missing := "lat"
+		if hasLat {
+			missing = "lon"
+		}
+		b.Violation(errs.InQuery, missing, errs.FieldRequired, "lat and lon must be given together")
+		return nil
+	}
+
+	lat := b.QueryFloat("lat", 0, -90, 90)
+	lon := b.QueryFloat("lon", 0, -180, 180)
+	if !b.Valid("lat") || !b.Valid("lon") {
+		return nil
+	}
+	return &store.Point{Lat: lat, Lon: lon}
+}
+
+// QueryPage parses limit, offset, cursor, sort and total. A cursor takes
+// precedence over offset.
+func (b *Binder) QueryPage(defaultLimit int, sorts ...string) pagination.Request {
+	page := pagination.Request{
+		Limit:     b.QueryInt("limit", defaultLimit),
+		Offset:    b.QueryInt("offset", 0),
+		WithTotal: b.QueryBool("total", false),
+	}
+	b.Min("limit", page.Limit, 0)
+	b.Min("offset", page.Offset, 0)
+
+	sort, err := pagination.ParseSort(b.query("sort"), sorts...)
+	if err != nil {
+		b.Violation(errs.InQuery, "sort", errs.FieldInvalidValue, "%s must be one of: %s", "sort", strings.Join(sorts, ", "))
+	}
+	page.Sort = sort
+
+	if raw := b.query("cursor"); raw != "" {
+		cursor, err := pagination.DecodeCursor(raw)
+		if err != nil {
+			b.Violation(errs.InQuery, "cursor", errs.FieldInvalidFormat, "cursor is not a valid page cursor")
+		} else {
+			page.Cursor = cursor
+		}
+	}
+
+	if b.Valid("sort") && b.Valid("cursor") {
+		if err := page.Validate(); err != nil {
+			b.Violation(errs.InQuery, "cursor", errs.FieldInvalidValue, "cursor does not match the requested sort")
+		}
+	}
+
+	return page
+}
+
+// RequireOrigin records a violation when sorting by distance without lat and
+// lon.
+func (b *Binder) RequireOrigin(sort pagination.Sort, origin *store.Point) {
+	if sort.Field == pagination.SortDistance && origin == nil && b.Valid("lat") && b.Valid("lon") {
+		b.Violation(errs.InQuery, "lat", errs.FieldRequired, "sorting by distance requires lat and lon")
+	}
+}
+
+// Min records a violation when a valid query parameter is below min.
+func (b *Binder) Min(name string, value, min int) {
+	if b.Valid(name) && value < min {
+		b.Violation(errs.InQuery, name, errs.FieldOutOfRange, "%s must be at least %d", name, min)
+	}
+}
+
+// Validatable is implemented by request bodies that check their own fields.
+type Validatable interface {
+	Validate(b *Binder)
+}
+
+// DecodeJSON decodes the request body into dst, rejecting unknown fields. When
+// dst implements Validatable its rules are applied as well.
+func (b *Binder) DecodeJSON(dst any) {
+	dec := json.NewDecoder(io.LimitReader(b.r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.Is(err, io.EOF):
+			b.Violation(errs.InBody, "", errs.FieldRequired, "request body is required")
+		case errors.As(err, &typeErr):
+			b.Violation(errs.InBody, typeErr.Field, errs.FieldInvalidFormat, "%s must be a %s", typeErr.Field, typeErr.Type.String())
+		case strings.HasPrefix(err.Error(), "json: unknown field "):
+			field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+			b.Violation(errs.InBody, field, errs.FieldUnknown, "%s is not a known field", field)
+		default:
+			b.Violation(errs.InBody, "", errs.FieldInvalidFormat, "request body must be valid JSON")
+		}
+		return
+	}
+
+	if v, ok := dst.(Validatable); ok {
+		v.Validate(b)
+	}
+}
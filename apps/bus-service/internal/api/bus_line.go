@@ -1,11 +1,23 @@
 package api
 
 import (
+	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/pagination"
 	"github.com/perkzen/mbus/apps/bus-service/internal/store"
 	"log/slog"
 	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
 )
 
+// maxLineText bounds the long name and operator of a line.
+const maxLineText = 255
+
+var colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
 type BusLineHandler struct {
 	busLineStore store.BusLineStore
 	logger       *slog.Logger
@@ -20,17 +32,127 @@ func NewBusLineHandler(busLineStore store.BusLineStore, logger *slog.Logger) *Bu
 
 // GetBusLines godoc
 // @Summary Get bus lines
-// @Description Retrieve a list of bus lines
+// @Description Retrieve a list of bus lines. Without a limit all lines are returned.
 // @Tags Bus Lines
 // @Accept json
 // @Produce json
+// @Param limit query int false "Limit the number of results" default(0)
+// @Param offset query int false "Offset for pagination, ignored with a cursor" default(0)
+// @Param cursor query string false "Opaque cursor from the X-Next-Cursor header or Link header of the previous page"
+// @Param sort query string false "Sort field, prefix with - for descending; name follows the sort order of the lines, then G1, G2, ..., P19" Enums(name, -name, distance, -distance, stationCount, -stationCount) default(name)
+// @Param total query bool false "Include the total count in the X-Total-Count header"
+// @Param lat query number false "Latitude of the origin for distances"
+// @Param lon query number false "Longitude of the origin for distances"
+// @Param name query string false "Filter by bus line name prefix"
+// @Param station query int false "Only lines serving this bus station id"
+// @Param lang query string false "Response language, overrides Accept-Language" Enums(sl, en)
 // @Success 200 {array} store.BusLine "List of bus lines"
+// @Header 200 {string} Link "Next page, rel=next"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Header 200 {integer} X-Total-Count "Total number of matching lines"
+// @Failure 400 {object} Problem "Invalid parameters"
 // @Router /api/bus-lines [get]
 func (h *BusLineHandler) GetBusLines(w http.ResponseWriter, r *http.Request) error {
-	lines, err := h.busLineStore.ListBusLines()
+	b := Bind(r)
+	page := b.QueryPage(0, pagination.SortName, pagination.SortDistance, pagination.SortStationCount)
+	origin := b.QueryOrigin()
+	name := b.QueryStr("name", "")
+	stationID := b.QueryInt("station", 0)
+	b.RequireOrigin(page.Sort, origin)
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	lines, err := h.busLineStore.ListBusLines(r.Context(), &store.BusLineFilterOptions{
+		Name:      name,
+		StationID: stationID,
+		Origin:    origin,
+		Lang:      i18n.FromContext(r.Context()),
+	}, page)
+	if err != nil {
+		return err
+	}
+
+	return WritePage(w, r, lines)
+}
+
+type lineMetadataRequest store.LineOverrides // @name LineMetadataRequest
+
+func (m *lineMetadataRequest) Validate(b *Binder) {
+	if m.Category != nil && !slices.Contains(store.LineCategories, *m.Category) {
+		b.Violation(errs.InBody, "category", errs.FieldInvalidValue, "%s must be one of: %s", "category", strings.Join(store.LineCategories, ", "))
+	}
+	for _, c := range []struct {
+		field string
+		value *string
+	}{{"color", m.Color}, {"textColor", m.TextColor}} {
+		if c.value == nil {
+			continue
+		}
+		if !colorPattern.MatchString(*c.value) {
+			b.Violation(errs.InBody, c.field, errs.FieldInvalidFormat, "%s must be a colour in #RRGGBB format", c.field)
+			continue
+		}
+		*c.value = strings.ToUpper(*c.value)
+	}
+	for _, t := range []struct {
+		field string
+		value *string
+	}{{"longName", m.LongName}, {"operator", m.Operator}} {
+		if t.value != nil && utf8.RuneCountInString(*t.value) > maxLineText {
+			b.Violation(errs.InBody, t.field, errs.FieldOutOfRange, "%s must be at most %d characters", t.field, maxLineText)
+		}
+	}
+	if m.SortOrder != nil && *m.SortOrder < 0 {
+		b.Violation(errs.InBody, "sortOrder", errs.FieldOutOfRange, "%s must be at least %d", "sortOrder", 0)
+	}
+}
+
+// PutLineMetadata godoc
+// @Summary Override bus line metadata
+// @Description Replace the metadata an admin set for a bus line. Omitted or null fields fall back to the values derived by the seeder, which never overwrites these.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "Bus line id"
+// @Param metadata body lineMetadataRequest true "Metadata"
+// @Success 200 {object} store.BusLine "Bus line with its resulting metadata"
+// @Failure 400 {object} Problem "Invalid metadata"
+// @Failure 404 {object} Problem "Bus line not found"
+// @Router /api/admin/bus-lines/{id}/metadata [put]
+func (h *AdminHandler) PutLineMetadata(w http.ResponseWriter, r *http.Request) error {
+	b := Bind(r)
+	lineID := b.PathInt("id")
+	var req lineMetadataRequest
+	b.DecodeJSON(&req)
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	lang := i18n.FromContext(r.Context())
+	lines, err := h.busLineStore.FindBusLinesByIDs(r.Context(), []int{lineID}, lang)
 	if err != nil {
 		return err
 	}
+	if len(lines) == 0 {
+		return errs.BusLineNotFoundError(lineID)
+	}
+
+	if err := h.busLineStore.UpsertLineOverrides(r.Context(), lineID, store.LineOverrides(req)); err != nil {
+		return err
+	}
+	if _, err := h.cacheAdmin.BumpDataVersion(r.Context(), "line metadata"); err != nil {
+		return err
+	}
+
+	lines, err = h.busLineStore.FindBusLinesByIDs(r.Context(), []int{lineID}, lang)
+	if err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		return errs.BusLineNotFoundError(lineID)
+	}
 
-	return WriteJSON(w, http.StatusOK, lines)
+	return WriteJSON(w, http.StatusOK, lines[0])
 }
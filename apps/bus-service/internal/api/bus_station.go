@@ -1,12 +1,11 @@
 package api
 
 import (
-	"github.com/go-chi/chi/v5"
 	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"github.com/perkzen/mbus/apps/bus-service/internal/pagination"
 	"github.com/perkzen/mbus/apps/bus-service/internal/store"
 	"log/slog"
 	"net/http"
-	"strconv"
 )
 
 type BusStationHandler struct {
@@ -28,27 +27,45 @@ func NewBusStationHandler(busStationStore store.BusStationStore, logger *slog.Lo
 // @Accept json
 // @Produce json
 // @Param limit query int false "Limit the number of results" default(10)
-// @Param offset query int false "Offset for pagination" default(0)
+// @Param offset query int false "Offset for pagination, ignored with a cursor" default(0)
+// @Param cursor query string false "Opaque cursor from the X-Next-Cursor header or Link header of the previous page"
+// @Param sort query string false "Sort field, prefix with - for descending" Enums(name, -name, distance, -distance, lineCount, -lineCount) default(name)
+// @Param total query bool false "Include the total count in the X-Total-Count header"
+// @Param lat query number false "Latitude of the origin for distances"
+// @Param lon query number false "Longitude of the origin for distances"
 // @Param name query string false "Filter by bus station name"
 // @Param line query string false "Filter by bus line"
+// @Param has query []string false "Only stations known to have all of these attributes" collectionFormat(multi) Enums(wheelchairAccessible, shelter, bench, realtimeDisplay, ticketMachine, tactilePaving)
+// @Param lang query string false "Response language, overrides Accept-Language" Enums(sl, en)
 // @Success 200 {array} store.BusStation "List of bus stations"
+// @Header 200 {string} Link "Next page, rel=next"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Header 200 {integer} X-Total-Count "Total number of matching stations"
+// @Failure 400 {object} Problem "Invalid parameters"
 // @Router /api/bus-stations [get]
 func (h *BusStationHandler) GetBusStations(w http.ResponseWriter, r *http.Request) error {
-	limit := QueryInt(r, "limit", 10)
-	offset := QueryInt(r, "offset", 0)
-
-	name, _ := QueryStr(r, "name")
-	line, _ := QueryStr(r, "line")
+	b := Bind(r)
+	page := b.QueryPage(10, pagination.SortName, pagination.SortDistance, pagination.SortLineCount)
+	origin := b.QueryOrigin()
+	name := b.QueryStr("name", "")
+	line := b.QueryStr("line", "")
+	attributes := b.QueryEnums("has", store.StationAttributeNames...)
+	b.RequireOrigin(page.Sort, origin)
+	if err := b.Err(); err != nil {
+		return err
+	}
 
-	busStations, err := h.busStationStore.ListBusStations(limit, offset, &store.BusStationFilterOptions{
-		Name: name,
-		Line: line,
-	})
+	busStations, err := h.busStationStore.ListBusStations(r.Context(), &store.BusStationFilterOptions{
+		Name:       name,
+		Line:       line,
+		Origin:     origin,
+		Attributes: attributes,
+	}, page)
 	if err != nil {
 		return err
 	}
 
-	return WriteJSON(w, http.StatusOK, busStations)
+	return WritePage(w, r, busStations)
 }
 
 // GetBusStationByID godoc
@@ -58,23 +75,60 @@ func (h *BusStationHandler) GetBusStations(w http.ResponseWriter, r *http.Reques
 // @Accept json
 // @Produce json
 // @Param id path int true "Bus station id"
+// @Param lang query string false "Response language, overrides Accept-Language" Enums(sl, en)
 // @Success 200 {object} store.BusStation "Bus station details"
+// @Failure 400 {object} Problem "Invalid id"
+// @Failure 404 {object} Problem "Bus station not found"
 // @Router /api/bus-stations/{id} [get]
 func (h *BusStationHandler) GetBusStationByID(w http.ResponseWriter, r *http.Request) error {
-	id := chi.URLParam(r, "id")
-	if id == "" {
-		return errs.BadRequestError("Bus station id is required")
+	b := Bind(r)
+	stationID := b.PathInt("id")
+	if err := b.Err(); err != nil {
+		return err
 	}
 
-	stationID, err := strconv.Atoi(id)
+	busStation, err := h.busStationStore.FindBusStationByID(r.Context(), stationID)
 	if err != nil {
-		return errs.BadRequestError("Invalid bus station id format")
+		return err
+	}
+	if busStation == nil {
+		return errs.BusStationNotFoundError(stationID)
+	}
+
+	return WriteJSON(w, http.StatusOK, busStation)
+}
+
+// GetBusStationStops godoc
+// @Summary Get the stops of a bus station
+// @Description Retrieve the stops of a bus station, one per stop code, with their platform, position and the lines and directions departing from them
+// @Tags Bus Stations
+// @Accept json
+// @Produce json
+// @Param id path int true "Bus station id"
+// @Param lang query string false "Response language, overrides Accept-Language" Enums(sl, en)
+// @Success 200 {array} store.Stop "Stops of the bus station in code order"
+// @Failure 400 {object} Problem "Invalid id"
+// @Failure 404 {object} Problem "Bus station not found"
+// @Router /api/bus-stations/{id}/stops [get]
+func (h *BusStationHandler) GetBusStationStops(w http.ResponseWriter, r *http.Request) error {
+	b := Bind(r)
+	stationID := b.PathInt("id")
+	if err := b.Err(); err != nil {
+		return err
 	}
 
-	busStation, err := h.busStationStore.FindBusStationByID(stationID)
+	busStation, err := h.busStationStore.FindBusStationByID(r.Context(), stationID)
 	if err != nil {
+		return err
+	}
+	if busStation == nil {
 		return errs.BusStationNotFoundError(stationID)
 	}
 
-	return WriteJSON(w, http.StatusOK, busStation)
+	stops, err := h.busStationStore.FindStopsByStationID(r.Context(), stationID)
+	if err != nil {
+		return err
+	}
+
+	return WriteJSON(w, http.StatusOK, stops)
 }
@@ -1,7 +1,6 @@
 package api
 
 import (
-	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
 	"github.com/perkzen/mbus/apps/bus-service/internal/service/departure"
 	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
 	"log/slog"
@@ -28,22 +27,59 @@ func NewDepartureHandler(departureService *departure.Service, logger *slog.Logge
 // @Produce json
 // @Param from query int true "Departure station code"
 // @Param to query int true "Arrival station code"
-// @Param date query string false "Date in YYYY-MM-DD format"
+// @Param date query string false "Date in YYYY-MM-DD format, defaults to the current service day, which runs until 03:00 the next morning"
+// @Param after query string false "Only departures at or after this time (HH:MM or 'now')"
+// @Param before query string false "Only departures at or before this time (HH:MM)"
+// @Param limit query int false "Maximum number of departures"
+// @Param line query []string false "Only these bus lines" collectionFormat(multi)
+// @Param direction query string false "Only directions containing this text"
+// @Param stepFree query bool false "Only journeys between wheelchair accessible stations"
+// @Param lang query string false "Response language, overrides Accept-Language" Enums(sl, en)
 // @Success 200 {array} departure.TimetableRow "List of departures"
+// @Failure 400 {object} Problem "Invalid parameters"
+// @Failure 404 {object} Problem "Bus station not found"
 // @Router /api/departures [get]
 func (h *DepartureHandler) GetDepartures(w http.ResponseWriter, r *http.Request) error {
-	fromID := QueryInt(r, "from", -1)
-	toID := QueryInt(r, "to", -1)
-	if fromID == -1 || toID == -1 {
-		return errs.BadRequestError("Both 'from' and 'to' parameters are required")
+	b := Bind(r)
+	fromID := b.RequiredQueryInt("from")
+	toID := b.RequiredQueryInt("to")
+	date := b.QueryDate("date", utils.ServiceDate())
+	after := b.QueryServiceTime("after")
+	before := b.QueryServiceTime("before")
+	limit := b.QueryInt("limit", 0)
+	b.Min("limit", limit, 0)
+	filter := departure.Filter{
+		Lines:     b.QueryStrs("line"),
+		Direction: b.QueryStr("direction", ""),
+		StepFree:  b.QueryBool("stepFree", false),
+		After:     after,
+		Before:    before,
+		Limit:     limit,
+	}
+	if err := b.Err(); err != nil {
+		return err
 	}
 
-	date := QueryDateStr(r, "date", utils.Today())
-
-	data, err := h.departureService.GenerateTimetable(fromID, toID, date)
+	data, err := h.departureService.GenerateTimetable(r.Context(), fromID, toID, date, filter)
 	if err != nil {
 		return err
 	}
 
 	return WriteJSON(w, http.StatusOK, data)
 }
+
+// TimetableKeyParts returns the inputs of a departures response that are not
+// part of its URL: the service date when it defaults to the current one, and
+// the current minute when a window is relative to "now".
+func TimetableKeyParts(r *http.Request) []string {
+	q := r.URL.Query()
+
+	var parts []string
+	if q.Get("date") == "" {
+		parts = append(parts, utils.ServiceDate())
+	}
+	if q.Get("after") == "now" || q.Get("before") == "now" {
+		parts = append(parts, utils.NowServiceTime().String())
+	}
+	return parts
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/geojson"
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/geo"
+)
+
+type GeoHandler struct {
+	geoService *geo.Service
+	logger     *slog.Logger
+}
+
+func NewGeoHandler(geoService *geo.Service, logger *slog.Logger) *GeoHandler {
+	return &GeoHandler{
+		geoService: geoService,
+		logger:     logger.With(slog.String("handler", "GeoHandler")),
+	}
+}
+
+// GetStations godoc
+// @Summary Get bus stations as GeoJSON
+// @Description Retrieve all bus stations as a GeoJSON FeatureCollection of points with codes, lines and image
+// @Tags GeoJSON
+// @Produce application/geo+json
+// @Param line query string false "Only stations served by this bus line"
+// @Param bbox query string false "Bounding box as minLon,minLat,maxLon,maxLat"
+// @Param lang query string false "Response language, overrides Accept-Language" Enums(sl, en)
+// @Success 200 {object} geojson.FeatureCollection "Bus stations"
+// @Failure 400 {object} Problem "Invalid parameters"
+// @Router /api/geojson/bus-stations [get]
+func (h *GeoHandler) GetStations(w http.ResponseWriter, r *http.Request) error {
+	b := Bind(r)
+	filter := geo.StationFilter{
+		Line: b.QueryStr("line", ""),
+		BBox: b.QueryBBox("bbox"),
+	}
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	fc, err := h.geoService.Stations(r.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return WriteGeoJSON(w, http.StatusOK, fc)
+}
+
+// GetLines godoc
+// @Summary Get bus line shapes as GeoJSON
+// @Description Retrieve one LineString per bus line and direction, built from the ordered stops or from routing provider geometry
+// @Tags GeoJSON
+// @Produce application/geo+json
+// @Param line query string false "Only this bus line"
+// @Param bbox query string false "Bounding box as minLon,minLat,maxLon,maxLat"
+// @Param geometry query string false "Geometry source" Enums(stops, routed) default(stops)
+// @Param lang query string false "Response language, overrides Accept-Language" Enums(sl, en)
+// @Success 200 {object} geojson.FeatureCollection "Bus line shapes"
+// @Failure 400 {object} Problem "Invalid parameters"
+// @Router /api/geojson/bus-lines [get]
+func (h *GeoHandler) GetLines(w http.ResponseWriter, r *http.Request) error {
+	b := Bind(r)
+	filter := geo.LineFilter{
+		Line:     b.QueryStr("line", ""),
+		BBox:     b.QueryBBox("bbox"),
+		Geometry: b.QueryEnum("geometry", geo.GeometryStops, geo.GeometryStops, geo.GeometryRouted),
+	}
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	fc, err := h.geoService.LineShapes(r.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return WriteGeoJSON(w, http.StatusOK, fc)
+}
+
+func WriteGeoJSON(w http.ResponseWriter, status int, fc *geojson.FeatureCollection) error {
+	w.Header().Set("Content-Type", geojson.MediaType)
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(fc)
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"github.com/perkzen/mbus/apps/bus-service/internal/graph"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type GraphQLHandler struct {
+	server *graph.Server
+	logger *slog.Logger
+}
+
+func NewGraphQLHandler(server *graph.Server, logger *slog.Logger) *GraphQLHandler {
+	return &GraphQLHandler{
+		server: server,
+		logger: logger.With(slog.String("handler", "GraphQLHandler")),
+	}
+}
+
+type graphQLRequest graph.Request // @name GraphQLRequest
+
+func (q *graphQLRequest) Validate(b *Binder) {
+	if strings.TrimSpace(q.Query) == "" {
+		b.Violation(errs.InBody, "query", errs.FieldRequired, "%s is required", "query")
+	}
+}
+
+// Query godoc
+// @Summary Run a GraphQL query
+// @Description Query bus stations, station codes, lines, directions, departures and timetables in one request. Nested fields are batched per level. Queries whose estimated number of resolved objects exceeds the limit are rejected with the error code query_too_complex. Resolver errors carry the problem code in extensions.code.
+// @Tags GraphQL
+// @Accept json
+// @Produce json
+// @Param request body graphQLRequest true "GraphQL request"
+// @Param lang query string false "Response language, overrides Accept-Language" Enums(sl, en)
+// @Success 200 {object} map[string]interface{} "Result with data and errors"
+// @Failure 400 {object} Problem "Malformed request body"
+// @Router /api/graphql [post]
+func (h *GraphQLHandler) Query(w http.ResponseWriter, r *http.Request) error {
+	b := Bind(r)
+	var req graphQLRequest
+	b.DecodeJSON(&req)
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	return WriteJSON(w, http.StatusOK, h.server.Exec(r.Context(), graph.Request(req)))
+}
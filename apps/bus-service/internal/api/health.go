@@ -0,0 +1,52 @@
+package api
+
+import (
+	"github.com/perkzen/mbus/apps/bus-service/internal/health"
+	"log/slog"
+	"net/http"
+)
+
+type HealthHandler struct {
+	checker *health.Checker
+	logger  *slog.Logger
+}
+
+func NewHealthHandler(checker *health.Checker, logger *slog.Logger) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+		logger:  logger.With(slog.String("handler", "HealthHandler")),
+	}
+}
+
+// Live godoc
+// @Summary Liveness probe
+// @Description Reports whether the process is up. Does not check dependencies.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]string "Process is alive"
+// @Router /health/live [get]
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) error {
+	return WriteJSON(w, http.StatusOK, map[string]string{
+		"status": string(health.StatusOK),
+	})
+}
+
+// Ready godoc
+// @Summary Readiness probe
+// @Description Checks every dependency (Postgres, migrations, Redis, routing provider, data freshness) and reports per-check status and latency.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Report "Service is ready (possibly degraded)"
+// @Failure 503 {object} health.Report "A critical dependency is down"
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) error {
+	report := h.checker.Run(r.Context())
+
+	status := http.StatusOK
+	if report.Status == health.StatusDown {
+		status = http.StatusServiceUnavailable
+		h.logger.Warn("readiness check failed", slog.Any("checks", report.Checks))
+	}
+
+	return WriteJSON(w, status, report)
+}
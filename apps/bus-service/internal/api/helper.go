@@ -1,10 +1,14 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"github.com/go-chi/chi/v5"
 	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
-	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+	"github.com/perkzen/mbus/apps/bus-service/internal/pagination"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -14,53 +18,86 @@ type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
 
 func MakeHandlerFunc(fn HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if err := fn(w, r); err != nil {
+		route := r.URL.Path
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+
+		ctx, span := telemetry.StartSpan(r.Context(), "handler "+r.Method+" "+route,
+			attribute.String("http.route", route),
+		)
+		r = r.WithContext(ctx)
+
+		err := fn(w, r)
+		if err != nil {
+			// The client went away; there is nobody left to answer.
+			if errors.Is(err, context.Canceled) && r.Context().Err() != nil {
+				slog.Debug("request cancelled by client", slog.String("route", route))
+				telemetry.EndSpan(span, err)
+				return
+			}
+
 			var apiErr errs.APIError
 			ok := errors.As(err, &apiErr)
-			if !ok {
+			switch {
+			case ok:
+			case errors.Is(err, context.DeadlineExceeded):
+				apiErr = errs.GatewayTimeoutError()
+			default:
 				slog.Error(err.Error())
 				apiErr = errs.InternalServerError()
 			}
-			_ = WriteJSON(w, apiErr.StatusCode, apiErr)
-
+			span.SetAttributes(
+				attribute.Int("http.status_code", apiErr.StatusCode),
+				attribute.String("error.code", apiErr.Code),
+			)
+			_ = apiErr.Write(w, r)
 		}
+
+		telemetry.EndSpan(span, err)
 	}
 }
 
+// NotFound answers unknown routes with a problem document.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	_ = errs.NotFoundError("Not Found").Write(w, r)
+}
+
+// MethodNotAllowed answers known routes called with the wrong method.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	_ = errs.MethodNotAllowedError(r.Method).Write(w, r)
+}
+
 func WriteJSON(w http.ResponseWriter, status int, data any) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	return json.NewEncoder(w).Encode(data)
 }
 
-func QueryInt(r *http.Request, key string, defaultValue int) int {
-	val := r.URL.Query().Get(key)
-	if parsed, err := strconv.Atoi(val); err == nil {
-		return parsed
-	}
-	return defaultValue
-}
-
-func QueryStr(r *http.Request, key string) (string, error) {
-	val := r.URL.Query().Get(key)
-	if val == "" {
-		return "", errs.BadRequestError("missing query parameter: " + key)
-	}
-
-	return val, nil
-
+// NextPageURL returns the request URL pointing at the page after cursor.
+func NextPageURL(r *http.Request, cursor string) string {
+	next := *r.URL
+	q := next.Query()
+	q.Set("cursor", cursor)
+	q.Del("offset")
+	next.RawQuery = q.Encode()
+	return next.RequestURI()
 }
 
-func QueryDateStr(r *http.Request, key string, defaultValue string) string {
-	date, _ := QueryStr(r, key)
-
-	if date != "" && !utils.ValidateDate(date) {
-		return defaultValue
+// WritePage writes the items of a page as a plain JSON array and reports the
+// pagination state in headers: a Link header with rel="next", X-Next-Cursor
+// and, when requested, X-Total-Count.
+func WritePage[T any](w http.ResponseWriter, r *http.Request, page *pagination.Page[T]) error {
+	if page.NextCursor != "" {
+		w.Header().Add("Link", `<`+NextPageURL(r, page.NextCursor)+`>; rel="next"`)
+		w.Header().Set("X-Next-Cursor", page.NextCursor)
 	}
-
-	if date == "" {
-		return defaultValue
+	if page.Total != nil {
+		w.Header().Set("X-Total-Count", strconv.Itoa(*page.Total))
 	}
 
-	return date
+	return WriteJSON(w, http.StatusOK, page.Items)
 }
+
+// Problem documents error responses in the API docs.
+type Problem = errs.APIError
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/images"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+)
+
+// Images under a content hash never change; the station redirect changes
+// when the operator replaces a photo.
+const (
+	immutableCacheControl = "public, max-age=31536000, immutable"
+	redirectCacheControl  = "public, max-age=3600"
+)
+
+type ImageHandler struct {
+	imageService *images.Service
+	logger       *slog.Logger
+}
+
+func NewImageHandler(imageService *images.Service, logger *slog.Logger) *ImageHandler {
+	return &ImageHandler{
+		imageService: imageService,
+		logger:       logger.With(slog.String("handler", "ImageHandler")),
+	}
+}
+
+// GetStationImage godoc
+// @Summary Get the image of a bus station
+// @Description Redirect to the stored copy of a bus station's photo, fetching it from the operator on first use. Stations whose photo is missing answer 404.
+// @Tags Images
+// @Param id path int true "Bus station id"
+// @Param size query string false "Thumbnail size, omit for the original" Enums(thumb, small, medium)
+// @Success 302 "Redirect to the content-addressed image"
+// @Header 302 {string} Location "/api/images/{hash}"
+// @Failure 400 {object} Problem "Invalid parameters"
+// @Failure 404 {object} Problem "Bus station not found or without an image"
+// @Failure 502 {object} Problem "The operator's server could not be reached"
+// @Router /api/images/stations/{id} [get]
+func (h *ImageHandler) GetStationImage(w http.ResponseWriter, r *http.Request) error {
+	b := Bind(r)
+	id := b.PathInt("id")
+	size := b.QueryEnum("size", "", images.Sizes...)
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	hash, err := h.imageService.StationImageHash(r.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Cache-Control", redirectCacheControl)
+	http.Redirect(w, r, store.ImagePath(hash, size), http.StatusFound)
+	return nil
+}
+
+// GetImage godoc
+// @Summary Get an image
+// @Description Serve a stored image or a JPEG thumbnail of it by content hash. The response never changes and may be cached indefinitely.
+// @Tags Images
+// @Produce jpeg
+// @Produce png
+// @Produce gif
+// @Param hash path string true "SHA-256 of the original image"
+// @Param size query string false "Thumbnail size, omit for the original" Enums(thumb, small, medium)
+// @Success 200 {file} binary "Image"
+// @Header 200 {string} ETag "Hash and size of the image"
+// @Failure 400 {object} Problem "Invalid parameters"
+// @Failure 404 {object} Problem "Image not found"
+// @Router /api/images/{hash} [get]
+func (h *ImageHandler) GetImage(w http.ResponseWriter, r *http.Request) error {
+	b := Bind(r)
+	hash := chi.URLParam(r, "hash")
+	if !images.ValidHash(hash) {
+		b.Violation(errs.InPath, "hash", errs.FieldInvalidFormat, "%s must be a SHA-256 hex digest", "hash")
+	}
+	size := b.QueryEnum("size", "", images.Sizes...)
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	img, err := h.imageService.Image(r.Context(), hash, size)
+	if err != nil {
+		return err
+	}
+
+	etag := `"` + img.Hash
+	if img.Size != "" {
+		etag += "-" + img.Size
+	}
+	etag += `"`
+
+	header := w.Header()
+	header.Set("Content-Type", img.ContentType)
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", immutableCacheControl)
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(img.Data))
+	return nil
+}
+
+// SyncImages godoc
+// @Summary Copy station images
+// @Description Start fetching the photos of all stations that are not stored yet, or of all stations with force, from the operator. The sync runs in the background; poll GET /api/admin/images/sync for its outcome. Stations whose photo is missing get no image URL.
+// @Tags Admin
+// @Produce json
+// @Security AdminToken
+// @Param force query bool false "Fetch stored images again" default(false)
+// @Success 202 {object} images.SyncStatus "Sync started"
+// @Failure 400 {object} Problem "Invalid parameters"
+// @Failure 409 {object} Problem "A sync is already running"
+// @Router /api/admin/images/sync [post]
+func (h *AdminHandler) SyncImages(w http.ResponseWriter, r *http.Request) error {
+	b := Bind(r)
+	force := b.QueryBool("force", false)
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	status, started := h.imageService.StartSync(r.Context(), force, h.imagesSynced)
+	if !started {
+		return errs.ConflictError("An image sync is already running")
+	}
+	return WriteJSON(w, http.StatusAccepted, status)
+}
+
+// GetImageSync godoc
+// @Summary Get the image sync status
+// @Description Report whether an image sync is running and the outcome of the last one started on this instance
+// @Tags Admin
+// @Produce json
+// @Security AdminToken
+// @Success 200 {object} images.SyncStatus "Running or last sync"
+// @Failure 404 {object} Problem "No sync has run yet"
+// @Router /api/admin/images/sync [get]
+func (h *AdminHandler) GetImageSync(w http.ResponseWriter, r *http.Request) error {
+	status, ok := h.imageService.SyncStatus()
+	if !ok {
+		return errs.NotFoundError("No image sync has run yet")
+	}
+	return WriteJSON(w, http.StatusOK, status)
+}
+
+// imagesSynced bumps the data version once a sync stored or dropped any image,
+// since station responses carry the image URLs. A sync that failed partway
+// still bumps it for the stations it got through.
+func (h *AdminHandler) imagesSynced(ctx context.Context, result *images.SyncResult, err error) {
+	if result != nil && result.Fetched+result.Missing > 0 {
+		if _, bumpErr := h.cacheAdmin.BumpDataVersion(ctx, "station images"); bumpErr != nil {
+			h.logger.Error("failed to bump data version after image sync", slog.Any("error", bumpErr))
+		}
+	}
+
+	if err != nil {
+		h.logger.Error("image sync failed", slog.Any("error", err))
+	}
+	if result != nil {
+		h.logger.Info("synced station images",
+			slog.Int("fetched", result.Fetched),
+			slog.Int("missing", result.Missing),
+			slog.Int("failed", result.Failed))
+	}
+}
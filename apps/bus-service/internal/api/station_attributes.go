@@ -0,0 +1,239 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+)
+
+// maxImportViolations bounds the violations reported for one CSV import.
+const maxImportViolations = 50
+
+type stationAttributesRequest store.StationAttributes // @name StationAttributesRequest
+
+type StationAttributesImport struct {
+	Imported   int      `json:"imported"`
+	Attributes []string `json:"attributes"`
+} // @name StationAttributesImport
+
+// PutStationAttributes godoc
+// @Summary Set station attributes
+// @Description Replace the accessibility and amenity attributes of a bus station. Omitted or null attributes become unknown.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "Bus station id"
+// @Param attributes body stationAttributesRequest true "Attributes"
+// @Success 200 {object} store.StationAttributes "Stored attributes"
+// @Failure 400 {object} Problem "Invalid attributes"
+// @Failure 404 {object} Problem "Bus station not found"
+// @Router /api/admin/bus-stations/{id}/attributes [put]
+func (h *AdminHandler) PutStationAttributes(w http.ResponseWriter, r *http.Request) error {
+	b := Bind(r)
+	stationID := b.PathInt("id")
+	var req stationAttributesRequest
+	b.DecodeJSON(&req)
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	station, err := h.busStationStore.FindBusStationByID(r.Context(), stationID)
+	if err != nil {
+		return err
+	}
+	if station == nil {
+		return errs.BusStationNotFoundError(stationID)
+	}
+
+	if err := h.stationAttributesStore.UpsertStationAttributes(r.Context(), stationID, store.StationAttributes(req)); err != nil {
+		return err
+	}
+	if _, err := h.cacheAdmin.BumpDataVersion(r.Context(), "station attributes"); err != nil {
+		return err
+	}
+
+	return WriteJSON(w, http.StatusOK, store.StationAttributes(req))
+}
+
+// ImportStationAttributes godoc
+// @Summary Import station attributes
+// @Description Set station attributes from CSV. The header row names a stationId column and one or more attribute columns (wheelchairAccessible, shelter, bench, realtimeDisplay, ticketMachine, tactilePaving; snake_case works as well). Values are yes/no, true/false or 1/0; an empty value marks the attribute as unknown. Attributes without a column keep their values. Nothing is imported if any row is invalid.
+// @Tags Admin
+// @Accept text/csv
+// @Produce json
+// @Security AdminToken
+// @Param csv body string true "CSV with a header row"
+// @Success 200 {object} StationAttributesImport "Number of imported stations"
+// @Failure 400 {object} Problem "Invalid CSV"
+// @Router /api/admin/bus-stations/attributes/import [post]
+func (h *AdminHandler) ImportStationAttributes(w http.ResponseWriter, r *http.Request) error {
+	b := Bind(r)
+	names, rows, lines := parseStationAttributesCSV(b, io.LimitReader(r.Body, maxBodyBytes))
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	ids := make([]int, len(rows))
+	for i, row := range rows {
+		ids[i] = row.StationID
+	}
+	stations, err := h.busStationStore.FindBusStationsByIDs(r.Context(), ids)
+	if err != nil {
+		return err
+	}
+	known := make(map[int]bool, len(stations))
+	for _, s := range stations {
+		known[s.ID] = true
+	}
+	for _, row := range rows {
+		if !known[row.StationID] && len(b.violations) < maxImportViolations {
+			b.Violation(errs.InBody, "stationId", errs.FieldInvalidValue, "Line %d: bus station %d does not exist", lines[row.StationID], row.StationID)
+		}
+	}
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	if err := h.stationAttributesStore.ImportStationAttributes(r.Context(), names, rows); err != nil {
+		return err
+	}
+	if _, err := h.cacheAdmin.BumpDataVersion(r.Context(), "station attributes"); err != nil {
+		return err
+	}
+
+	h.logger.Info("imported station attributes", slog.Int("stations", len(rows)), slog.Any("attributes", names))
+	return WriteJSON(w, http.StatusOK, StationAttributesImport{Imported: len(rows), Attributes: names})
+}
+
+// parseStationAttributesCSV reads the attribute columns named in the header
+// and one row per station, recording a violation per invalid cell. lines maps
+// station ids to the line they were given on.
+func parseStationAttributesCSV(b *Binder, body io.Reader) (names []string, rows []store.StationAttributesRow, lines map[int]int) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		b.Violation(errs.InBody, "", errs.FieldRequired, "request body is required")
+		return nil, nil, nil
+	}
+	if err != nil {
+		b.Violation(errs.InBody, "", errs.FieldInvalidFormat, "request body must be valid CSV")
+		return nil, nil, nil
+	}
+
+	type column struct {
+		index int
+		name  string
+	}
+	idColumn := -1
+	var columns []column
+	for i, h := range header {
+		name := attributeName(h)
+		switch {
+		case name == "stationId":
+			idColumn = i
+		case slices.Contains(store.StationAttributeNames, name):
+			if slices.Contains(names, name) {
+				b.Violation(errs.InBody, h, errs.FieldInvalidValue, "%s is given more than once", h)
+				continue
+			}
+			names = append(names, name)
+			columns = append(columns, column{index: i, name: name})
+		default:
+			b.Violation(errs.InBody, h, errs.FieldUnknown, "%s is not a known field", h)
+		}
+	}
+	if idColumn < 0 {
+		b.Violation(errs.InBody, "stationId", errs.FieldRequired, "%s is required", "stationId")
+	}
+	if len(names) == 0 {
+		b.Violation(errs.InBody, "", errs.FieldRequired, "at least one attribute column is required")
+	}
+	if len(b.violations) > 0 {
+		return nil, nil, nil
+	}
+
+	lines = map[int]int{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			line := 0
+			if errors.As(err, &parseErr) {
+				line = parseErr.Line
+			}
+			b.Violation(errs.InBody, "", errs.FieldInvalidFormat, "Line %d: row must be valid CSV with %d fields", line, len(header))
+			break
+		}
+		line, _ := reader.FieldPos(0)
+
+		row := store.StationAttributesRow{}
+		id, err := strconv.Atoi(strings.TrimSpace(record[idColumn]))
+		if err != nil || id <= 0 {
+			b.Violation(errs.InBody, "stationId", errs.FieldInvalidFormat, "Line %d: %s must be a positive integer", line, "stationId")
+		} else if first, ok := lines[id]; ok {
+			b.Violation(errs.InBody, "stationId", errs.FieldInvalidValue, "Line %d: bus station %d is already set on line %d", line, id, first)
+		} else {
+			lines[id] = line
+		}
+		row.StationID = id
+
+		for _, c := range columns {
+			v, ok := parseAttributeValue(record[c.index])
+			if !ok {
+				b.Violation(errs.InBody, c.name, errs.FieldInvalidValue, "Line %d: %s must be yes, no or empty", line, c.name)
+				continue
+			}
+			*row.Attributes.Field(c.name) = v
+		}
+
+		if len(b.violations) >= maxImportViolations {
+			break
+		}
+		rows = append(rows, row)
+	}
+
+	return names, rows, lines
+}
+
+// attributeName matches a CSV header to stationId or an attribute name,
+// ignoring case and underscores, so station_id and Shelter are accepted.
+func attributeName(header string) string {
+	name := strings.ReplaceAll(strings.TrimSpace(header), "_", "")
+	for _, known := range append([]string{"stationId"}, store.StationAttributeNames...) {
+		if strings.EqualFold(name, known) {
+			return known
+		}
+	}
+	return name
+}
+
+// parseAttributeValue reads yes/no, true/false or 1/0. Empty values are
+// unknown.
+func parseAttributeValue(v string) (*bool, bool) {
+	var value bool
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "":
+		return nil, true
+	case "yes", "y", "true", "1":
+		value = true
+	case "no", "n", "false", "0":
+		value = false
+	default:
+		return nil, false
+	}
+	return &value, true
+}
@@ -0,0 +1,53 @@
+package v2
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/api"
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+type AlertHandler struct {
+	alertStore store.AlertStore
+	logger     *slog.Logger
+}
+
+func NewAlertHandler(alertStore store.AlertStore, logger *slog.Logger) *AlertHandler {
+	return &AlertHandler{
+		alertStore: alertStore,
+		logger:     logger.With(slog.String("handler", "v2.AlertHandler")),
+	}
+}
+
+// GetAlerts godoc
+// @Summary Get active alerts
+// @Description Retrieve the service notices active now, newest first, with their title and body in the response language. Alerts for the whole network are included with every line filter.
+// @Tags Alerts
+// @Produce json
+// @Param line query string false "Only alerts affecting this bus line, e.g. G6"
+// @Param lang query string false "Response language, overrides Accept-Language" Enums(sl, en)
+// @Success 200 {object} Envelope[[]store.Alert] "Active alerts"
+// @Failure 400 {object} api.Problem "Invalid parameters"
+// @Router /api/v2/alerts [get]
+func (h *AlertHandler) GetAlerts(w http.ResponseWriter, r *http.Request) error {
+	b := api.Bind(r)
+	line := strings.ToUpper(b.QueryStr("line", ""))
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	alerts, err := h.alertStore.ListAlerts(r.Context(), &store.AlertFilterOptions{
+		Line: line,
+		At:   utils.Now(),
+		Lang: i18n.FromContext(r.Context()),
+	})
+	if err != nil {
+		return err
+	}
+
+	return api.WriteJSON(w, http.StatusOK, newListEnvelope(r, alerts))
+}
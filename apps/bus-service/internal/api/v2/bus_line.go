@@ -0,0 +1,65 @@
+package v2
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/api"
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/pagination"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+)
+
+type BusLineHandler struct {
+	busLineStore store.BusLineStore
+	logger       *slog.Logger
+}
+
+func NewBusLineHandler(busLineStore store.BusLineStore, logger *slog.Logger) *BusLineHandler {
+	return &BusLineHandler{
+		busLineStore: busLineStore,
+		logger:       logger.With(slog.String("handler", "v2.BusLineHandler")),
+	}
+}
+
+// GetBusLines godoc
+// @Summary Get bus lines
+// @Description Retrieve bus lines. Without a limit all lines are returned.
+// @Tags Bus Lines
+// @Produce json
+// @Param limit query int false "Limit the number of results" default(0)
+// @Param offset query int false "Offset for pagination, ignored with a cursor" default(0)
+// @Param cursor query string false "Opaque cursor from meta.nextCursor of the previous page"
+// @Param sort query string false "Sort field, prefix with - for descending; name follows the sort order of the lines, then G1, G2, ..., P19" Enums(name, -name, distance, -distance, stationCount, -stationCount) default(name)
+// @Param total query bool false "Include the total count in meta.total"
+// @Param lat query number false "Latitude of the origin for distances"
+// @Param lon query number false "Longitude of the origin for distances"
+// @Param name query string false "Filter by bus line name prefix"
+// @Param station query int false "Only lines serving this bus station id"
+// @Param lang query string false "Response language, overrides Accept-Language" Enums(sl, en)
+// @Success 200 {object} Envelope[[]Line] "Page of bus lines"
+// @Failure 400 {object} api.Problem "Invalid parameters"
+// @Router /api/v2/bus-lines [get]
+func (h *BusLineHandler) GetBusLines(w http.ResponseWriter, r *http.Request) error {
+	b := api.Bind(r)
+	page := b.QueryPage(0, pagination.SortName, pagination.SortDistance, pagination.SortStationCount)
+	origin := b.QueryOrigin()
+	name := b.QueryStr("name", "")
+	stationID := b.QueryInt("station", 0)
+	b.RequireOrigin(page.Sort, origin)
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	lines, err := h.busLineStore.ListBusLines(r.Context(), &store.BusLineFilterOptions{
+		Name:      name,
+		StationID: stationID,
+		Origin:    origin,
+		Lang:      i18n.FromContext(r.Context()),
+	}, page)
+	if err != nil {
+		return err
+	}
+
+	return api.WriteJSON(w, http.StatusOK, newPageEnvelope(r, lines, newLine))
+}
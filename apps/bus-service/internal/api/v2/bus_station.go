@@ -0,0 +1,134 @@
+package v2
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/api"
+	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"github.com/perkzen/mbus/apps/bus-service/internal/pagination"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+)
+
+type BusStationHandler struct {
+	busStationStore store.BusStationStore
+	logger          *slog.Logger
+}
+
+func NewBusStationHandler(busStationStore store.BusStationStore, logger *slog.Logger) *BusStationHandler {
+	return &BusStationHandler{
+		busStationStore: busStationStore,
+		logger:          logger.With(slog.String("handler", "v2.BusStationHandler")),
+	}
+}
+
+// GetBusStations godoc
+// @Summary Get bus stations
+// @Description Retrieve a page of bus stations with optional filters
+// @Tags Bus Stations
+// @Produce json
+// @Param limit query int false "Limit the number of results" default(10)
+// @Param offset query int false "Offset for pagination, ignored with a cursor" default(0)
+// @Param cursor query string false "Opaque cursor from meta.nextCursor of the previous page"
+// @Param sort query string false "Sort field, prefix with - for descending" Enums(name, -name, distance, -distance, lineCount, -lineCount) default(name)
+// @Param total query bool false "Include the total count in meta.total"
+// @Param lat query number false "Latitude of the origin for distances"
+// @Param lon query number false "Longitude of the origin for distances"
+// @Param name query string false "Filter by bus station name"
+// @Param line query string false "Filter by bus line"
+// @Param has query []string false "Only stations known to have all of these attributes" collectionFormat(multi) Enums(wheelchairAccessible, shelter, bench, realtimeDisplay, ticketMachine, tactilePaving)
+// @Param lang query string false "Response language, overrides Accept-Language" Enums(sl, en)
+// @Success 200 {object} Envelope[[]Station] "Page of bus stations"
+// @Failure 400 {object} api.Problem "Invalid parameters"
+// @Router /api/v2/bus-stations [get]
+func (h *BusStationHandler) GetBusStations(w http.ResponseWriter, r *http.Request) error {
+	b := api.Bind(r)
+	page := b.QueryPage(10, pagination.SortName, pagination.SortDistance, pagination.SortLineCount)
+	origin := b.QueryOrigin()
+	name := b.QueryStr("name", "")
+	line := b.QueryStr("line", "")
+	attributes := b.QueryEnums("has", store.StationAttributeNames...)
+	b.RequireOrigin(page.Sort, origin)
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	busStations, err := h.busStationStore.ListBusStations(r.Context(), &store.BusStationFilterOptions{
+		Name:       name,
+		Line:       line,
+		Origin:     origin,
+		Attributes: attributes,
+	}, page)
+	if err != nil {
+		return err
+	}
+
+	return api.WriteJSON(w, http.StatusOK, newPageEnvelope(r, busStations, newStation))
+}
+
+// GetBusStationByID godoc
+// @Summary Get bus station by id
+// @Description Retrieve a bus station by its id
+// @Tags Bus Stations
+// @Produce json
+// @Param id path int true "Bus station id"
+// @Param lang query string false "Response language, overrides Accept-Language" Enums(sl, en)
+// @Success 200 {object} Envelope[Station] "Bus station details"
+// @Failure 400 {object} api.Problem "Invalid id"
+// @Failure 404 {object} api.Problem "Bus station not found"
+// @Router /api/v2/bus-stations/{id} [get]
+func (h *BusStationHandler) GetBusStationByID(w http.ResponseWriter, r *http.Request) error {
+	b := api.Bind(r)
+	stationID := b.PathInt("id")
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	busStation, err := h.busStationStore.FindBusStationByID(r.Context(), stationID)
+	if err != nil {
+		return err
+	}
+	if busStation == nil {
+		return errs.BusStationNotFoundError(stationID)
+	}
+
+	return api.WriteJSON(w, http.StatusOK, newEnvelope(r, newStation(*busStation)))
+}
+
+// GetBusStationStops godoc
+// @Summary Get the stops of a bus station
+// @Description Retrieve the stops of a bus station, one per stop code, with their platform, position and the lines and directions departing from them
+// @Tags Bus Stations
+// @Produce json
+// @Param id path int true "Bus station id"
+// @Param lang query string false "Response language, overrides Accept-Language" Enums(sl, en)
+// @Success 200 {object} Envelope[[]Stop] "Stops of the bus station in code order"
+// @Failure 400 {object} api.Problem "Invalid id"
+// @Failure 404 {object} api.Problem "Bus station not found"
+// @Router /api/v2/bus-stations/{id}/stops [get]
+func (h *BusStationHandler) GetBusStationStops(w http.ResponseWriter, r *http.Request) error {
+	b := api.Bind(r)
+	stationID := b.PathInt("id")
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	busStation, err := h.busStationStore.FindBusStationByID(r.Context(), stationID)
+	if err != nil {
+		return err
+	}
+	if busStation == nil {
+		return errs.BusStationNotFoundError(stationID)
+	}
+
+	stops, err := h.busStationStore.FindStopsByStationID(r.Context(), stationID)
+	if err != nil {
+		return err
+	}
+
+	items := make([]Stop, len(stops))
+	for i, s := range stops {
+		items[i] = newStop(s)
+	}
+	return api.WriteJSON(w, http.StatusOK, newListEnvelope(r, items))
+}
@@ -0,0 +1,88 @@
+package v2
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/api"
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/departure"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+type DepartureHandler struct {
+	departureService *departure.Service
+	logger           *slog.Logger
+}
+
+func NewDepartureHandler(departureService *departure.Service, logger *slog.Logger) *DepartureHandler {
+	return &DepartureHandler{
+		departureService: departureService,
+		logger:           logger.With(slog.String("handler", "v2.DepartureHandler")),
+	}
+}
+
+// GetDepartures godoc
+// @Summary Get departures
+// @Description Retrieve departures between two bus stations on a service date. Times are RFC 3339 timestamps and durations ISO 8601.
+// @Tags Departures
+// @Produce json
+// @Param from query int true "Departure station id"
+// @Param to query int true "Arrival station id"
+// @Param date query string false "Service date in YYYY-MM-DD format, defaults to the current service day, which runs until 03:00 the next morning"
+// @Param after query string false "Only departures at or after this time (HH:MM or 'now')"
+// @Param before query string false "Only departures at or before this time (HH:MM)"
+// @Param limit query int false "Maximum number of departures"
+// @Param line query []string false "Only these bus lines" collectionFormat(multi)
+// @Param direction query string false "Only directions containing this text"
+// @Param stepFree query bool false "Only journeys between wheelchair accessible stations"
+// @Param lang query string false "Response language, overrides Accept-Language" Enums(sl, en)
+// @Success 200 {object} Envelope[[]Departure] "Departures"
+// @Failure 400 {object} api.Problem "Invalid parameters"
+// @Failure 404 {object} api.Problem "Bus station not found"
+// @Router /api/v2/departures [get]
+func (h *DepartureHandler) GetDepartures(w http.ResponseWriter, r *http.Request) error {
+	b := api.Bind(r)
+	fromID := b.RequiredQueryInt("from")
+	toID := b.RequiredQueryInt("to")
+	date := b.QueryDate("date", utils.ServiceDate())
+	after := b.QueryServiceTime("after")
+	before := b.QueryServiceTime("before")
+	limit := b.QueryInt("limit", 0)
+	b.Min("limit", limit, 0)
+	filter := departure.Filter{
+		Lines:     b.QueryStrs("line"),
+		Direction: b.QueryStr("direction", ""),
+		StepFree:  b.QueryBool("stepFree", false),
+		After:     after,
+		Before:    before,
+		Limit:     limit,
+	}
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	rows, err := h.departureService.GenerateTimetable(r.Context(), fromID, toID, date, filter)
+	if err != nil {
+		return err
+	}
+
+	departures := make([]Departure, 0, len(rows))
+	for _, row := range rows {
+		d, err := newDeparture(date, row)
+		if err != nil {
+			return err
+		}
+		departures = append(departures, d)
+	}
+
+	envelope := newListEnvelope(r, departures)
+	envelope.Meta.Date = date
+	schedule := store.ScheduleTyp(date)
+	envelope.Meta.Schedule = &Schedule{
+		Type:  string(schedule),
+		Label: i18n.T(i18n.FromContext(r.Context()), schedule.Label()),
+	}
+	return api.WriteJSON(w, http.StatusOK, envelope)
+}
@@ -0,0 +1,8 @@
+// Package v2 serves the /api/v2 route tree. Every response is wrapped in an
+// Envelope, durations are ISO 8601, timestamps are RFC 3339 in the agency
+// timezone and field names carry their units.
+package v2
+
+// @title mubs Bus Service API
+// @version 2.0
+// @description Version 2 of the mubs Bus Service API. Responses are wrapped in data/meta/links envelopes, durations are ISO 8601 and timestamps RFC 3339 with the agency's UTC offset.
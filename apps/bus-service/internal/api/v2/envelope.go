@@ -0,0 +1,79 @@
+package v2
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/api"
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/pagination"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+// Envelope wraps every v2 response body.
+type Envelope[T any] struct {
+	Data  T     `json:"data"`
+	Meta  Meta  `json:"meta"`
+	Links Links `json:"links"`
+}
+
+type Meta struct {
+	// GeneratedAt is when the response was built, in the agency timezone.
+	GeneratedAt time.Time `json:"generatedAt"`
+	Timezone    string    `json:"timezone"`
+	// Count is the number of items in data, for list responses.
+	Count *int `json:"count,omitempty"`
+	// Total is the number of matching items across all pages, if requested.
+	Total      *int   `json:"total,omitempty"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	// Date is the service date of timetable responses.
+	Date string `json:"date,omitempty"`
+	// Schedule is the timetable that applies on Date.
+	Schedule *Schedule `json:"schedule,omitempty"`
+	// Lang is the language of messages and localized fields.
+	Lang string `json:"lang"`
+} // @name Meta
+
+type Schedule struct {
+	Type  string `json:"type" enums:"weekday,saturday,sunday"`
+	Label string `json:"label" example:"Weekday"`
+} // @name Schedule
+
+type Links struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+} // @name Links
+
+func newEnvelope[T any](r *http.Request, data T) Envelope[T] {
+	return Envelope[T]{
+		Data: data,
+		Meta: Meta{
+			GeneratedAt: utils.Now().Truncate(time.Second),
+			Timezone:    utils.Location().String(),
+			Lang:        string(i18n.FromContext(r.Context())),
+		},
+		Links: Links{Self: r.URL.RequestURI()},
+	}
+}
+
+func newListEnvelope[T any](r *http.Request, items []T) Envelope[[]T] {
+	e := newEnvelope(r, items)
+	count := len(items)
+	e.Meta.Count = &count
+	return e
+}
+
+func newPageEnvelope[S, T any](r *http.Request, page *pagination.Page[S], convert func(S) T) Envelope[[]T] {
+	items := make([]T, len(page.Items))
+	for i, item := range page.Items {
+		items[i] = convert(item)
+	}
+
+	e := newListEnvelope(r, items)
+	e.Meta.Total = page.Total
+	if page.NextCursor != "" {
+		e.Meta.NextCursor = page.NextCursor
+		e.Links.Next = api.NextPageURL(r, page.NextCursor)
+	}
+	return e
+}
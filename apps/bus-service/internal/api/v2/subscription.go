@@ -0,0 +1,466 @@
+package v2
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/api"
+	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/notify"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+// SubscriptionTokenHeader carries the token returned when a subscription is
+// created. It is required to read or delete the subscription.
+const SubscriptionTokenHeader = "X-Subscription-Token"
+
+// Subscription limits.
+const (
+	maxSubscriptionName = 100
+	defaultLeadMinutes  = 5
+	maxLeadMinutes      = 60
+)
+
+type SubscriptionHandler struct {
+	subscriptionStore store.SubscriptionStore
+	busStationStore   store.BusStationStore
+	notifiers         notify.Notifiers
+	logger            *slog.Logger
+}
+
+func NewSubscriptionHandler(
+	subscriptionStore store.SubscriptionStore,
+	busStationStore store.BusStationStore,
+	notifiers notify.Notifiers,
+	logger *slog.Logger,
+) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		subscriptionStore: subscriptionStore,
+		busStationStore:   busStationStore,
+		notifiers:         notifiers,
+		logger:            logger.With(slog.String("handler", "v2.SubscriptionHandler")),
+	}
+}
+
+type Subscription struct {
+	ID       int        `json:"id"`
+	Name     string     `json:"name"`
+	From     StationRef `json:"from"`
+	To       StationRef `json:"to"`
+	Lines    []string   `json:"lines"`
+	Weekdays []string   `json:"weekdays" enums:"mon,tue,wed,thu,fri,sat,sun"`
+	// WindowStart and WindowEnd bound the departures that are reminded of,
+	// as HH:MM wall-clock times.
+	WindowStart string `json:"windowStart" example:"07:00"`
+	WindowEnd   string `json:"windowEnd" example:"08:30"`
+	// LeadMinutes is how long before a departure the reminder is sent.
+	LeadMinutes int       `json:"leadMinutes" example:"5"`
+	Channel     string    `json:"channel" enums:"webhook,email"`
+	Target      string    `json:"target" example:"https://example.com/hooks/mbus"`
+	Lang        string    `json:"lang" enums:"sl,en"`
+	CreatedAt   time.Time `json:"createdAt"`
+	// Confirmed is false until the target opted in with the code sent to
+	// it. Unconfirmed subscriptions get no reminders.
+	Confirmed bool `json:"confirmed"`
+	// Token is only returned on creation. Send it in the X-Subscription-Token
+	// header to read or delete the subscription.
+	Token string `json:"token,omitempty"`
+} // @name Subscription
+
+type subscriptionRequest struct {
+	Name          string   `json:"name" example:"To work"`
+	FromStationID int      `json:"fromStationId" example:"1"`
+	ToStationID   int      `json:"toStationId" example:"2"`
+	Lines         []string `json:"lines"`
+	Weekdays      []string `json:"weekdays" enums:"mon,tue,wed,thu,fri,sat,sun"`
+	WindowStart   string   `json:"windowStart" example:"07:00"`
+	WindowEnd     string   `json:"windowEnd" example:"08:30"`
+	// LeadMinutes defaults to 5.
+	LeadMinutes *int   `json:"leadMinutes,omitempty" example:"5"`
+	Channel     string `json:"channel" enums:"webhook,email"`
+	Target      string `json:"target" example:"https://example.com/hooks/mbus"`
+	// Lang of the reminders, defaults to the language of the request.
+	Lang string `json:"lang,omitempty" enums:"sl,en"`
+
+	windowStart utils.ServiceTime
+	windowEnd   utils.ServiceTime
+} // @name SubscriptionRequest
+
+func (s *subscriptionRequest) Validate(b *api.Binder) {
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Name == "" {
+		b.Violation(errs.InBody, "name", errs.FieldRequired, "%s is required", "name")
+	} else if utf8.RuneCountInString(s.Name) > maxSubscriptionName {
+		b.Violation(errs.InBody, "name", errs.FieldOutOfRange, "%s must be at most %d characters", "name", maxSubscriptionName)
+	}
+
+	if s.FromStationID <= 0 {
+		b.Violation(errs.InBody, "fromStationId", errs.FieldRequired, "%s is required", "fromStationId")
+	}
+	if s.ToStationID <= 0 {
+		b.Violation(errs.InBody, "toStationId", errs.FieldRequired, "%s is required", "toStationId")
+	} else if s.ToStationID == s.FromStationID {
+		b.Violation(errs.InBody, "toStationId", errs.FieldInvalidValue, "%s must differ from %s", "toStationId", "fromStationId")
+	}
+
+	lines := make([]string, 0, len(s.Lines))
+	for _, l := range s.Lines {
+		if l = strings.ToUpper(strings.TrimSpace(l)); l != "" && !slices.Contains(lines, l) {
+			lines = append(lines, l)
+		}
+	}
+	s.Lines = lines
+
+	if len(s.Weekdays) == 0 {
+		b.Violation(errs.InBody, "weekdays", errs.FieldRequired, "%s is required", "weekdays")
+	}
+	weekdays := make([]string, 0, len(s.Weekdays))
+	for _, d := range s.Weekdays {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if !slices.Contains(store.Weekdays, d) {
+			b.Violation(errs.InBody, "weekdays", errs.FieldInvalidValue, "%s must be one of: %s", "weekdays", "mon, tue, wed, thu, fri, sat, sun")
+			break
+		}
+		if !slices.Contains(weekdays, d) {
+			weekdays = append(weekdays, d)
+		}
+	}
+	s.Weekdays = weekdays
+
+	s.windowStart = clockField(b, "windowStart", s.WindowStart)
+	s.windowEnd = clockField(b, "windowEnd", s.WindowEnd)
+	if b.Valid("windowStart") && b.Valid("windowEnd") && s.windowStart > s.windowEnd {
+		b.Violation(errs.InBody, "windowStart", errs.FieldInvalidValue, "%s must not be after %s", "windowStart", "windowEnd")
+	}
+
+	if s.LeadMinutes == nil {
+		lead := defaultLeadMinutes
+		s.LeadMinutes = &lead
+	} else if *s.LeadMinutes < 0 || *s.LeadMinutes > maxLeadMinutes {
+		b.Violation(errs.InBody, "leadMinutes", errs.FieldOutOfRange, "%s must be between %d and %d", "leadMinutes", 0, maxLeadMinutes)
+	}
+
+	if s.Lang != "" {
+		if _, ok := i18n.Parse(s.Lang); !ok || len(s.Lang) != 2 {
+			b.Violation(errs.InBody, "lang", errs.FieldInvalidValue, "%s is not a supported language", s.Lang)
+		}
+	}
+}
+
+// clockField parses a required HH:MM wall-clock time of the service day.
+func clockField(b *api.Binder, name, v string) utils.ServiceTime {
+	if v == "" {
+		b.Violation(errs.InBody, name, errs.FieldRequired, "%s is required", name)
+		return 0
+	}
+	t, err := utils.ServiceTimeFromClock(v)
+	if err != nil {
+		b.Violation(errs.InBody, name, errs.FieldInvalidFormat, "%s must be a time in HH:MM format", name)
+		return 0
+	}
+	return t
+}
+
+// CreateSubscription godoc
+// @Summary Create a subscription
+// @Description Save a trip and get reminded of its departures. Every departure from the origin to the destination that leaves inside the time window on one of the weekdays is announced leadMinutes ahead through the channel. The response contains a token that is required to read, confirm or delete the subscription; it is not shown again. Email subscriptions are sent a confirmation code and get no reminders until it is posted to /api/v2/subscriptions/{id}/confirm. Webhook targets must be public http or https URLs.
+// @Tags Subscriptions
+// @Accept json
+// @Produce json
+// @Param subscription body subscriptionRequest true "Subscription"
+// @Param lang query string false "Response language, overrides Accept-Language" Enums(sl, en)
+// @Success 201 {object} Envelope[Subscription] "Created subscription with its token"
+// @Failure 400 {object} api.Problem "Invalid subscription"
+// @Failure 502 {object} api.Problem "Confirmation could not be sent"
+// @Router /api/v2/subscriptions [post]
+func (h *SubscriptionHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	b := api.Bind(r)
+	var req subscriptionRequest
+	b.DecodeJSON(&req)
+	if b.Valid("channel") && b.Valid("") {
+		if notifier, ok := h.notifiers[req.Channel]; !ok {
+			b.Violation(errs.InBody, "channel", errs.FieldInvalidValue, "%s must be one of: %s", "channel", strings.Join(h.notifiers.Channels(), ", "))
+		} else if !notifier.ValidTarget(ctx, req.Target) {
+			b.Violation(errs.InBody, "target", errs.FieldInvalidValue, "%s is not a valid target for channel %s", "target", req.Channel)
+		}
+	}
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	stations, err := h.stationRefs(r, req.FromStationID, req.ToStationID)
+	if err != nil {
+		return err
+	}
+	if _, ok := stations[req.FromStationID]; !ok {
+		b.Violation(errs.InBody, "fromStationId", errs.FieldInvalidValue, "Bus station with ID %d does not exist", req.FromStationID)
+	}
+	if _, ok := stations[req.ToStationID]; !ok {
+		b.Violation(errs.InBody, "toStationId", errs.FieldInvalidValue, "Bus station with ID %d does not exist", req.ToStationID)
+	}
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	lang := i18n.FromContext(ctx)
+	if req.Lang != "" {
+		lang, _ = i18n.Parse(req.Lang)
+	}
+
+	token, err := newSubscriptionToken()
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256([]byte(token))
+
+	confirmer, needsConfirmation := h.notifiers.Confirmer(req.Channel)
+	var code string
+	var codeHash []byte
+	if needsConfirmation {
+		if code, err = newConfirmationCode(); err != nil {
+			return err
+		}
+		sum := sha256.Sum256([]byte(code))
+		codeHash = sum[:]
+	}
+
+	sub := &store.Subscription{
+		Name:          req.Name,
+		FromStationID: req.FromStationID,
+		ToStationID:   req.ToStationID,
+		Lines:         req.Lines,
+		Weekdays:      req.Weekdays,
+		WindowStart:   req.windowStart,
+		WindowEnd:     req.windowEnd,
+		LeadMinutes:   *req.LeadMinutes,
+		Channel:       req.Channel,
+		Target:        req.Target,
+		Lang:          string(lang),
+		TokenHash:     hash[:],
+
+		ConfirmationHash: codeHash,
+	}
+	if err := h.subscriptionStore.CreateSubscription(ctx, sub); err != nil {
+		return err
+	}
+
+	if needsConfirmation {
+		err := confirmer.Confirm(ctx, sub.Target, notify.Confirmation{
+			SubscriptionID: sub.ID,
+			Name:           sub.Name,
+			Lang:           lang,
+			Code:           code,
+		})
+		if err != nil {
+			h.logger.Error("failed to send confirmation", slog.Int("id", sub.ID), slog.Any("error", err))
+			if _, err := h.subscriptionStore.DeleteSubscription(ctx, sub.ID); err != nil {
+				return err
+			}
+			return errs.BadGatewayError("Confirmation could not be sent to %s", sub.Target)
+		}
+	}
+
+	h.logger.Info("created subscription", slog.Int("id", sub.ID), slog.String("channel", sub.Channel))
+	data := newSubscription(*sub, stations)
+	data.Token = token
+	return api.WriteJSON(w, http.StatusCreated, newEnvelope(r, data))
+}
+
+// GetSubscription godoc
+// @Summary Get a subscription
+// @Description Retrieve a subscription with the token returned on creation
+// @Tags Subscriptions
+// @Produce json
+// @Param id path int true "Subscription id"
+// @Param X-Subscription-Token header string true "Token returned on creation"
+// @Param lang query string false "Response language, overrides Accept-Language" Enums(sl, en)
+// @Success 200 {object} Envelope[Subscription] "Subscription"
+// @Failure 404 {object} api.Problem "Subscription not found or token does not match"
+// @Router /api/v2/subscriptions/{id} [get]
+func (h *SubscriptionHandler) GetSubscription(w http.ResponseWriter, r *http.Request) error {
+	sub, err := h.authorizedSubscription(r)
+	if err != nil {
+		return err
+	}
+
+	stations, err := h.stationRefs(r, sub.FromStationID, sub.ToStationID)
+	if err != nil {
+		return err
+	}
+
+	return api.WriteJSON(w, http.StatusOK, newEnvelope(r, newSubscription(*sub, stations)))
+}
+
+type confirmationRequest struct {
+	// Code is the confirmation code sent to the target.
+	Code string `json:"code" example:"K7QX2M4D"`
+} // @name ConfirmationRequest
+
+func (c *confirmationRequest) Validate(b *api.Binder) {
+	c.Code = strings.ToUpper(strings.TrimSpace(c.Code))
+	if c.Code == "" {
+		b.Violation(errs.InBody, "code", errs.FieldRequired, "%s is required", "code")
+	}
+}
+
+// ConfirmSubscription godoc
+// @Summary Confirm a subscription
+// @Description Opt in to the reminders of a subscription with the code sent to its target. Confirming an already confirmed subscription has no effect.
+// @Tags Subscriptions
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription id"
+// @Param X-Subscription-Token header string true "Token returned on creation"
+// @Param confirmation body confirmationRequest true "Confirmation code"
+// @Param lang query string false "Response language, overrides Accept-Language" Enums(sl, en)
+// @Success 200 {object} Envelope[Subscription] "Confirmed subscription"
+// @Failure 400 {object} api.Problem "Code does not match"
+// @Failure 404 {object} api.Problem "Subscription not found or token does not match"
+// @Router /api/v2/subscriptions/{id}/confirm [post]
+func (h *SubscriptionHandler) ConfirmSubscription(w http.ResponseWriter, r *http.Request) error {
+	sub, err := h.authorizedSubscription(r)
+	if err != nil {
+		return err
+	}
+
+	b := api.Bind(r)
+	var req confirmationRequest
+	b.DecodeJSON(&req)
+	if b.Valid("code") && sub.ConfirmedAt == nil {
+		hash := sha256.Sum256([]byte(req.Code))
+		if subtle.ConstantTimeCompare(hash[:], sub.ConfirmationHash) != 1 {
+			b.Violation(errs.InBody, "code", errs.FieldInvalidValue, "%s does not match the code that was sent", "code")
+		}
+	}
+	if err := b.Err(); err != nil {
+		return err
+	}
+
+	if sub.ConfirmedAt == nil {
+		confirmedAt, err := h.subscriptionStore.ConfirmSubscription(r.Context(), sub.ID)
+		if err != nil {
+			return err
+		}
+		if confirmedAt == nil {
+			// Confirmed or deleted concurrently.
+			return h.GetSubscription(w, r)
+		}
+		sub.ConfirmedAt = confirmedAt
+		h.logger.Info("confirmed subscription", slog.Int("id", sub.ID))
+	}
+
+	stations, err := h.stationRefs(r, sub.FromStationID, sub.ToStationID)
+	if err != nil {
+		return err
+	}
+
+	return api.WriteJSON(w, http.StatusOK, newEnvelope(r, newSubscription(*sub, stations)))
+}
+
+// DeleteSubscription godoc
+// @Summary Delete a subscription
+// @Description Stop the reminders of a subscription and delete it
+// @Tags Subscriptions
+// @Param id path int true "Subscription id"
+// @Param X-Subscription-Token header string true "Token returned on creation"
+// @Success 204 "Subscription deleted"
+// @Failure 404 {object} api.Problem "Subscription not found or token does not match"
+// @Router /api/v2/subscriptions/{id} [delete]
+func (h *SubscriptionHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) error {
+	sub, err := h.authorizedSubscription(r)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.subscriptionStore.DeleteSubscription(r.Context(), sub.ID); err != nil {
+		return err
+	}
+
+	h.logger.Info("deleted subscription", slog.Int("id", sub.ID))
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// authorizedSubscription loads the subscription in the path if the request
+// carries its token. A wrong token is reported like a missing subscription,
+// so ids cannot be probed.
+func (h *SubscriptionHandler) authorizedSubscription(r *http.Request) (*store.Subscription, error) {
+	b := api.Bind(r)
+	id := b.PathInt("id")
+	if err := b.Err(); err != nil {
+		return nil, err
+	}
+
+	sub, err := h.subscriptionStore.FindSubscriptionByID(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256([]byte(r.Header.Get(SubscriptionTokenHeader)))
+	if sub == nil || subtle.ConstantTimeCompare(hash[:], sub.TokenHash) != 1 {
+		return nil, errs.NotFoundError("Subscription does not exist")
+	}
+	return sub, nil
+}
+
+func (h *SubscriptionHandler) stationRefs(r *http.Request, ids ...int) (map[int]StationRef, error) {
+	stations, err := h.busStationStore.FindBusStationsByIDs(r.Context(), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make(map[int]StationRef, len(stations))
+	for _, s := range stations {
+		refs[s.ID] = StationRef{ID: s.ID, Name: s.Name}
+	}
+	return refs, nil
+}
+
+func newSubscription(s store.Subscription, stations map[int]StationRef) Subscription {
+	return Subscription{
+		ID:          s.ID,
+		Name:        s.Name,
+		From:        stations[s.FromStationID],
+		To:          stations[s.ToStationID],
+		Lines:       nonNil(s.Lines),
+		Weekdays:    nonNil(s.Weekdays),
+		WindowStart: s.WindowStart.Clock(),
+		WindowEnd:   s.WindowEnd.Clock(),
+		LeadMinutes: s.LeadMinutes,
+		Channel:     s.Channel,
+		Target:      s.Target,
+		Lang:        s.Lang,
+		CreatedAt:   s.CreatedAt.Truncate(time.Second),
+		Confirmed:   s.ConfirmedAt != nil,
+	}
+}
+
+// newConfirmationCode returns 8 random base32 characters, short enough to
+// type from an email.
+func newConfirmationCode() (string, error) {
+	code := make([]byte, 5)
+	if _, err := rand.Read(code); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.EncodeToString(code), nil
+}
+
+func newSubscriptionToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
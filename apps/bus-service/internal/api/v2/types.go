@@ -0,0 +1,200 @@
+package v2
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/departure"
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/images"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+type Station struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// ImageURL is the station photo; append ?size=thumb, small or medium
+	// for a JPEG thumbnail.
+	ImageURL     string   `json:"imageUrl,omitempty"`
+	ThumbnailURL string   `json:"thumbnailUrl,omitempty"`
+	Lat          float64  `json:"lat"`
+	Lon          float64  `json:"lon"`
+	Codes        []int    `json:"codes"`
+	Lines        []string `json:"lines"`
+	// LineDetails are the lines serving the station with their metadata, in
+	// line order.
+	LineDetails []LineRef `json:"lineDetails"`
+	// Attributes describe accessibility and amenities; null fields are
+	// unknown.
+	Attributes store.StationAttributes `json:"attributes"`
+	// DistanceMeters from the requested origin, if one was given.
+	DistanceMeters *int `json:"distanceMeters,omitempty"`
+} // @name Station
+
+type Line struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	store.LineMetadata
+	// DistanceMeters from the requested origin to the closest station of the
+	// line, if an origin was given.
+	DistanceMeters *int `json:"distanceMeters,omitempty"`
+} // @name Line
+
+type LineRef struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	store.LineMetadata
+} // @name LineRef
+
+type StationRef struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Stop is the platform to board at, or to get off at for the
+	// destination.
+	Stop *StopRef `json:"stop,omitempty"`
+} // @name StationRef
+
+type StopRef struct {
+	ID       int     `json:"id"`
+	Code     int     `json:"code"`
+	Platform string  `json:"platform"`
+	Lat      float64 `json:"lat"`
+	Lon      float64 `json:"lon"`
+} // @name StopRef
+
+// Stop is a stop of a station, e.g. one side of the road, with its own code.
+type Stop struct {
+	StopRef
+	// Lines and Directions departing from the stop.
+	Lines      []string `json:"lines"`
+	Directions []string `json:"directions"`
+} // @name Stop
+
+type Departure struct {
+	ID        int        `json:"id"`
+	Line      string     `json:"line"`
+	Direction string     `json:"direction"`
+	From      StationRef `json:"from"`
+	To        StationRef `json:"to"`
+	// DepartureTime and ArrivalTime are RFC 3339 timestamps with the agency's
+	// UTC offset, so trips past midnight need no day offsets.
+	DepartureTime time.Time `json:"departureTime"`
+	ArrivalTime   time.Time `json:"arrivalTime"`
+	// Duration is an ISO 8601 duration, e.g. "PT25M".
+	Duration       string `json:"duration" example:"PT25M"`
+	DistanceMeters int    `json:"distanceMeters"`
+	// Estimated is set when distance and travel time come from the offline
+	// estimator instead of the routing provider.
+	Estimated bool `json:"estimated"`
+	// StepFree is set when both stations are known to be wheelchair
+	// accessible.
+	StepFree bool `json:"stepFree"`
+	// LineDetails is the line with its metadata.
+	LineDetails *LineRef `json:"lineDetails,omitempty"`
+} // @name Departure
+
+func newStation(s store.BusStation) Station {
+	return Station{
+		ID:             s.ID,
+		Name:           s.Name,
+		ImageURL:       s.ImageURL,
+		ThumbnailURL:   s.ImageSizeURL(images.SizeThumb),
+		Lat:            s.Lat,
+		Lon:            s.Lon,
+		Codes:          nonNil(s.Codes),
+		Lines:          nonNil(s.Lines),
+		LineDetails:    newLineRefs(s.LineDetails),
+		Attributes:     s.Attributes,
+		DistanceMeters: meters(s.Distance),
+	}
+}
+
+func newLine(l store.BusLine) Line {
+	return Line{
+		ID:             l.ID,
+		Name:           l.Name,
+		Description:    l.Description,
+		LineMetadata:   l.LineMetadata,
+		DistanceMeters: meters(l.Distance),
+	}
+}
+
+func newLineRefs(lines []store.LineRef) []LineRef {
+	refs := make([]LineRef, len(lines))
+	for i, l := range lines {
+		refs[i] = LineRef(l)
+	}
+	return refs
+}
+
+func newStopRef(c store.StationCode) StopRef {
+	return StopRef{
+		ID:       c.ID,
+		Code:     c.Code,
+		Platform: c.Platform,
+		Lat:      c.Lat,
+		Lon:      c.Lon,
+	}
+}
+
+func newStop(s store.Stop) Stop {
+	return Stop{
+		StopRef:    newStopRef(s.StationCode),
+		Lines:      nonNil(s.Lines),
+		Directions: nonNil(s.Directions),
+	}
+}
+
+func newStationRef(s departure.Station) StationRef {
+	ref := StationRef{ID: s.ID, Name: s.Name}
+	if s.Stop != nil {
+		stop := newStopRef(*s.Stop)
+		ref.Stop = &stop
+	}
+	return ref
+}
+
+func newDeparture(date string, row departure.TimetableRow) (Departure, error) {
+	departureTime, err := utils.ServiceDateTime(date, row.GetDepartureAt())
+	if err != nil {
+		return Departure{}, fmt.Errorf("failed to resolve departure time of %d: %w", row.ID, err)
+	}
+	arrivalTime, err := utils.ServiceDateTime(date, row.GetArriveAt())
+	if err != nil {
+		return Departure{}, fmt.Errorf("failed to resolve arrival time of %d: %w", row.ID, err)
+	}
+
+	return Departure{
+		ID:             row.ID,
+		Line:           row.Line,
+		Direction:      row.Direction,
+		From:           newStationRef(row.FromStation),
+		To:             newStationRef(row.ToStation),
+		DepartureTime:  departureTime,
+		ArrivalTime:    arrivalTime,
+		Duration:       utils.ISODuration(arrivalTime.Sub(departureTime)),
+		DistanceMeters: int(math.Round(row.Distance * 1000)),
+		Estimated:      row.Estimated,
+		StepFree:       row.StepFree,
+		LineDetails:    (*LineRef)(row.LineDetails),
+	}, nil
+}
+
+// meters converts an optional distance in kilometres.
+func meters(km *float64) *int {
+	if km == nil {
+		return nil
+	}
+	m := int(math.Round(*km * 1000))
+	return &m
+}
+
+// nonNil keeps empty lists as [] rather than null.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
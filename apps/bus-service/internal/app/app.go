@@ -5,16 +5,32 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/perkzen/mbus/apps/bus-service/internal/api"
+	v2 "github.com/perkzen/mbus/apps/bus-service/internal/api/v2"
+	"github.com/perkzen/mbus/apps/bus-service/internal/cache"
 	"github.com/perkzen/mbus/apps/bus-service/internal/config"
 	"github.com/perkzen/mbus/apps/bus-service/internal/db"
+	"github.com/perkzen/mbus/apps/bus-service/internal/graph"
+	"github.com/perkzen/mbus/apps/bus-service/internal/health"
+	"github.com/perkzen/mbus/apps/bus-service/internal/notify"
+	"github.com/perkzen/mbus/apps/bus-service/internal/objectstore"
+	"github.com/perkzen/mbus/apps/bus-service/internal/provider/estimator"
 	"github.com/perkzen/mbus/apps/bus-service/internal/provider/openrouteservice"
+	"github.com/perkzen/mbus/apps/bus-service/internal/provider/osrm"
+	"github.com/perkzen/mbus/apps/bus-service/internal/provider/routing"
+	"github.com/perkzen/mbus/apps/bus-service/internal/provider/valhalla"
+	"github.com/perkzen/mbus/apps/bus-service/internal/rpc"
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/cacheadmin"
 	"github.com/perkzen/mbus/apps/bus-service/internal/service/departure"
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/geo"
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/images"
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/reminder"
 	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
 	"github.com/perkzen/mbus/apps/bus-service/migrations"
 	"github.com/redis/go-redis/v9"
 	"log/slog"
-	"net/http"
 	"os"
+	"time"
 )
 
 type Application struct {
@@ -24,7 +40,26 @@ type Application struct {
 	BusStationHandler *api.BusStationHandler
 	BusLineHandler    *api.BusLineHandler
 	DepartureHandler  *api.DepartureHandler
-	Cache             *redis.Client
+	HealthHandler     *api.HealthHandler
+	AdminHandler      *api.AdminHandler
+	GeoHandler        *api.GeoHandler
+	GraphQLHandler    *api.GraphQLHandler
+	ImageHandler      *api.ImageHandler
+	BusService        *rpc.BusService
+	HealthChecker     *health.Checker
+	ReminderScheduler *reminder.Scheduler
+	V2                *V2Handlers
+	Cache             cache.Cache
+	CacheNamespace    *cache.Namespace
+}
+
+// V2Handlers serve the /api/v2 route tree.
+type V2Handlers struct {
+	BusStationHandler   *v2.BusStationHandler
+	BusLineHandler      *v2.BusLineHandler
+	DepartureHandler    *v2.DepartureHandler
+	SubscriptionHandler *v2.SubscriptionHandler
+	AlertHandler        *v2.AlertHandler
 }
 
 func NewApplication(env *config.Environment) (*Application, error) {
@@ -33,7 +68,15 @@ func NewApplication(env *config.Environment) (*Application, error) {
 	}))
 	slog.SetDefault(logger)
 
-	pgDb, err := db.NewPostgresDB(env.PostgresURL).Open()
+	loc, err := time.LoadLocation(env.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load timezone %q: %w", env.Timezone, err)
+	}
+	utils.SetLocation(loc)
+
+	pgDb, err := db.NewPostgresDB(env.PostgresURL).
+		WithStatementTimeout(env.DBQueryTimeout).
+		Open()
 	if err != nil {
 		return nil, fmt.Errorf("failed to open Postgres DB: %w", err)
 	}
@@ -46,48 +89,216 @@ func NewApplication(env *config.Environment) (*Application, error) {
 		return nil, fmt.Errorf("failed to run DB migrations: %w", err)
 	}
 
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     env.RedisAddr,
-		Password: env.RedisPassword,
-		DB:       0,
-	})
-
-	if _, err := rdb.Ping(context.Background()).Result(); err != nil {
-		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	appCache, err := newCache(env)
+	if err != nil {
+		return nil, err
 	}
 
+	dataVersionStore := store.NewPostgresDataVersionStore(pgDb)
+	cacheNamespace := cache.NewNamespace(dataVersionStore, env.CacheGenerationTTL)
+
 	busStationStore := store.NewPostgresBusStationStore(pgDb)
 	busStationHandler := api.NewBusStationHandler(busStationStore, logger)
 
 	busLineStore := store.NewPostgresBusLineStore(pgDb)
 	busLineHandler := api.NewBusLineHandler(busLineStore, logger)
 
-	orsApiClient := openrouteservice.NewAPIClient(env.ORSApiKey, rdb)
+	router, err := NewRouter(env)
+	if err != nil {
+		return nil, err
+	}
 	departureStore := store.NewPostgresDepartureStore(pgDb)
 	directionStore := store.NewPostgresDirectionStore(pgDb)
+	segmentStore := store.NewPostgresSegmentStore(pgDb)
+	switch env.EstimatorMode {
+	case estimator.ModeFallback, estimator.ModeExclusive, estimator.ModeDisabled:
+	default:
+		return nil, fmt.Errorf("unknown estimator mode %q", env.EstimatorMode)
+	}
+	travelEstimator := estimator.New(departureStore, estimator.Options{
+		DetourFactor: env.EstimatorDetourFactor,
+		SpeedKmh:     env.EstimatorSpeedKmh,
+	})
+	cachedRouter := routing.NewCachedProvider(router, appCache, 24*time.Hour)
 	departureService := departure.NewService(
-		orsApiClient,
-		rdb,
+		cachedRouter,
+		appCache,
+		cacheNamespace,
 		busStationStore,
 		departureStore,
 		busLineStore,
 		directionStore,
-		departure.WithCache(env.EnableCache))
+		departure.WithCache(env.EnableCache),
+		departure.WithEstimator(travelEstimator, env.EstimatorMode),
+		departure.WithSegments(segmentStore),
+		departure.WithCacheOptions(
+			utils.WithStaleWhileRevalidate(env.CacheStaleTTL),
+			utils.WithNegativeTTL(env.CacheNegativeTTL),
+			utils.WithDistributedLock(env.CacheLockTTL),
+		))
 	departureHandler := api.NewDepartureHandler(departureService, logger)
 
+	geoService := geo.NewService(busStationStore, segmentStore, cachedRouter, appCache, cacheNamespace)
+	geoHandler := api.NewGeoHandler(geoService, logger)
+
+	graphServer, err := graph.NewServer(
+		graph.NewResolver(busStationStore, busLineStore, directionStore, departureStore, departureService),
+		graph.Options{
+			MaxDepth:       env.GraphQLMaxDepth,
+			MaxComplexity:  env.GraphQLMaxComplexity,
+			MaxParallelism: env.GraphQLMaxParallelism,
+		},
+		logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GraphQL schema: %w", err)
+	}
+	graphQLHandler := api.NewGraphQLHandler(graphServer, logger)
+
+	objects, err := newObjectStore(env)
+	if err != nil {
+		return nil, err
+	}
+	imageService := images.NewService(objects, store.NewPostgresStationImageStore(pgDb), busStationStore, logger,
+		images.WithSourceBaseURL(env.ImageSourceBaseURL),
+		images.WithMaxBytes(env.ImageMaxBytes),
+		images.WithFetchTimeout(env.ImageFetchTimeout),
+		images.WithRetryAfter(env.ImageRetryAfter))
+	imageHandler := api.NewImageHandler(imageService, logger)
+
+	healthChecker := health.NewChecker(env.HealthCheckTimeout,
+		health.PostgresCheck(pgDb),
+		health.MigrationCheck(pgDb, migrations.FS),
+		health.CacheCheck(env.CacheBackend, appCache),
+		health.ObjectStoreCheck(env.ImageStoreBackend, objects),
+		health.RoutingCheck(router),
+		health.DataFreshnessCheck(pgDb, env.DataMaxAge),
+	)
+	healthHandler := api.NewHealthHandler(healthChecker, logger)
+
+	cacheAdminService := cacheadmin.NewService(appCache, cacheNamespace, dataVersionStore, busStationStore)
+	translationStore := store.NewPostgresTranslationStore(pgDb)
+	stationAttributesStore := store.NewPostgresStationAttributesStore(pgDb)
+	alertStore := store.NewPostgresAlertStore(pgDb)
+	adminHandler := api.NewAdminHandler(cacheAdminService, dataVersionStore, translationStore, busStationStore, busLineStore, stationAttributesStore, alertStore, imageService, logger)
+
+	notifiers := NewNotifiers(env)
+	subscriptionStore := store.NewPostgresSubscriptionStore(pgDb)
+	reminderScheduler := reminder.NewScheduler(subscriptionStore, departureService, notifiers, env.ReminderInterval, logger)
+
 	return &Application{
 		Logger:            logger,
 		Env:               env,
 		DB:                pgDb,
-		Cache:             rdb,
+		Cache:             appCache,
+		CacheNamespace:    cacheNamespace,
 		BusStationHandler: busStationHandler,
 		BusLineHandler:    busLineHandler,
 		DepartureHandler:  departureHandler,
+		HealthHandler:     healthHandler,
+		AdminHandler:      adminHandler,
+		GeoHandler:        geoHandler,
+		GraphQLHandler:    graphQLHandler,
+		ImageHandler:      imageHandler,
+		BusService:        rpc.NewBusService(busStationStore, busLineStore, departureStore, departureService, logger),
+		HealthChecker:     healthChecker,
+		ReminderScheduler: reminderScheduler,
+		V2: &V2Handlers{
+			BusStationHandler:   v2.NewBusStationHandler(busStationStore, logger),
+			BusLineHandler:      v2.NewBusLineHandler(busLineStore, logger),
+			DepartureHandler:    v2.NewDepartureHandler(departureService, logger),
+			SubscriptionHandler: v2.NewSubscriptionHandler(subscriptionStore, busStationStore, notifiers, logger),
+			AlertHandler:        v2.NewAlertHandler(alertStore, logger),
+		},
 	}, nil
 }
 
-func (app *Application) HealthCheck(w http.ResponseWriter, r *http.Request) error {
-	return api.WriteJSON(w, http.StatusOK, map[string]string{
-		"status": "ok",
+// NewRouter builds the routing engine client selected by ROUTING_PROVIDER.
+func NewRouter(env *config.Environment) (routing.Provider, error) {
+	switch env.RoutingProvider {
+	case routing.ProviderORS:
+		return openrouteservice.NewAPIClient(env.ORSApiKey,
+			openrouteservice.WithBaseURL(env.ORSURL),
+			openrouteservice.WithProfile(env.ORSProfile),
+			openrouteservice.WithTimeout(env.ProviderTimeout)), nil
+	case routing.ProviderOSRM:
+		return osrm.NewAPIClient(env.OSRMURL,
+			osrm.WithProfile(env.OSRMProfile),
+			osrm.WithTimeout(env.ProviderTimeout)), nil
+	case routing.ProviderValhalla:
+		return valhalla.NewAPIClient(env.ValhallaURL,
+			valhalla.WithCosting(env.ValhallaCosting),
+			valhalla.WithTimeout(env.ProviderTimeout)), nil
+	default:
+		return nil, fmt.Errorf("unknown routing provider %q", env.RoutingProvider)
+	}
+}
+
+// NewNotifiers builds the reminder channels. Webhooks are always available,
+// email only when SMTP_HOST is set.
+func NewNotifiers(env *config.Environment) notify.Notifiers {
+	webhookOpts := []notify.WebhookOption{
+		notify.WithWebhookSecret(env.WebhookSecret),
+		notify.WithWebhookTimeout(env.NotifyTimeout),
+	}
+	if env.WebhookPrivate {
+		webhookOpts = append(webhookOpts, notify.WithPrivateTargets())
+	}
+
+	notifiers := notify.Notifiers{
+		notify.ChannelWebhook: notify.NewWebhookNotifier(webhookOpts...),
+	}
+	if env.SMTPHost != "" {
+		notifiers[notify.ChannelEmail] = notify.NewSMTPNotifier(notify.SMTPOptions{
+			Host:     env.SMTPHost,
+			Port:     env.SMTPPort,
+			Username: env.SMTPUsername,
+			Password: env.SMTPPassword,
+			From:     env.SMTPFrom,
+			Timeout:  env.NotifyTimeout,
+		})
+	}
+	return notifiers
+}
+
+// newObjectStore builds the image store selected by IMAGE_STORE_BACKEND.
+func newObjectStore(env *config.Environment) (objectstore.Store, error) {
+	switch env.ImageStoreBackend {
+	case objectstore.BackendFilesystem:
+		return objectstore.NewFilesystemStore(env.ImageStoreDir)
+	default:
+		return nil, fmt.Errorf("unknown image store backend %q", env.ImageStoreBackend)
+	}
+}
+
+// newCache builds the cache backend selected by CACHE_BACKEND. Only backends
+// that involve Redis require it to be reachable at startup.
+func newCache(env *config.Environment) (cache.Cache, error) {
+	switch env.CacheBackend {
+	case cache.BackendMemory:
+		return cache.NewMemoryCache(env.CacheMemoryMaxEntries), nil
+	case cache.BackendRedis, cache.BackendTiered:
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", env.CacheBackend)
+	}
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:         env.RedisAddr,
+		Password:     env.RedisPassword,
+		DB:           0,
+		DialTimeout:  env.RedisTimeout,
+		ReadTimeout:  env.RedisTimeout,
+		WriteTimeout: env.RedisTimeout,
 	})
+
+	if _, err := rdb.Ping(context.Background()).Result(); err != nil {
+		_ = rdb.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	redisCache := cache.NewRedisCache(rdb)
+	if env.CacheBackend == cache.BackendRedis {
+		return redisCache, nil
+	}
+
+	return cache.NewTieredCache(cache.NewMemoryCache(env.CacheMemoryMaxEntries), redisCache, env.CacheL1TTL), nil
 }
@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMiss is returned by Get when the key does not exist or has expired.
+var ErrMiss = errors.New("cache: miss")
+
+// Cache is a byte-oriented key/value store with per-entry TTLs. Callers are
+// expected to treat every error as a miss and fall back to the source.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	// Keys lists keys matching a glob-style pattern (Redis MATCH semantics).
+	Keys(ctx context.Context, pattern string) ([]string, error)
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+// Locker is implemented by backends that can hold a lock shared between
+// service instances. release is safe to call more than once.
+type Locker interface {
+	TryLock(ctx context.Context, key string, ttl time.Duration) (release func(), ok bool, err error)
+}
+
+const (
+	BackendRedis  = "redis"
+	BackendMemory = "memory"
+	BackendTiered = "tiered"
+)
@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// KeyPrefix is prepended to every key the service writes, so patterns can
+// never reach keys owned by other applications sharing the same Redis.
+const KeyPrefix = "mbus:"
+
+type GenerationSource interface {
+	// CurrentVersion returns the data generation and when it started.
+	CurrentVersion(ctx context.Context) (int64, time.Time, error)
+}
+
+// Namespace builds cache keys scoped to the current data generation. Bumping
+// the generation after a reseed or admin write makes every older key
+// unreachable at once; they then simply expire.
+type Namespace struct {
+	source  GenerationSource
+	refresh time.Duration
+	fetches singleflight.Group
+
+	mu         sync.Mutex
+	generation int64
+	updatedAt  time.Time
+	// fetchedAt is the last time the version was read or set, also when
+	// reading failed, so a failing source is retried once per refresh
+	// interval rather than by every caller.
+	fetchedAt time.Time
+}
+
+func NewNamespace(source GenerationSource, refresh time.Duration) *Namespace {
+	return &Namespace{
+		source:  source,
+		refresh: refresh,
+	}
+}
+
+// Key joins parts into a generation-scoped key, e.g. "mbus:g3:timetable:1:2".
+func (n *Namespace) Key(ctx context.Context, parts ...any) string {
+	return n.Prefix(ctx) + joinParts(parts)
+}
+
+// Prefix returns "mbus:g<generation>:" for the current generation.
+func (n *Namespace) Prefix(ctx context.Context) string {
+	return fmt.Sprintf("%sg%d:", KeyPrefix, n.Generation(ctx))
+}
+
+// Generation returns the current data generation.
+func (n *Namespace) Generation(ctx context.Context) int64 {
+	gen, _ := n.Version(ctx)
+	return gen
+}
+
+// Version returns the current data generation and when it started,
+// re-reading them from the source at most once per refresh interval.
+// Concurrent callers share one read, made without holding the lock. If the
+// source fails the last known version is kept until the next interval.
+func (n *Namespace) Version(ctx context.Context) (int64, time.Time) {
+	n.mu.Lock()
+	gen, updatedAt, fetchedAt := n.generation, n.updatedAt, n.fetchedAt
+	n.mu.Unlock()
+
+	if !fetchedAt.IsZero() && time.Since(fetchedAt) < n.refresh {
+		return gen, updatedAt
+	}
+
+	_, _, _ = n.fetches.Do("version", func() (any, error) {
+		started := time.Now()
+		gen, updatedAt, err := n.source.CurrentVersion(ctx)
+
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		switch {
+		case n.fetchedAt.After(started):
+			// SetVersion ran meanwhile and is at least as recent.
+		case err != nil && ctx.Err() != nil:
+			// The caller went away; let the next one retry.
+		case err != nil:
+			n.fetchedAt = time.Now()
+		default:
+			n.generation = gen
+			n.updatedAt = updatedAt
+			n.fetchedAt = time.Now()
+		}
+		return nil, err
+	})
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.generation, n.updatedAt
+}
+
+// SetVersion updates the in-process version immediately, e.g. right after
+// this instance bumped it.
+func (n *Namespace) SetVersion(gen int64, updatedAt time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.generation = gen
+	n.updatedAt = updatedAt
+	n.fetchedAt = time.Now()
+}
+
+// Key builds an unversioned key for values that do not depend on timetable
+// data, such as routing results for fixed coordinates.
+func Key(parts ...any) string {
+	return KeyPrefix + joinParts(parts)
+}
+
+func joinParts(parts []any) string {
+	s := make([]string, len(parts))
+	for i, p := range parts {
+		s[i] = fmt.Sprint(p)
+	}
+	return strings.Join(s, ":")
+}
@@ -0,0 +1,55 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/cache"
+)
+
+// failingSource counts its calls and fails after the first one.
+type failingSource struct {
+	calls atomic.Int32
+}
+
+func (s *failingSource) CurrentVersion(context.Context) (int64, time.Time, error) {
+	if s.calls.Add(1) == 1 {
+		return 3, time.Unix(1700000000, 0), nil
+	}
+	time.Sleep(10 * time.Millisecond)
+	return 0, time.Time{}, errors.New("database is down")
+}
+
+func TestVersionBacksOffWhileSourceFails(t *testing.T) {
+	source := &failingSource{}
+	n := cache.NewNamespace(source, 50*time.Millisecond)
+
+	if gen := n.Generation(context.Background()); gen != 3 {
+		t.Fatalf("Generation() = %d, want 3", gen)
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	// The version is due for a refresh and the source now fails: concurrent
+	// callers share one read and all keep the last known generation.
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if gen := n.Generation(context.Background()); gen != 3 {
+				t.Errorf("Generation() = %d, want the last known 3", gen)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Within the refresh interval after the failure the source is not asked.
+	n.Generation(context.Background())
+	if calls := source.calls.Load(); calls != 2 {
+		t.Errorf("source read %d times, want 2", calls)
+	}
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// TieredCache keeps hot keys in a local L1 cache in front of a shared L2
+// cache. L1 entries live at most l1TTL so instances converge on L2 quickly
+// after a write elsewhere.
+type TieredCache struct {
+	l1    Cache
+	l2    Cache
+	l1TTL time.Duration
+}
+
+func NewTieredCache(l1, l2 Cache, l1TTL time.Duration) *TieredCache {
+	return &TieredCache{
+		l1:    l1,
+		l2:    l2,
+		l1TTL: l1TTL,
+	}
+}
+
+func (c *TieredCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if value, err := c.l1.Get(ctx, key); err == nil {
+		return value, nil
+	}
+
+	value, err := c.l2.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = c.l1.Set(ctx, key, value, c.l1TTL)
+	return value, nil
+}
+
+func (c *TieredCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_ = c.l1.Set(ctx, key, value, c.localTTL(ttl))
+	return c.l2.Set(ctx, key, value, ttl)
+}
+
+func (c *TieredCache) Delete(ctx context.Context, keys ...string) error {
+	return errors.Join(c.l1.Delete(ctx, keys...), c.l2.Delete(ctx, keys...))
+}
+
+// Keys lists keys from L2; L1 only ever holds a subset of them.
+func (c *TieredCache) Keys(ctx context.Context, pattern string) ([]string, error) {
+	return c.l2.Keys(ctx, pattern)
+}
+
+func (c *TieredCache) Ping(ctx context.Context) error {
+	return c.l2.Ping(ctx)
+}
+
+func (c *TieredCache) Close() error {
+	return errors.Join(c.l1.Close(), c.l2.Close())
+}
+
+func (c *TieredCache) localTTL(ttl time.Duration) time.Duration {
+	if ttl > 0 && ttl < c.l1TTL {
+		return ttl
+	}
+	return c.l1TTL
+}
+
+// TryLock delegates to L2, the only tier shared between instances.
+func (c *TieredCache) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	locker, ok := c.l2.(Locker)
+	if !ok {
+		return func() {}, true, nil
+	}
+	return locker.TryLock(ctx, key, ttl)
+}
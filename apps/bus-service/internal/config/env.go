@@ -1,17 +1,87 @@
 package config
 
 import (
+	"time"
+
 	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
 )
 
 type Environment struct {
 	Port          int    `env:"PORT" envDefault:"8080"`
+	GRPCPort      int    `env:"GRPC_PORT" envDefault:"9090"`
 	PostgresURL   string `env:"POSTGRES_URL"`
+	Timezone      string `env:"TIMEZONE" envDefault:"Europe/Ljubljana"`
 	RedisAddr     string `env:"REDIS_ADDR" envDefault:"localhost:6379"`
 	RedisPassword string `env:"REDIS_PASSWORD"`
-	ORSApiKey     string `env:"ORS_API_KEY"`
-	EnableCache   bool   `env:"ENABLE_CACHE" envDefault:"true"`
+
+	RoutingProvider string `env:"ROUTING_PROVIDER" envDefault:"ors"` // ors, osrm, valhalla
+	ORSApiKey       string `env:"ORS_API_KEY"`
+	ORSURL          string `env:"ORS_URL"` // empty uses the public API
+	ORSProfile      string `env:"ORS_PROFILE" envDefault:"driving-car"`
+	OSRMURL         string `env:"OSRM_URL" envDefault:"http://localhost:5000"`
+	OSRMProfile     string `env:"OSRM_PROFILE" envDefault:"driving"`
+	ValhallaURL     string `env:"VALHALLA_URL" envDefault:"http://localhost:8002"`
+	ValhallaCosting string `env:"VALHALLA_COSTING" envDefault:"bus"`
+
+	SegmentBatchSize       int           `env:"SEGMENT_BATCH_SIZE" envDefault:"50"`           // stops per matrix request
+	SegmentRequestInterval time.Duration `env:"SEGMENT_REQUEST_INTERVAL" envDefault:"1500ms"` // pause between matrix requests
+
+	EstimatorMode         string  `env:"ESTIMATOR_MODE" envDefault:"fallback"` // fallback, exclusive, disabled
+	EstimatorDetourFactor float64 `env:"ESTIMATOR_DETOUR_FACTOR" envDefault:"1.3"`
+	EstimatorSpeedKmh     float64 `env:"ESTIMATOR_SPEED_KMH" envDefault:"20"`
+	EnableCache           bool    `env:"ENABLE_CACHE" envDefault:"true"`
+
+	CacheBackend          string        `env:"CACHE_BACKEND" envDefault:"redis"` // redis, memory, tiered
+	CacheMemoryMaxEntries int           `env:"CACHE_MEMORY_MAX_ENTRIES" envDefault:"10000"`
+	CacheL1TTL            time.Duration `env:"CACHE_L1_TTL" envDefault:"30s"`
+	CacheGenerationTTL    time.Duration `env:"CACHE_GENERATION_TTL" envDefault:"5s"`
+	CacheStaleTTL         time.Duration `env:"CACHE_STALE_TTL" envDefault:"1h"`
+	CacheNegativeTTL      time.Duration `env:"CACHE_NEGATIVE_TTL" envDefault:"5m"`
+	CacheLockTTL          time.Duration `env:"CACHE_LOCK_TTL" envDefault:"0s"` // 0 disables the cross-instance lock
+
+	AdminToken string `env:"ADMIN_TOKEN"`
+
+	ImageStoreBackend  string        `env:"IMAGE_STORE_BACKEND" envDefault:"filesystem"`
+	ImageStoreDir      string        `env:"IMAGE_STORE_DIR" envDefault:"storage/images"`
+	ImageSourceBaseURL string        `env:"IMAGE_SOURCE_BASE_URL" envDefault:"https://vozniredi.marprom.si/"` // resolves relative image URLs
+	ImageMaxBytes      int64         `env:"IMAGE_MAX_BYTES" envDefault:"10485760"`
+	ImageFetchTimeout  time.Duration `env:"IMAGE_FETCH_TIMEOUT" envDefault:"10s"`
+	ImageRetryAfter    time.Duration `env:"IMAGE_RETRY_AFTER" envDefault:"24h"` // before a missing image is fetched again
+
+	APIV1DeprecatedAt time.Time `env:"API_V1_DEPRECATED_AT" envDefault:"2026-10-19T00:00:00Z"`
+	APIV1Sunset       time.Time `env:"API_V1_SUNSET" envDefault:"2027-04-30T00:00:00Z"`
+
+	GraphQLMaxDepth       int `env:"GRAPHQL_MAX_DEPTH" envDefault:"15"`
+	GraphQLMaxComplexity  int `env:"GRAPHQL_MAX_COMPLEXITY" envDefault:"1000"` // estimated resolved objects
+	GraphQLMaxParallelism int `env:"GRAPHQL_MAX_PARALLELISM" envDefault:"10"`
+
+	RemindersEnabled bool          `env:"REMINDERS_ENABLED" envDefault:"true"`
+	ReminderInterval time.Duration `env:"REMINDER_INTERVAL" envDefault:"1m"`
+	NotifyTimeout    time.Duration `env:"NOTIFY_TIMEOUT" envDefault:"10s"`
+	WebhookSecret    string        `env:"WEBHOOK_SECRET"` // signs webhook bodies when set
+	SMTPHost         string        `env:"SMTP_HOST"`      // empty disables email reminders
+	SMTPPort         int           `env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername     string        `env:"SMTP_USERNAME"`
+	SMTPPassword     string        `env:"SMTP_PASSWORD"`
+	SMTPFrom         string        `env:"SMTP_FROM" envDefault:"mbus@localhost"`
+	// WebhookPrivate allows loopback and private webhook targets. Only enable
+	// it for a local notify-sink.
+	WebhookPrivate bool `env:"WEBHOOK_ALLOW_PRIVATE_TARGETS" envDefault:"false"`
+
+	RequestTimeout  time.Duration `env:"REQUEST_TIMEOUT" envDefault:"10s"`
+	DBQueryTimeout  time.Duration `env:"DB_QUERY_TIMEOUT" envDefault:"5s"`
+	RedisTimeout    time.Duration `env:"REDIS_TIMEOUT" envDefault:"500ms"`
+	ProviderTimeout time.Duration `env:"PROVIDER_TIMEOUT" envDefault:"5s"`
+
+	HealthCheckTimeout time.Duration `env:"HEALTH_CHECK_TIMEOUT" envDefault:"2s"`
+	DataMaxAge         time.Duration `env:"DATA_MAX_AGE" envDefault:"720h"`
+
+	TracingServiceName string  `env:"TRACING_SERVICE_NAME" envDefault:"bus-service"`
+	TracingExporter    string  `env:"TRACING_EXPORTER" envDefault:"none"` // none, stdout, otlp
+	TracingSampleRatio float64 `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
+	OTLPEndpoint       string  `env:"OTLP_ENDPOINT"`
+	OTLPInsecure       bool    `env:"OTLP_INSECURE" envDefault:"false"`
 }
 
 func LoadEnvironment() (*Environment, error) {
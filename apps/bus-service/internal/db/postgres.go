@@ -4,13 +4,17 @@ import (
 	"database/sql"
 	"fmt"
 	"io/fs"
+	"strconv"
+	"time"
 
-	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/stdlib"
 	"github.com/pressly/goose/v3"
 )
 
 type PostgresDB struct {
-	databaseURL string
+	databaseURL      string
+	statementTimeout time.Duration
 }
 
 func NewPostgresDB(url string) *PostgresDB {
@@ -19,12 +23,25 @@ func NewPostgresDB(url string) *PostgresDB {
 	}
 }
 
+// WithStatementTimeout makes Postgres abort any statement running longer than
+// d, as a server-side safety net for queries whose context is never cancelled.
+func (pg *PostgresDB) WithStatementTimeout(d time.Duration) *PostgresDB {
+	pg.statementTimeout = d
+	return pg
+}
+
 func (pg *PostgresDB) Open() (*sql.DB, error) {
-	db, err := sql.Open("pgx", pg.databaseURL)
+	config, err := pgx.ParseConfig(pg.databaseURL)
 	if err != nil {
-		return nil, fmt.Errorf("db: open %w", err)
+		return nil, fmt.Errorf("db: parse config %w", err)
 	}
 
+	if pg.statementTimeout > 0 {
+		config.RuntimeParams["statement_timeout"] = strconv.FormatInt(pg.statementTimeout.Milliseconds(), 10)
+	}
+
+	db := stdlib.OpenDB(*config)
+
 	if err := db.Ping(); err != nil {
 		return nil, fmt.Errorf("db: open %w", err)
 	}
@@ -1,19 +1,109 @@
 package errs
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+)
+
+// ProblemMediaType is the content type of error responses (RFC 7807).
+const ProblemMediaType = "application/problem+json"
+
+// Codes identify the kind of error independently of its message, so clients
+// can switch on them.
+const (
+	CodeBadRequest         = "bad_request"
+	CodeValidation         = "validation_failed"
+	CodeUnauthorized       = "unauthorized"
+	CodeNotFound           = "not_found"
+	CodeMethodNotAllowed   = "method_not_allowed"
+	CodeConflict           = "conflict"
+	CodeBusStationNotFound = "bus_station_not_found"
+	CodeBusLineNotFound    = "bus_line_not_found"
+	CodeGatewayTimeout     = "gateway_timeout"
+	CodeBadGateway         = "bad_gateway"
+	CodeQueryTooComplex    = "query_too_complex"
+	CodeInternal           = "internal_error"
+)
+
+// Field violation codes.
+const (
+	FieldRequired      = "required"
+	FieldInvalidFormat = "invalid_format"
+	FieldOutOfRange    = "out_of_range"
+	FieldInvalidValue  = "invalid_value"
+	FieldUnknown       = "unknown_field"
+)
+
+// Locations of a violating field.
+const (
+	InQuery = "query"
+	InPath  = "path"
+	InBody  = "body"
+	// InArgument is a GraphQL field argument.
+	InArgument = "argument"
 )
 
+// FieldError describes one invalid request parameter.
+type FieldError struct {
+	Field   string `json:"field"`
+	In      string `json:"in"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+
+	format string
+	args   []any
+}
+
+// NewFieldError builds a violation whose message is format rendered with
+// args. format is the English message and the translation catalog key.
+func NewFieldError(in, field, code, format string, args ...any) FieldError {
+	return FieldError{
+		Field:   field,
+		In:      in,
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+		format:  format,
+		args:    args,
+	}
+}
+
+// APIError is an error returned to clients as an RFC 7807 problem document.
 type APIError struct {
-	StatusCode int    `json:"statusCode"`
-	Message    string `json:"message"`
+	Type       string       `json:"type"`
+	Title      string       `json:"title"`
+	StatusCode int          `json:"status"`
+	Message    string       `json:"detail"`
+	Instance   string       `json:"instance,omitempty"`
+	Code       string       `json:"code"`
+	Errors     []FieldError `json:"errors,omitempty"`
+
+	// LegacyStatusCode and LegacyMessage repeat status and detail under the
+	// keys of the original error body, which v1 clients still read.
+	LegacyStatusCode int    `json:"statusCode"`
+	LegacyMessage    string `json:"message"`
+
+	format string
+	args   []any
 }
 
-func NewAPIError(statusCode int, message string) APIError {
+// NewAPIError builds an error whose message is format rendered with args.
+// format is the English message and the translation catalog key.
+func NewAPIError(statusCode int, code string, format string, args ...any) APIError {
+	message := format
+	if len(args) > 0 {
+		message = fmt.Sprintf(format, args...)
+	}
 	return APIError{
+		Type:       "about:blank",
+		Title:      http.StatusText(statusCode),
 		StatusCode: statusCode,
 		Message:    message,
+		Code:       code,
+		format:     format,
+		args:       args,
 	}
 }
 
@@ -21,18 +111,88 @@ func (e APIError) Error() string {
 	return e.Message
 }
 
+// Localize returns a copy of e with its title and messages in lang.
+func (e APIError) Localize(lang i18n.Lang) APIError {
+	e.Title = i18n.T(lang, http.StatusText(e.StatusCode))
+	if e.format != "" {
+		e.Message = i18n.T(lang, e.format, e.args...)
+	}
+
+	violations := make([]FieldError, len(e.Errors))
+	for i, v := range e.Errors {
+		if v.format != "" {
+			v.Message = i18n.T(lang, v.format, v.args...)
+		}
+		violations[i] = v
+	}
+	if len(violations) > 0 {
+		e.Errors = violations
+	}
+	return e
+}
+
+// Write sends e as a problem document about r, in the language negotiated
+// for r.
+func (e APIError) Write(w http.ResponseWriter, r *http.Request) error {
+	lang := i18n.FromContext(r.Context())
+	e = e.Localize(lang)
+	e.Instance = r.URL.Path
+	e.LegacyStatusCode = e.StatusCode
+	e.LegacyMessage = e.Message
+
+	w.Header().Set("Content-Type", ProblemMediaType)
+	w.Header().Set("Content-Language", string(lang))
+	w.WriteHeader(e.StatusCode)
+	return json.NewEncoder(w).Encode(e)
+}
+
 func InternalServerError() APIError {
-	return NewAPIError(http.StatusInternalServerError, "Internal Server Error")
+	return NewAPIError(http.StatusInternalServerError, CodeInternal, "Internal Server Error")
+}
+
+func GatewayTimeoutError() APIError {
+	return NewAPIError(http.StatusGatewayTimeout, CodeGatewayTimeout, "Request timed out")
+}
+
+// BadGatewayError reports that an upstream server the request depends on
+// failed; the client may retry later.
+func BadGatewayError(message string, args ...any) APIError {
+	return NewAPIError(http.StatusBadGateway, CodeBadGateway, message, args...)
 }
 
-func BadRequestError(message string) APIError {
-	return NewAPIError(http.StatusBadRequest, message)
+func BadRequestError(message string, args ...any) APIError {
+	return NewAPIError(http.StatusBadRequest, CodeBadRequest, message, args...)
 }
 
-func NotFoundError(message string) APIError {
-	return NewAPIError(http.StatusNotFound, message)
+// ValidationError reports every invalid parameter of a request at once.
+func ValidationError(violations []FieldError) APIError {
+	e := NewAPIError(http.StatusBadRequest, CodeValidation, "Request parameters are invalid")
+	e.Errors = violations
+	return e
+}
+
+func UnauthorizedError() APIError {
+	return NewAPIError(http.StatusUnauthorized, CodeUnauthorized, "Unauthorized")
+}
+
+func NotFoundError(message string, args ...any) APIError {
+	return NewAPIError(http.StatusNotFound, CodeNotFound, message, args...)
+}
+
+// ConflictError reports that the request clashes with the current state, e.g.
+// a job that is already running.
+func ConflictError(message string, args ...any) APIError {
+	return NewAPIError(http.StatusConflict, CodeConflict, message, args...)
+}
+
+func MethodNotAllowedError(method string) APIError {
+	return NewAPIError(http.StatusMethodNotAllowed, CodeMethodNotAllowed, "Method %s is not allowed", method)
 }
 
 func BusStationNotFoundError(id int) APIError {
-	return NotFoundError(fmt.Sprintf("Bus station with ID %d does not exist", id))
+	return NewAPIError(http.StatusNotFound, CodeBusStationNotFound, "Bus station with ID %d does not exist", id)
+}
+
+func BusLineNotFoundError(id int) APIError {
+	return NewAPIError(http.StatusNotFound, CodeBusLineNotFound, "Bus line with ID %d does not exist", id)
 }
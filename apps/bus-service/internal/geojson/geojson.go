@@ -0,0 +1,48 @@
+package geojson
+
+// MediaType is the registered content type of GeoJSON documents (RFC 7946).
+const MediaType = "application/geo+json"
+
+const (
+	TypeFeatureCollection = "FeatureCollection"
+	TypeFeature           = "Feature"
+	TypePoint             = "Point"
+	TypeLineString        = "LineString"
+)
+
+type FeatureCollection struct {
+	Type     string    `json:"type" example:"FeatureCollection"`
+	Features []Feature `json:"features"`
+} // @name FeatureCollection
+
+type Feature struct {
+	Type       string         `json:"type" example:"Feature"`
+	ID         any            `json:"id,omitempty" swaggertype:"string"`
+	Geometry   Geometry       `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+} // @name Feature
+
+// Geometry holds a Point ([lon, lat]) or a LineString ([[lon, lat], ...]).
+type Geometry struct {
+	Type        string `json:"type" example:"Point"`
+	Coordinates any    `json:"coordinates" swaggertype:"array,number"`
+} // @name Geometry
+
+func NewFeatureCollection(features []Feature) *FeatureCollection {
+	if features == nil {
+		features = []Feature{}
+	}
+	return &FeatureCollection{Type: TypeFeatureCollection, Features: features}
+}
+
+func NewFeature(id any, geometry Geometry, properties map[string]any) Feature {
+	return Feature{Type: TypeFeature, ID: id, Geometry: geometry, Properties: properties}
+}
+
+func NewPoint(lon, lat float64) Geometry {
+	return Geometry{Type: TypePoint, Coordinates: [2]float64{lon, lat}}
+}
+
+func NewLineString(coordinates [][2]float64) Geometry {
+	return Geometry{Type: TypeLineString, Coordinates: coordinates}
+}
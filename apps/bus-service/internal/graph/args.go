@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+// arguments collects the violations of a field's arguments, like api.Binder
+// does for query parameters.
+type arguments struct {
+	violations []errs.FieldError
+}
+
+func (a *arguments) violation(field, code, format string, args ...any) {
+	a.violations = append(a.violations, errs.NewFieldError(errs.InArgument, field, code, format, args...))
+}
+
+func (a *arguments) err() error {
+	if len(a.violations) == 0 {
+		return nil
+	}
+	return errs.ValidationError(a.violations)
+}
+
+// date returns a date in YYYY-MM-DD format, or the current service date when
+// it is missing, so "now" in the same query refers to the same day.
+func (a *arguments) date(name string, v *string) string {
+	if v == nil || *v == "" {
+		return utils.ServiceDate()
+	}
+	if !utils.ValidateDate(*v) {
+		a.violation(name, errs.FieldInvalidFormat, "%s must be a date in YYYY-MM-DD format", name)
+		return utils.ServiceDate()
+	}
+	return *v
+}
+
+// serviceTime parses a time of day given as HH:MM or "now", like
+// api.Binder.QueryServiceTime.
+func (a *arguments) serviceTime(name string, v *string) *utils.ServiceTime {
+	if v == nil || *v == "" {
+		return nil
+	}
+	if *v == "now" {
+		t := utils.NowServiceTime()
+		return &t
+	}
+
+	t, err := utils.ServiceTimeFromClock(*v)
+	if err != nil {
+		if t, err = utils.ParseServiceTime(*v); err != nil {
+			a.violation(name, errs.FieldInvalidFormat, "%s must be a time in HH:MM format or 'now'", name)
+			return nil
+		}
+	}
+	return &t
+}
+
+func (a *arguments) min(name string, value, min int32) int {
+	if value < min {
+		a.violation(name, errs.FieldOutOfRange, "%s must be at least %d", name, min)
+	}
+	return int(value)
+}
+
+// between bounds list sizes, which also keeps the complexity estimate honest.
+func (a *arguments) between(name string, value, min, max int32) int {
+	if value < min || value > max {
+		a.violation(name, errs.FieldOutOfRange, "%s must be between %d and %d", name, min, max)
+	}
+	return int(value)
+}
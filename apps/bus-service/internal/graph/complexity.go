@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// listSizes estimates the length of list fields that are not bounded by a
+// limit argument. Fields not listed return a single object.
+var listSizes = map[string]int{
+	"busStations": 10,
+	"busLines":    20,
+	"timetable":   50,
+	"codes":       4,
+	"lines":       10,
+	"stations":    40,
+	"directions":  5,
+	"departures":  20,
+}
+
+// complexity estimates the cost of a query as the number of objects it can
+// resolve: every field costs one, and the fields below a list field are
+// counted once per expected item. Introspection is not counted. Queries that
+// do not parse cost nothing; the executor reports their errors.
+func complexity(query, operationName string, variables map[string]any) int {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return 0
+	}
+
+	c := &costCounter{doc: doc, variables: variables, visiting: map[string]bool{}}
+	cost := 0
+	for _, op := range doc.Operations {
+		if operationName != "" && op.Name != operationName {
+			continue
+		}
+		cost = max(cost, c.selectionSet(op.SelectionSet))
+	}
+	return cost
+}
+
+type costCounter struct {
+	doc       *ast.QueryDocument
+	variables map[string]any
+	// visiting guards against fragment cycles, which the executor rejects.
+	visiting map[string]bool
+}
+
+func (c *costCounter) selectionSet(set ast.SelectionSet) int {
+	cost := 0
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(sel.Name, "__") {
+				continue
+			}
+			cost += 1 + c.listSize(sel)*c.selectionSet(sel.SelectionSet)
+		case *ast.InlineFragment:
+			cost += c.selectionSet(sel.SelectionSet)
+		case *ast.FragmentSpread:
+			frag := c.doc.Fragments.ForName(sel.Name)
+			if frag == nil || c.visiting[sel.Name] {
+				continue
+			}
+			c.visiting[sel.Name] = true
+			cost += c.selectionSet(frag.SelectionSet)
+			c.visiting[sel.Name] = false
+		}
+	}
+	return cost
+}
+
+func (c *costCounter) listSize(field *ast.Field) int {
+	size, ok := listSizes[field.Name]
+	if !ok {
+		return 1
+	}
+	if arg := field.Arguments.ForName("limit"); arg != nil {
+		if limit := c.intValue(arg.Value); limit > 0 {
+			return limit
+		}
+	}
+	return size
+}
+
+func (c *costCounter) intValue(v *ast.Value) int {
+	value, err := v.Value(c.variables)
+	if err != nil {
+		return 0
+	}
+	switch n := value.(type) {
+	case int64:
+		return int(n)
+	case float64: // variables decoded from JSON
+		return int(n)
+	default:
+		return 0
+	}
+}
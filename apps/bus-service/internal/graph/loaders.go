@@ -0,0 +1,142 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+)
+
+// departureKey identifies the departures from one station code on one
+// schedule.
+type departureKey struct {
+	code     int
+	schedule store.ScheduleType
+}
+
+// loaders batch the lookups of nested fields into one query per field and
+// level of the result. They are created per request, so their caches never
+// outlive a data generation.
+type loaders struct {
+	station          *dataloader.Loader[int, *store.BusStation]
+	line             *dataloader.Loader[int, *store.BusLine]
+	codesByStation   *dataloader.Loader[int, []store.StationCode]
+	linesByStation   *dataloader.Loader[int, []store.BusLine]
+	stationsByLine   *dataloader.Loader[int, []int]
+	directionsByCode *dataloader.Loader[int, []store.Direction]
+	departuresByCode *dataloader.Loader[departureKey, []store.Departure]
+}
+
+func (r *Resolver) newLoaders(lang i18n.Lang) *loaders {
+	return &loaders{
+		station: dataloader.NewBatchedLoader(batchByID(r.busStationStore.FindBusStationsByIDs,
+			func(s store.BusStation) int { return s.ID })),
+		line: dataloader.NewBatchedLoader(batchByID(
+			func(ctx context.Context, ids []int) ([]store.BusLine, error) {
+				return r.busLineStore.FindBusLinesByIDs(ctx, ids, lang)
+			},
+			func(l store.BusLine) int { return l.ID })),
+		codesByStation: dataloader.NewBatchedLoader(batchGrouped(
+			func(ctx context.Context, ids []int) (map[int][]store.StationCode, error) {
+				codes, err := r.busStationStore.FindStationCodesByStationIDs(ctx, ids)
+				if err != nil {
+					return nil, err
+				}
+				byStation := make(map[int][]store.StationCode, len(ids))
+				for _, c := range codes {
+					byStation[c.StationID] = append(byStation[c.StationID], c)
+				}
+				return byStation, nil
+			})),
+		linesByStation: dataloader.NewBatchedLoader(batchGrouped(
+			func(ctx context.Context, ids []int) (map[int][]store.BusLine, error) {
+				return r.busLineStore.FindBusLinesByStationIDs(ctx, ids, lang)
+			})),
+		stationsByLine:   dataloader.NewBatchedLoader(batchGrouped(r.busStationStore.FindBusStationIDsByLineIDs)),
+		directionsByCode: dataloader.NewBatchedLoader(batchGrouped(r.directionStore.FindDirectionsByStationCodes)),
+		departuresByCode: dataloader.NewBatchedLoader(r.batchDepartures),
+	}
+}
+
+// batchByID adapts a store lookup by ids. Ids without a row resolve to nil.
+func batchByID[V any](find func(context.Context, []int) ([]V, error), id func(V) int) dataloader.BatchFunc[int, *V] {
+	return func(ctx context.Context, keys []int) []*dataloader.Result[*V] {
+		rows, err := find(ctx, keys)
+		if err != nil {
+			return failed[*V](len(keys), err)
+		}
+
+		byID := make(map[int]*V, len(rows))
+		for i := range rows {
+			byID[id(rows[i])] = &rows[i]
+		}
+
+		results := make([]*dataloader.Result[*V], len(keys))
+		for i, key := range keys {
+			results[i] = &dataloader.Result[*V]{Data: byID[key]}
+		}
+		return results
+	}
+}
+
+// batchGrouped adapts a store lookup returning rows grouped by key. Keys
+// without rows resolve to the zero value.
+func batchGrouped[K comparable, V any](find func(context.Context, []K) (map[K]V, error)) dataloader.BatchFunc[K, V] {
+	return func(ctx context.Context, keys []K) []*dataloader.Result[V] {
+		grouped, err := find(ctx, keys)
+		if err != nil {
+			return failed[V](len(keys), err)
+		}
+
+		results := make([]*dataloader.Result[V], len(keys))
+		for i, key := range keys {
+			results[i] = &dataloader.Result[V]{Data: grouped[key]}
+		}
+		return results
+	}
+}
+
+// batchDepartures loads departures with one query per schedule in the batch,
+// which in practice is one query.
+func (r *Resolver) batchDepartures(ctx context.Context, keys []departureKey) []*dataloader.Result[[]store.Departure] {
+	codes := make(map[store.ScheduleType][]int)
+	for _, key := range keys {
+		codes[key.schedule] = append(codes[key.schedule], key.code)
+	}
+
+	departures := make(map[departureKey][]store.Departure, len(keys))
+	for schedule, scheduleCodes := range codes {
+		byCode, err := r.departureStore.FindDeparturesByStationCodes(ctx, scheduleCodes, schedule)
+		if err != nil {
+			return failed[[]store.Departure](len(keys), err)
+		}
+		for code, deps := range byCode {
+			departures[departureKey{code: code, schedule: schedule}] = deps
+		}
+	}
+
+	results := make([]*dataloader.Result[[]store.Departure], len(keys))
+	for i, key := range keys {
+		results[i] = &dataloader.Result[[]store.Departure]{Data: departures[key]}
+	}
+	return results
+}
+
+func failed[V any](n int, err error) []*dataloader.Result[V] {
+	results := make([]*dataloader.Result[V], n)
+	for i := range results {
+		results[i] = &dataloader.Result[V]{Error: err}
+	}
+	return results
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
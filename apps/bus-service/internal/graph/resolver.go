@@ -0,0 +1,180 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/pagination"
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/departure"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+)
+
+// maxListLimit bounds the limit argument of list fields.
+const maxListLimit = 100
+
+// Resolver resolves the Query type.
+type Resolver struct {
+	busStationStore  store.BusStationStore
+	busLineStore     store.BusLineStore
+	directionStore   store.DirectionStore
+	departureStore   store.DepartureStore
+	departureService *departure.Service
+}
+
+func NewResolver(
+	busStationStore store.BusStationStore,
+	busLineStore store.BusLineStore,
+	directionStore store.DirectionStore,
+	departureStore store.DepartureStore,
+	departureService *departure.Service,
+) *Resolver {
+	return &Resolver{
+		busStationStore:  busStationStore,
+		busLineStore:     busLineStore,
+		directionStore:   directionStore,
+		departureStore:   departureStore,
+		departureService: departureService,
+	}
+}
+
+func (r *Resolver) BusStation(ctx context.Context, args struct{ ID int32 }) (*busStationResolver, error) {
+	station, err := loadersFrom(ctx).station.Load(ctx, int(args.ID))()
+	if err != nil || station == nil {
+		return nil, err
+	}
+	return &busStationResolver{s: *station}, nil
+}
+
+// stationAttributes maps StationAttribute enum values to attribute names.
+var stationAttributes = map[string]string{
+	"WHEELCHAIR_ACCESSIBLE": store.AttrWheelchairAccessible,
+	"SHELTER":               store.AttrShelter,
+	"BENCH":                 store.AttrBench,
+	"REALTIME_DISPLAY":      store.AttrRealtimeDisplay,
+	"TICKET_MACHINE":        store.AttrTicketMachine,
+	"TACTILE_PAVING":        store.AttrTactilePaving,
+}
+
+func (r *Resolver) BusStations(ctx context.Context, args struct {
+	Name   *string
+	Line   *string
+	Has    *[]string
+	Limit  int32
+	Offset int32
+}) ([]*busStationResolver, error) {
+	a := &arguments{}
+	page := pagination.Request{
+		Limit:  a.between("limit", args.Limit, 1, maxListLimit),
+		Offset: a.min("offset", args.Offset, 0),
+		Sort:   pagination.Sort{Field: pagination.SortName},
+	}
+	if err := a.err(); err != nil {
+		return nil, err
+	}
+
+	opts := &store.BusStationFilterOptions{
+		Name: deref(args.Name),
+		Line: deref(args.Line),
+	}
+	if args.Has != nil {
+		for _, attr := range *args.Has {
+			opts.Attributes = append(opts.Attributes, stationAttributes[attr])
+		}
+	}
+
+	stations, err := r.busStationStore.ListBusStations(ctx, opts, page)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*busStationResolver, len(stations.Items))
+	for i, s := range stations.Items {
+		resolvers[i] = &busStationResolver{s: s}
+	}
+	return resolvers, nil
+}
+
+func (r *Resolver) BusLines(ctx context.Context, args struct {
+	Name    *string
+	Station *int32
+	Limit   int32
+	Offset  int32
+}) ([]*busLineResolver, error) {
+	a := &arguments{}
+	page := pagination.Request{
+		Limit:  a.between("limit", args.Limit, 1, maxListLimit),
+		Offset: a.min("offset", args.Offset, 0),
+		Sort:   pagination.Sort{Field: pagination.SortName},
+	}
+	if err := a.err(); err != nil {
+		return nil, err
+	}
+
+	opts := &store.BusLineFilterOptions{
+		Name: deref(args.Name),
+		Lang: i18n.FromContext(ctx),
+	}
+	if args.Station != nil {
+		opts.StationID = int(*args.Station)
+	}
+
+	lines, err := r.busLineStore.ListBusLines(ctx, opts, page)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*busLineResolver, len(lines.Items))
+	for i, l := range lines.Items {
+		resolvers[i] = &busLineResolver{l: l}
+	}
+	return resolvers, nil
+}
+
+func (r *Resolver) Timetable(ctx context.Context, args struct {
+	From      int32
+	To        int32
+	Date      *string
+	After     *string
+	Before    *string
+	Lines     *[]string
+	Direction *string
+	Limit     *int32
+	StepFree  bool
+}) ([]*timetableRowResolver, error) {
+	a := &arguments{}
+	date := a.date("date", args.Date)
+	filter := departure.Filter{
+		Direction: deref(args.Direction),
+		After:     a.serviceTime("after", args.After),
+		Before:    a.serviceTime("before", args.Before),
+		StepFree:  args.StepFree,
+	}
+	if args.Lines != nil {
+		filter.Lines = *args.Lines
+	}
+	if args.Limit != nil {
+		filter.Limit = a.min("limit", *args.Limit, 0)
+	}
+	if err := a.err(); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.departureService.GenerateTimetable(ctx, int(args.From), int(args.To), date, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*timetableRowResolver, len(rows))
+	for i, row := range rows {
+		resolvers[i] = &timetableRowResolver{row: row, date: date}
+	}
+	return resolvers, nil
+}
+
+func deref[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+	return *v
+}
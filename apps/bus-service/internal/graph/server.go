@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+type Options struct {
+	// MaxDepth bounds the nesting of selections, 0 disables the check.
+	MaxDepth int
+	// MaxComplexity bounds the estimated number of resolved objects, 0
+	// disables the check.
+	MaxComplexity int
+	// MaxParallelism bounds the fields resolved concurrently per request.
+	MaxParallelism int
+}
+
+// Request is a GraphQL request as sent over HTTP.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	// Extensions are accepted for client compatibility and ignored.
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// Server executes queries against the schema.
+type Server struct {
+	schema        *graphql.Schema
+	resolver      *Resolver
+	maxComplexity int
+	logger        *slog.Logger
+}
+
+func NewServer(resolver *Resolver, opts Options, logger *slog.Logger) (*Server, error) {
+	schemaOpts := []graphql.SchemaOpt{graphql.UseStringDescriptions()}
+	if opts.MaxDepth > 0 {
+		schemaOpts = append(schemaOpts, graphql.MaxDepth(opts.MaxDepth))
+	}
+	if opts.MaxParallelism > 0 {
+		schemaOpts = append(schemaOpts, graphql.MaxParallelism(opts.MaxParallelism))
+	}
+
+	schema, err := graphql.ParseSchema(schemaSDL, resolver, schemaOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		schema:        schema,
+		resolver:      resolver,
+		maxComplexity: opts.MaxComplexity,
+		logger:        logger.With(slog.String("component", "graph.Server")),
+	}, nil
+}
+
+// Exec runs req with fresh loaders. Queries above the complexity limit are
+// rejected before anything is resolved. Errors are returned in the language
+// negotiated for ctx, and internal errors are logged and masked.
+func (s *Server) Exec(ctx context.Context, req Request) *graphql.Response {
+	lang := i18n.FromContext(ctx)
+
+	if s.maxComplexity > 0 {
+		if cost := complexity(req.Query, req.OperationName, req.Variables); cost > s.maxComplexity {
+			return &graphql.Response{Errors: []*gqlerrors.QueryError{{
+				Message: i18n.T(lang, "Query complexity %d exceeds the limit of %d", cost, s.maxComplexity),
+				Extensions: map[string]any{
+					"code":       errs.CodeQueryTooComplex,
+					"complexity": cost,
+					"limit":      s.maxComplexity,
+				},
+			}}}
+		}
+	}
+
+	ctx = withLoaders(ctx, s.resolver.newLoaders(lang))
+	resp := s.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	for _, qErr := range resp.Errors {
+		s.localize(qErr, lang)
+	}
+	return resp
+}
+
+// localize replaces the message of resolver errors the way api.MakeHandlerFunc
+// does for problem documents. Syntax and validation errors are left as they
+// are.
+func (s *Server) localize(qErr *gqlerrors.QueryError, lang i18n.Lang) {
+	if qErr.ResolverError == nil {
+		return
+	}
+
+	var apiErr errs.APIError
+	switch {
+	case errors.As(qErr.ResolverError, &apiErr):
+	case errors.Is(qErr.ResolverError, context.DeadlineExceeded):
+		apiErr = errs.GatewayTimeoutError()
+	default:
+		s.logger.Error(qErr.ResolverError.Error(), slog.Any("path", qErr.Path))
+		apiErr = errs.InternalServerError()
+	}
+
+	apiErr = apiErr.Localize(lang)
+	qErr.Message = apiErr.Message
+	qErr.Extensions = map[string]any{"code": apiErr.Code}
+	if len(apiErr.Errors) > 0 {
+		qErr.Extensions["errors"] = apiErr.Errors
+	}
+}
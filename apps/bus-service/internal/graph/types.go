@@ -0,0 +1,370 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/departure"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+type busStationResolver struct {
+	s store.BusStation
+}
+
+func (r *busStationResolver) ID() int32    { return int32(r.s.ID) }
+func (r *busStationResolver) Name() string { return r.s.Name }
+func (r *busStationResolver) Lat() float64 { return r.s.Lat }
+func (r *busStationResolver) Lon() float64 { return r.s.Lon }
+func (r *busStationResolver) ImageURL(args struct{ Size *string }) *string {
+	url := r.s.ImageURL
+	if args.Size != nil {
+		url = r.s.ImageSizeURL(strings.ToLower(*args.Size))
+	}
+	if url == "" {
+		return nil
+	}
+	return &url
+}
+
+func (r *busStationResolver) Attributes() *stationAttributesResolver {
+	return &stationAttributesResolver{a: r.s.Attributes}
+}
+
+func (r *busStationResolver) Codes(ctx context.Context) ([]*stationCodeResolver, error) {
+	codes, err := loadersFrom(ctx).codesByStation.Load(ctx, r.s.ID)()
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*stationCodeResolver, len(codes))
+	for i, c := range codes {
+		resolvers[i] = &stationCodeResolver{c: c}
+	}
+	return resolvers, nil
+}
+
+func (r *busStationResolver) Lines(ctx context.Context) ([]*busLineResolver, error) {
+	lines, err := loadersFrom(ctx).linesByStation.Load(ctx, r.s.ID)()
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*busLineResolver, len(lines))
+	for i, l := range lines {
+		resolvers[i] = &busLineResolver{l: l}
+	}
+	return resolvers, nil
+}
+
+func (r *busStationResolver) Directions(ctx context.Context) ([]*directionResolver, error) {
+	codes, err := r.codes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return loadDirections(ctx, codes)
+}
+
+func (r *busStationResolver) Departures(ctx context.Context, args departuresArgs) ([]*departureResolver, error) {
+	codes, err := r.codes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return loadDepartures(ctx, codes, args)
+}
+
+// codes loads the stop codes of the station. Stations from list queries do
+// not carry them, so they always come from the codesByStation loader.
+func (r *busStationResolver) codes(ctx context.Context) ([]int, error) {
+	stationCodes, err := loadersFrom(ctx).codesByStation.Load(ctx, r.s.ID)()
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]int, len(stationCodes))
+	for i, c := range stationCodes {
+		codes[i] = c.Code
+	}
+	return codes, nil
+}
+
+type stationAttributesResolver struct {
+	a store.StationAttributes
+}
+
+func (r *stationAttributesResolver) WheelchairAccessible() *bool { return r.a.WheelchairAccessible }
+func (r *stationAttributesResolver) Shelter() *bool              { return r.a.Shelter }
+func (r *stationAttributesResolver) Bench() *bool                { return r.a.Bench }
+func (r *stationAttributesResolver) RealtimeDisplay() *bool      { return r.a.RealtimeDisplay }
+func (r *stationAttributesResolver) TicketMachine() *bool        { return r.a.TicketMachine }
+func (r *stationAttributesResolver) TactilePaving() *bool        { return r.a.TactilePaving }
+
+type stationCodeResolver struct {
+	c store.StationCode
+}
+
+func (r *stationCodeResolver) ID() int32        { return int32(r.c.ID) }
+func (r *stationCodeResolver) Code() int32      { return int32(r.c.Code) }
+func (r *stationCodeResolver) Platform() string { return r.c.Platform }
+func (r *stationCodeResolver) Lat() float64     { return r.c.Lat }
+func (r *stationCodeResolver) Lon() float64     { return r.c.Lon }
+
+func (r *stationCodeResolver) Station(ctx context.Context) (*busStationResolver, error) {
+	station, err := loadersFrom(ctx).station.Load(ctx, r.c.StationID)()
+	if err != nil {
+		return nil, err
+	}
+	if station == nil {
+		return nil, errors.New("station of code not found")
+	}
+	return &busStationResolver{s: *station}, nil
+}
+
+func (r *stationCodeResolver) Directions(ctx context.Context) ([]*directionResolver, error) {
+	return loadDirections(ctx, []int{r.c.Code})
+}
+
+func (r *stationCodeResolver) Departures(ctx context.Context, args departuresArgs) ([]*departureResolver, error) {
+	return loadDepartures(ctx, []int{r.c.Code}, args)
+}
+
+type busLineResolver struct {
+	l store.BusLine
+}
+
+func (r *busLineResolver) ID() int32    { return int32(r.l.ID) }
+func (r *busLineResolver) Name() string { return r.l.Name }
+func (r *busLineResolver) Description() *string {
+	if r.l.Description == "" {
+		return nil
+	}
+	return &r.l.Description
+}
+
+func (r *busLineResolver) Category() string   { return strings.ToUpper(r.l.Category) }
+func (r *busLineResolver) Color() *string     { return optional(r.l.Color) }
+func (r *busLineResolver) TextColor() *string { return optional(r.l.TextColor) }
+func (r *busLineResolver) LongName() *string  { return optional(r.l.LongName) }
+func (r *busLineResolver) Operator() *string  { return optional(r.l.Operator) }
+func (r *busLineResolver) SortOrder() int32   { return int32(r.l.SortOrder) }
+
+func (r *busLineResolver) Stations(ctx context.Context) ([]*busStationResolver, error) {
+	l := loadersFrom(ctx)
+	ids, err := l.stationsByLine.Load(ctx, r.l.ID)()
+	if err != nil {
+		return nil, err
+	}
+
+	stations, errs := l.station.LoadMany(ctx, ids)()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*busStationResolver, 0, len(stations))
+	for _, s := range stations {
+		if s != nil {
+			resolvers = append(resolvers, &busStationResolver{s: *s})
+		}
+	}
+	return resolvers, nil
+}
+
+type directionResolver struct {
+	d store.Direction
+}
+
+func (r *directionResolver) ID() int32    { return int32(r.d.ID) }
+func (r *directionResolver) Name() string { return r.d.Name }
+
+// loadDirections returns the distinct directions departing from codes.
+func loadDirections(ctx context.Context, codes []int) ([]*directionResolver, error) {
+	byCode, errs := loadersFrom(ctx).directionsByCode.LoadMany(ctx, codes)()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]bool)
+	resolvers := make([]*directionResolver, 0)
+	for _, directions := range byCode {
+		for _, d := range directions {
+			if !seen[d.ID] {
+				seen[d.ID] = true
+				resolvers = append(resolvers, &directionResolver{d: d})
+			}
+		}
+	}
+	return resolvers, nil
+}
+
+type departuresArgs struct {
+	Date  *string
+	Limit int32
+}
+
+// loadDepartures returns the departures from codes on a service date, merged
+// in departure order.
+func loadDepartures(ctx context.Context, codes []int, args departuresArgs) ([]*departureResolver, error) {
+	a := &arguments{}
+	date := a.date("date", args.Date)
+	limit := a.between("limit", args.Limit, 1, maxListLimit)
+	if err := a.err(); err != nil {
+		return nil, err
+	}
+
+	schedule := store.ScheduleTyp(date)
+	keys := make([]departureKey, len(codes))
+	for i, code := range codes {
+		keys[i] = departureKey{code: code, schedule: schedule}
+	}
+
+	byCode, errs := loadersFrom(ctx).departuresByCode.LoadMany(ctx, keys)()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	departures := slices.Concat(byCode...)
+	slices.SortStableFunc(departures, func(a, b store.Departure) int {
+		return int(a.DepartureTime - b.DepartureTime)
+	})
+	if len(departures) > limit {
+		departures = departures[:limit]
+	}
+
+	resolvers := make([]*departureResolver, len(departures))
+	for i, d := range departures {
+		resolvers[i] = &departureResolver{d: d, date: date}
+	}
+	return resolvers, nil
+}
+
+type departureResolver struct {
+	d    store.Departure
+	date string
+}
+
+func (r *departureResolver) ID() int32           { return int32(r.d.ID) }
+func (r *departureResolver) Direction() string   { return r.d.Direction }
+func (r *departureResolver) DepartureAt() string { return r.d.DepartureTime.Clock() }
+func (r *departureResolver) DayOffset() int32    { return int32(r.d.DepartureTime.DayOffset()) }
+func (r *departureResolver) Schedule() string    { return strings.ToUpper(string(r.d.ScheduleType)) }
+
+func (r *departureResolver) Line(ctx context.Context) (*busLineResolver, error) {
+	line, err := loadersFrom(ctx).line.Load(ctx, r.d.LineID)()
+	if err != nil {
+		return nil, err
+	}
+	if line == nil {
+		return &busLineResolver{l: r.d.Line}, nil
+	}
+	return &busLineResolver{l: *line}, nil
+}
+
+func (r *departureResolver) DepartureTime() (string, error) {
+	t, err := utils.ServiceDateTime(r.date, r.d.DepartureTime)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(time.RFC3339), nil
+}
+
+type timetableRowResolver struct {
+	row  departure.TimetableRow
+	date string
+}
+
+func (r *timetableRowResolver) ID() int32                 { return int32(r.row.ID) }
+func (r *timetableRowResolver) Line() string              { return r.row.Line }
+func (r *timetableRowResolver) Direction() string         { return r.row.Direction }
+func (r *timetableRowResolver) DepartureAt() string       { return r.row.DepartureAt }
+func (r *timetableRowResolver) DepartureDayOffset() int32 { return int32(r.row.DepartureDayOffset) }
+func (r *timetableRowResolver) ArriveAt() string          { return r.row.ArriveAt }
+func (r *timetableRowResolver) ArriveDayOffset() int32    { return int32(r.row.ArriveDayOffset) }
+func (r *timetableRowResolver) Estimated() bool           { return r.row.Estimated }
+func (r *timetableRowResolver) StepFree() bool            { return r.row.StepFree }
+func (r *timetableRowResolver) DistanceMeters() int32 {
+	return int32(math.Round(r.row.Distance * 1000))
+}
+
+func (r *timetableRowResolver) From(ctx context.Context) (*busStationResolver, error) {
+	return loadStationRef(ctx, r.row.FromStation)
+}
+
+func (r *timetableRowResolver) To(ctx context.Context) (*busStationResolver, error) {
+	return loadStationRef(ctx, r.row.ToStation)
+}
+
+// LineDetails loads the line of the row. Timetables cached before line
+// metadata was tracked have none.
+func (r *timetableRowResolver) LineDetails(ctx context.Context) (*busLineResolver, error) {
+	if r.row.LineDetails == nil {
+		return nil, nil
+	}
+	line, err := loadersFrom(ctx).line.Load(ctx, r.row.LineDetails.ID)()
+	if err != nil || line == nil {
+		return nil, err
+	}
+	return &busLineResolver{l: *line}, nil
+}
+
+func (r *timetableRowResolver) FromStop() *stationCodeResolver {
+	return stopRef(r.row.FromStation)
+}
+
+func (r *timetableRowResolver) ToStop() *stationCodeResolver {
+	return stopRef(r.row.ToStation)
+}
+
+func (r *timetableRowResolver) DepartureTime() (string, error) {
+	t, err := utils.ServiceDateTime(r.date, r.row.GetDepartureAt())
+	if err != nil {
+		return "", err
+	}
+	return t.Format(time.RFC3339), nil
+}
+
+func (r *timetableRowResolver) ArrivalTime() (string, error) {
+	t, err := utils.ServiceDateTime(r.date, r.row.GetArriveAt())
+	if err != nil {
+		return "", err
+	}
+	return t.Format(time.RFC3339), nil
+}
+
+func (r *timetableRowResolver) Duration() string {
+	return utils.ISODuration(time.Duration(r.row.GetArriveAt()-r.row.GetDepartureAt()) * time.Minute)
+}
+
+// stopRef resolves the stop of a timetable station. Timetables cached
+// before stops were tracked have none.
+func stopRef(ref departure.Station) *stationCodeResolver {
+	if ref.Stop == nil {
+		return nil
+	}
+	return &stationCodeResolver{c: *ref.Stop}
+}
+
+// loadStationRef resolves a timetable station. Cached timetables may refer to
+// stations removed since, which keep the name they had.
+func loadStationRef(ctx context.Context, ref departure.Station) (*busStationResolver, error) {
+	station, err := loadersFrom(ctx).station.Load(ctx, ref.ID)()
+	if err != nil {
+		return nil, err
+	}
+	if station == nil {
+		return &busStationResolver{s: store.BusStation{ID: ref.ID, Name: ref.Name}}, nil
+	}
+	return &busStationResolver{s: *station}, nil
+}
+
+// optional maps empty strings to null.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,219 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/cache"
+	"github.com/perkzen/mbus/apps/bus-service/internal/objectstore"
+	"github.com/perkzen/mbus/apps/bus-service/internal/provider/openrouteservice"
+	"github.com/perkzen/mbus/apps/bus-service/internal/provider/routing"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+// quotaDegradedRatio is the share of remaining quota below which the
+// routing check reports degraded.
+const quotaDegradedRatio = 0.05
+
+func PostgresCheck(db *sql.DB) Check {
+	return Check{
+		Name:     "postgres",
+		Critical: true,
+		Run: func(ctx context.Context) Result {
+			if err := db.PingContext(ctx); err != nil {
+				return Result{Status: StatusDown, Message: err.Error()}
+			}
+
+			stats := db.Stats()
+			return Result{
+				Status: StatusOK,
+				Details: map[string]any{
+					"openConnections": stats.OpenConnections,
+					"inUse":           stats.InUse,
+				},
+			}
+		},
+	}
+}
+
+// MigrationCheck compares the applied goose version with the newest
+// migration embedded in the binary.
+func MigrationCheck(db *sql.DB, migrationsFS fs.FS) Check {
+	return Check{
+		Name:     "migrations",
+		Critical: true,
+		Run: func(ctx context.Context) Result {
+			expected, err := latestMigrationVersion(migrationsFS)
+			if err != nil {
+				return Result{Status: StatusDown, Message: err.Error()}
+			}
+
+			var current sql.NullInt64
+			err = db.QueryRowContext(ctx,
+				`SELECT MAX(version_id) FROM goose_db_version WHERE is_applied`,
+			).Scan(&current)
+			if err != nil {
+				return Result{Status: StatusDown, Message: err.Error()}
+			}
+
+			details := map[string]any{
+				"current":  current.Int64,
+				"expected": expected,
+			}
+
+			if current.Int64 < expected {
+				return Result{Status: StatusDown, Message: "pending migrations", Details: details}
+			}
+			return Result{Status: StatusOK, Details: details}
+		},
+	}
+}
+
+// CacheCheck pings the configured cache backend. Every cache error is treated
+// as a miss, so an unreachable cache slows the service down but does not
+// make it unready.
+func CacheCheck(backend string, c cache.Cache) Check {
+	return Check{
+		Name:     "cache",
+		Critical: false,
+		Run: func(ctx context.Context) Result {
+			details := map[string]any{"backend": backend}
+			if err := c.Ping(ctx); err != nil {
+				return Result{Status: StatusDegraded, Message: err.Error(), Details: details}
+			}
+			return Result{Status: StatusOK, Details: details}
+		},
+	}
+}
+
+// ObjectStoreCheck pings the store holding station images. Without it only
+// the images degrade, so it does not make the service unready.
+func ObjectStoreCheck(backend string, s objectstore.Store) Check {
+	return Check{
+		Name:     "images",
+		Critical: false,
+		Run: func(ctx context.Context) Result {
+			details := map[string]any{"backend": backend}
+			if err := s.Ping(ctx); err != nil {
+				return Result{Status: StatusDegraded, Message: err.Error(), Details: details}
+			}
+			return Result{Status: StatusOK, Details: details}
+		},
+	}
+}
+
+// quotaReporter is implemented by routing providers with a request quota.
+type quotaReporter interface {
+	Quota() *openrouteservice.Quota
+}
+
+// lastErrorReporter is implemented by routing providers that remember
+// whether their last request failed.
+type lastErrorReporter interface {
+	LastError() error
+}
+
+// RoutingCheck reports reachability of the routing engine and, for hosted
+// engines, the remaining quota. Routing problems degrade the service but do
+// not make it unready.
+func RoutingCheck(provider routing.Provider) Check {
+	return Check{
+		Name:     "routing",
+		Critical: false,
+		Run: func(ctx context.Context) Result {
+			details := map[string]any{"provider": provider.Name()}
+
+			if err := provider.Ping(ctx); err != nil {
+				return Result{Status: StatusDegraded, Message: err.Error(), Details: details}
+			}
+
+			res := Result{Status: StatusOK, Details: details}
+
+			if qr, ok := provider.(quotaReporter); ok {
+				if quota := qr.Quota(); quota != nil {
+					res.Details["quota"] = quota
+					if quota.Limit > 0 && float64(quota.Remaining)/float64(quota.Limit) < quotaDegradedRatio {
+						res.Status = StatusDegraded
+						res.Message = "routing quota nearly exhausted"
+					}
+				}
+			}
+
+			if er, ok := provider.(lastErrorReporter); ok {
+				if err := er.LastError(); err != nil {
+					res.Status = StatusDegraded
+					res.Message = "last routing request failed: " + err.Error()
+				}
+			}
+
+			return res
+		},
+	}
+}
+
+// DataFreshnessCheck reports how much timetable data is loaded and when it
+// was last seeded. Without any stations the service cannot answer requests.
+func DataFreshnessCheck(db *sql.DB, maxAge time.Duration) Check {
+	return Check{
+		Name:     "data",
+		Critical: true,
+		Run: func(ctx context.Context) Result {
+			var stations, departures int
+			var lastSeed sql.NullTime
+
+			err := db.QueryRowContext(ctx, `
+				SELECT
+					(SELECT COUNT(*) FROM bus_stations),
+					(SELECT COUNT(*) FROM departures),
+					(SELECT MAX(created_at) FROM departures)
+			`).Scan(&stations, &departures, &lastSeed)
+			if err != nil {
+				return Result{Status: StatusDown, Message: err.Error()}
+			}
+
+			details := map[string]any{
+				"stationCount":   stations,
+				"departureCount": departures,
+			}
+			if lastSeed.Valid {
+				details["lastSeededAt"] = utils.InLocation(lastSeed.Time)
+			}
+
+			switch {
+			case stations == 0 || departures == 0:
+				return Result{Status: StatusDown, Message: "no timetable data loaded", Details: details}
+			case maxAge > 0 && lastSeed.Valid && time.Since(lastSeed.Time) > maxAge:
+				return Result{Status: StatusDegraded, Message: "timetable data is stale", Details: details}
+			}
+
+			return Result{Status: StatusOK, Details: details}
+		},
+	}
+}
+
+func latestMigrationVersion(migrationsFS fs.FS) (int64, error) {
+	files, err := fs.Glob(migrationsFS, "*.sql")
+	if err != nil {
+		return 0, fmt.Errorf("list migrations: %w", err)
+	}
+
+	var latest int64
+	for _, f := range files {
+		prefix, _, ok := strings.Cut(f, "_")
+		if !ok {
+			continue
+		}
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+		latest = max(latest, version)
+	}
+
+	return latest, nil
+}
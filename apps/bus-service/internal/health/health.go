@@ -0,0 +1,117 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+type Status string
+
+const (
+	StatusOK       Status = "ok"
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+type Result struct {
+	Status  Status         `json:"status"`
+	Message string         `json:"message,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+type CheckResult struct {
+	Name      string         `json:"name"`
+	Status    Status         `json:"status"`
+	Critical  bool           `json:"critical"`
+	LatencyMs float64        `json:"latencyMs"`
+	Message   string         `json:"message,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+} // @name HealthCheckResult
+
+type Report struct {
+	Status    Status        `json:"status"`
+	CheckedAt time.Time     `json:"checkedAt"`
+	Checks    []CheckResult `json:"checks"`
+} // @name HealthReport
+
+// Check is a single dependency probe. A critical check that reports
+// StatusDown makes the whole report down; everything else only degrades it.
+type Check struct {
+	Name     string
+	Critical bool
+	Run      func(ctx context.Context) Result
+}
+
+type Checker struct {
+	checks  []Check
+	timeout time.Duration
+}
+
+func NewChecker(timeout time.Duration, checks ...Check) *Checker {
+	return &Checker{
+		checks:  checks,
+		timeout: timeout,
+	}
+}
+
+// Run executes all checks concurrently, each bounded by the checker timeout.
+func (c *Checker) Run(ctx context.Context) Report {
+	results := make([]CheckResult, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = c.runCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	return Report{
+		Status:    aggregate(results),
+		CheckedAt: utils.Now(),
+		Checks:    results,
+	}
+}
+
+func (c *Checker) runCheck(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan Result, 1)
+	go func() { done <- check.Run(ctx) }()
+
+	var res Result
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		res = Result{Status: StatusDown, Message: "check timed out"}
+	}
+
+	return CheckResult{
+		Name:      check.Name,
+		Status:    res.Status,
+		Critical:  check.Critical,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		Message:   res.Message,
+		Details:   res.Details,
+	}
+}
+
+func aggregate(results []CheckResult) Status {
+	status := StatusOK
+	for _, r := range results {
+		switch {
+		case r.Status == StatusDown && r.Critical:
+			return StatusDown
+		case r.Status != StatusOK:
+			status = StatusDegraded
+		}
+	}
+	return status
+}
@@ -0,0 +1,90 @@
+package i18n
+
+// catalog maps English messages to their translations. Keys must match the
+// format strings used in the code exactly, including verbs.
+var catalog = map[Lang]map[string]string{
+	Slovenian: {
+		// Problem titles (HTTP status texts)
+		"Bad Request":           "Neveljavna zahteva",
+		"Unauthorized":          "Nepooblaščen dostop",
+		"Not Found":             "Ni najdeno",
+		"Method Not Allowed":    "Metoda ni dovoljena",
+		"Conflict":              "Konflikt",
+		"Internal Server Error": "Notranja napaka strežnika",
+		"Bad Gateway":           "Napaka prehoda",
+		"Gateway Timeout":       "Časovna omejitev prehoda",
+
+		// errs
+		"Request timed out":                                          "Zahteva je potekla",
+		"Request parameters are invalid":                             "Parametri zahteve so neveljavni",
+		"Method %s is not allowed":                                   "Metoda %s ni dovoljena",
+		"Bus station with ID %d does not exist":                      "Avtobusna postaja z ID %d ne obstaja",
+		"Bus line with ID %d does not exist":                         "Avtobusna linija z ID %d ne obstaja",
+		"Either 'station' or 'line' is required":                     "Obvezen je parameter 'station' ali 'line'",
+		"Stop code %d does not exist":                                "Postajališče s kodo %d ne obstaja",
+		"Either 'id' or 'code' is required":                          "Obvezen je parameter 'id' ali 'code'",
+		"Translation does not exist":                                 "Prevod ne obstaja",
+		"Query complexity %d exceeds the limit of %d":                "Zahtevnost poizvedbe %d presega omejitev %d",
+		"Subscription does not exist":                                "Naročnina ne obstaja",
+		"Alert does not exist":                                       "Obvestilo ne obstaja",
+		"Confirmation could not be sent to %s":                       "Potrditve ni bilo mogoče poslati na %s",
+		"An image sync is already running":                           "Sinhronizacija slik že poteka",
+		"No image sync has run yet":                                  "Sinhronizacija slik se še ni izvedla",
+		"Bus station with ID %d has no image":                        "Avtobusna postaja z ID %d nima slike",
+		"Image %s does not exist":                                    "Slika %s ne obstaja",
+		"Image of bus station with ID %d is temporarily unavailable": "Slika avtobusne postaje z ID %d trenutno ni na voljo",
+
+		// Field violations
+		"%s is required":                                    "%s je obvezen",
+		"%s must be one of: %s":                             "%s mora biti eden izmed: %s",
+		"%s must be an integer":                             "%s mora biti celo število",
+		"%s must be true or false":                          "%s mora biti true ali false",
+		"%s must be a SHA-256 hex digest":                   "%s mora biti šestnajstiški povzetek SHA-256",
+		"%s must be a number":                               "%s mora biti število",
+		"%s must be between %g and %g":                      "%s mora biti med %g in %g",
+		"%s must be at least %d":                            "%s mora biti vsaj %d",
+		"%s must be between %d and %d":                      "%s mora biti med %d in %d",
+		"%s must be a date in YYYY-MM-DD format":            "%s mora biti datum v obliki YYYY-MM-DD",
+		"%s must be a time in HH:MM format or 'now'":        "%s mora biti čas v obliki HH:MM ali 'now'",
+		"%s must be minLon,minLat,maxLon,maxLat":            "%s mora biti v obliki minLon,minLat,maxLon,maxLat",
+		"%s minimum corner must not exceed the maximum":     "%s: spodnji vogal ne sme presegati zgornjega",
+		"%s must be a %s":                                   "%s mora biti tipa %s",
+		"%s is not a known field":                           "%s ni znano polje",
+		"%s is not a supported language":                    "%s ni podprt jezik",
+		"%s is not translatable for %s":                     "%s ni mogoče prevesti za %s",
+		"lat and lon must be given together":                "lat in lon morata biti podana skupaj",
+		"sorting by distance requires lat and lon":          "razvrščanje po razdalji zahteva lat in lon",
+		"%s is not a valid page token":                      "%s ni veljaven žeton strani",
+		"cursor is not a valid page cursor":                 "cursor ni veljaven kazalec strani",
+		"cursor does not match the requested sort":          "cursor se ne ujema z zahtevanim razvrščanjem",
+		"request body is required":                          "telo zahteve je obvezno",
+		"request body must be valid JSON":                   "telo zahteve mora biti veljaven JSON",
+		"%s must be a time in HH:MM format":                 "%s mora biti čas v obliki HH:MM",
+		"%s must not be after %s":                           "%s ne sme biti za %s",
+		"%s must be after %s":                               "%s mora biti za %s",
+		"%s does not match the code that was sent":          "%s se ne ujema s poslano kodo",
+		"%s must differ from %s":                            "%s se mora razlikovati od %s",
+		"%s must be at most %d characters":                  "%s je lahko dolg največ %d znakov",
+		"%s is not a valid target for channel %s":           "%s ni veljaven naslov za kanal %s",
+		"%s is given more than once":                        "%s je podan večkrat",
+		"%s must be a colour in #RRGGBB format":             "%s mora biti barva v obliki #RRGGBB",
+		"at least one attribute column is required":         "obvezen je vsaj en stolpec z atributom",
+		"request body must be valid CSV":                    "telo zahteve mora biti veljaven CSV",
+		"Line %d: row must be valid CSV with %d fields":     "Vrstica %d: vrstica mora biti veljaven CSV s %d polji",
+		"Line %d: %s must be a positive integer":            "Vrstica %d: %s mora biti pozitivno celo število",
+		"Line %d: %s must be yes, no or empty":              "Vrstica %d: %s mora biti yes, no ali prazno",
+		"Line %d: bus station %d does not exist":            "Vrstica %d: avtobusna postaja %d ne obstaja",
+		"Line %d: bus station %d is already set on line %d": "Vrstica %d: avtobusna postaja %d je že podana v vrstici %d",
+
+		// Reminders
+		"Line %s towards %s departs at %s":                                      "Linija %s proti %s odpelje ob %s",
+		"%s: line %s towards %s departs from %s at %s and arrives at %s at %s.": "%s: linija %s proti %s odpelje s postaje %s ob %s in prispe na postajo %s ob %s.",
+		"Confirm the departure reminders for %s":                                "Potrdite opomnike na odhode za %s",
+		"This address was subscribed to departure reminders for %s. To receive them, confirm the subscription with the code %s. If you did not subscribe, ignore this message and you will not hear from us again.": "Ta naslov je bil prijavljen na opomnike na odhode za %s. Če jih želite prejemati, naročnino potrdite s kodo %s. Če se niste prijavili, to sporočilo prezrite in ne bomo vas več kontaktirali.",
+
+		// Schedule types
+		"Weekday":             "Delavnik",
+		"Saturday":            "Sobota",
+		"Sunday and holidays": "Nedelja in prazniki",
+	},
+}
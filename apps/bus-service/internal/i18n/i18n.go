@@ -0,0 +1,94 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lang is a supported response language as a two-letter ISO 639-1 code.
+type Lang string
+
+const (
+	English   Lang = "en"
+	Slovenian Lang = "sl"
+)
+
+// Default is used when a client states no supported preference. Messages are
+// written in English in the code, so it needs no catalog.
+const Default = English
+
+// Supported lists the languages a client may request.
+var Supported = []Lang{English, Slovenian}
+
+// Parse accepts a language tag such as "sl" or "sl-SI".
+func Parse(tag string) (Lang, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	lang := Lang(base)
+	return lang, slices.Contains(Supported, lang)
+}
+
+// Negotiate picks the supported language with the highest quality from an
+// Accept-Language header, falling back to Default.
+func Negotiate(acceptLanguage string) Lang {
+	type candidate struct {
+		lang Lang
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		lang, ok := Parse(tag)
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		if v, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{lang: lang, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Default
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
+
+type contextKey struct{}
+
+func WithLang(ctx context.Context, lang Lang) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// FromContext returns the language negotiated for the request, or Default.
+func FromContext(ctx context.Context) Lang {
+	if lang, ok := ctx.Value(contextKey{}).(Lang); ok {
+		return lang
+	}
+	return Default
+}
+
+// T formats a message in the given language. format is the English message
+// and doubles as the catalog key; untranslated messages stay English.
+func T(lang Lang, format string, args ...any) string {
+	if translated, ok := catalog[lang][format]; ok {
+		format = translated
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+)
+
+// VersionSource reports the current data generation and when it started.
+type VersionSource interface {
+	Version(ctx context.Context) (int64, time.Time)
+}
+
+// CachePolicy describes how browsers and shared caches may store responses of
+// an endpoint.
+type CachePolicy struct {
+	MaxAge       time.Duration // max-age for browsers
+	SharedMaxAge time.Duration // s-maxage for CDNs and proxies
+	// StaleWhileRevalidate lets caches serve a stale copy while refetching.
+	StaleWhileRevalidate time.Duration
+	// KeyParts returns inputs the response depends on besides the path and
+	// query, e.g. today's date for endpoints that default to it.
+	KeyParts func(r *http.Request) []string
+}
+
+func (p CachePolicy) header() string {
+	directives := []string{"public", fmt.Sprintf("max-age=%d", int(p.MaxAge.Seconds()))}
+	if p.SharedMaxAge > 0 {
+		directives = append(directives, fmt.Sprintf("s-maxage=%d", int(p.SharedMaxAge.Seconds())))
+	}
+	if p.StaleWhileRevalidate > 0 {
+		directives = append(directives, fmt.Sprintf("stale-while-revalidate=%d", int(p.StaleWhileRevalidate.Seconds())))
+	}
+	return strings.Join(directives, ", ")
+}
+
+// Conditional serves read-only endpoints with validators. The ETag is derived
+// from the data generation and the request, so it is known before the handler
+// runs and a matching If-None-Match or If-Modified-Since is answered with 304
+// without touching the database. The tag is strong: Compress, which runs
+// outside this middleware, encodes the body after the tag is set, so the tag
+// includes the content coding Compress picks and gzip and identity responses
+// get different tags. Last-Modified is the start of the current generation,
+// i.e. the last successful seed, sync or admin write. Only 200 responses are
+// marked cacheable.
+func Conditional(versions VersionSource, policy CachePolicy) func(next http.Handler) http.Handler {
+	cacheControl := policy.header()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			generation, updatedAt := versions.Version(r.Context())
+			etag := entityTag(generation, r, policy.KeyParts)
+			lastModified := updatedAt.UTC().Truncate(time.Second)
+
+			h := w.Header()
+			h.Set("ETag", etag)
+			h.Add("Vary", "Accept-Encoding")
+			if !lastModified.IsZero() {
+				h.Set("Last-Modified", lastModified.Format(http.TimeFormat))
+			}
+			h.Set("Cache-Control", cacheControl)
+
+			if notModified(r, etag, lastModified) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			next.ServeHTTP(&validatorWriter{ResponseWriter: w}, r)
+		})
+	}
+}
+
+// NoStore forbids caching, for endpoints that report live state.
+func NoStore(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		next.ServeHTTP(w, r)
+	})
+}
+
+func entityTag(generation int64, r *http.Request, keyParts func(r *http.Request) []string) string {
+	// Encode sorts the query by key, so parameter order does not matter. The
+	// language may come from Accept-Language rather than the query.
+	key := r.URL.Path + "?" + r.URL.Query().Encode() + "|" + string(i18n.FromContext(r.Context()))
+	if keyParts != nil {
+		key += "|" + strings.Join(keyParts(r), "|")
+	}
+	hash := sha256.Sum256([]byte(key))
+	if coding := contentCoding(r); coding != "" {
+		return fmt.Sprintf(`"g%d-%x-%s"`, generation, hash[:8], coding)
+	}
+	return fmt.Sprintf(`"g%d-%x"`, generation, hash[:8])
+}
+
+// contentCoding returns the coding Compress encodes the response to r with,
+// or "" for identity. It mirrors Compress, which prefers gzip over deflate
+// and matches the Accept-Encoding entries by substring.
+func contentCoding(r *http.Request) string {
+	accepted := strings.ToLower(r.Header.Get("Accept-Encoding"))
+	for _, coding := range []string{"gzip", "deflate"} {
+		if strings.Contains(accepted, coding) {
+			return coding
+		}
+	}
+	return ""
+}
+
+// notModified evaluates the preconditions of RFC 9110 section 13.2.2:
+// If-None-Match takes precedence, using weak comparison so tags a proxy
+// weakened still match, and If-Modified-Since is only consulted without it.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		return err == nil && !lastModified.After(since)
+	}
+	return false
+}
+
+// validatorWriter drops the validators and caching headers set by Conditional
+// when the handler answers with anything but 200, so errors are never cached.
+type validatorWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *validatorWriter) WriteHeader(status int) {
+	if !w.wroteHeader && status != http.StatusOK {
+		h := w.Header()
+		h.Del("ETag")
+		h.Del("Last-Modified")
+		h.Set("Cache-Control", "no-store")
+	}
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *validatorWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *validatorWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+)
+
+// Language negotiates the response language and stores it in the request
+// context. The lang query parameter overrides Accept-Language, so clients
+// that cannot set headers, such as links shared by a tourist app, can still
+// choose.
+func Language(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := i18n.Negotiate(r.Header.Get("Accept-Language"))
+		w.Header().Add("Vary", "Accept-Language")
+
+		if tag := r.URL.Query().Get("lang"); tag != "" {
+			override, ok := i18n.Parse(tag)
+			if !ok {
+				r = r.WithContext(i18n.WithLang(r.Context(), lang))
+				_ = errs.ValidationError([]errs.FieldError{
+					errs.NewFieldError(errs.InQuery, "lang", errs.FieldInvalidValue, "%s is not a supported language", tag),
+				}).Write(w, r)
+				return
+			}
+			lang = override
+		}
+
+		w.Header().Set("Content-Language", string(lang))
+		next.ServeHTTP(w, r.WithContext(i18n.WithLang(r.Context(), lang)))
+	})
+}
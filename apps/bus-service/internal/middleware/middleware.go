@@ -1,23 +1,95 @@
 package middleware
 
 import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
-func Init(r *chi.Mux) {
+func Init(r *chi.Mux, requestTimeout time.Duration) {
 
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Subscription-Token"},
+		ExposedHeaders:   []string{"Link", "X-Next-Cursor", "X-Total-Count", "Deprecation", "Sunset", "ETag"},
 		AllowCredentials: true,
 	}))
 
-	r.Use(middleware.Compress(5, "application/json"))
+	r.Use(middleware.Compress(5, "application/json", "application/geo+json"))
+
+	r.Use(otelhttp.NewMiddleware("http.server"))
 
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 
+	// Cancels the request context after the deadline so in-flight queries and
+	// provider calls are aborted.
+	r.Use(timeout(requestTimeout))
+
+	r.Use(Language)
+
+}
+
+// RequireAdminToken only lets requests through that carry the configured
+// token as a bearer token. With an empty token admin routes are disabled.
+func RequireAdminToken(token string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				_ = errs.NotFoundError("Not Found").Write(w, r)
+				return
+			}
+
+			given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+				_ = errs.UnauthorizedError().Write(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Deprecated marks every response as deprecated (RFC 9745) with a Sunset date
+// (RFC 8594) and links to the same path under successorPrefix, which replaces
+// the leading /api of the request path.
+func Deprecated(deprecatedAt, sunset time.Time, successorPrefix string) func(next http.Handler) http.Handler {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			successor := successorPrefix + strings.TrimPrefix(r.URL.Path, "/api")
+
+			w.Header().Set("Deprecation", deprecation)
+			w.Header().Set("Sunset", sunsetDate)
+			w.Header().Add("Link", `<`+successor+`>; rel="successor-version"`)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// timeout is like chi's middleware.Timeout but leaves writing the 504 to
+// api.MakeHandlerFunc, so the response body stays a regular API error.
+func timeout(d time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
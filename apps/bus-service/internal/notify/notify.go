@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+)
+
+// Channels a subscription can be reminded through.
+const (
+	ChannelWebhook = "webhook"
+	ChannelEmail   = "email"
+)
+
+// Reminder announces an upcoming departure of a saved trip. It is also the
+// JSON body posted to webhooks.
+type Reminder struct {
+	SubscriptionID int       `json:"subscriptionId"`
+	Name           string    `json:"name"`
+	Lang           i18n.Lang `json:"lang"`
+	Date           string    `json:"date"`
+	DepartureID    int       `json:"departureId"`
+	Line           string    `json:"line"`
+	Direction      string    `json:"direction"`
+	From           string    `json:"from"`
+	To             string    `json:"to"`
+	DepartureAt    time.Time `json:"departureAt"`
+	ArriveAt       time.Time `json:"arriveAt"`
+}
+
+// Subject is a one-line summary of r in its language.
+func (r Reminder) Subject() string {
+	return i18n.T(r.Lang, "Line %s towards %s departs at %s", r.Line, r.Direction, r.DepartureAt.Format("15:04"))
+}
+
+// Text is the plain-text message of r in its language.
+func (r Reminder) Text() string {
+	return i18n.T(r.Lang, "%s: line %s towards %s departs from %s at %s and arrives at %s at %s.",
+		r.Name, r.Line, r.Direction, r.From, r.DepartureAt.Format("15:04"), r.To, r.ArriveAt.Format("15:04"))
+}
+
+// Confirmation asks the owner of a target to opt in to the reminders of a
+// subscription with Code.
+type Confirmation struct {
+	SubscriptionID int
+	Name           string
+	Lang           i18n.Lang
+	Code           string
+}
+
+// Subject is a one-line summary of c in its language.
+func (c Confirmation) Subject() string {
+	return i18n.T(c.Lang, "Confirm the departure reminders for %s", c.Name)
+}
+
+// Text is the plain-text message of c in its language.
+func (c Confirmation) Text() string {
+	return i18n.T(c.Lang, "This address was subscribed to departure reminders for %s. To receive them, confirm the subscription with the code %s. If you did not subscribe, ignore this message and you will not hear from us again.",
+		c.Name, c.Code)
+}
+
+// Notifier delivers reminders through one channel.
+type Notifier interface {
+	// ValidTarget reports whether target is an address this channel can
+	// deliver to, e.g. a URL or an email address.
+	ValidTarget(ctx context.Context, target string) bool
+	Notify(ctx context.Context, target string, r Reminder) error
+}
+
+// Confirmer is implemented by channels whose targets must opt in before they
+// are sent reminders, because a subscription may name anyone's address.
+type Confirmer interface {
+	Confirm(ctx context.Context, target string, c Confirmation) error
+}
+
+// Notifiers maps the configured channels to their notifier.
+type Notifiers map[string]Notifier
+
+// Channels returns the configured channel names, sorted.
+func (n Notifiers) Channels() []string {
+	channels := make([]string, 0, len(n))
+	for channel := range n {
+		channels = append(channels, channel)
+	}
+	slices.Sort(channels)
+	return channels
+}
+
+// Confirmer returns the Confirmer of channel, if its targets must opt in.
+func (n Notifiers) Confirmer(channel string) (Confirmer, bool) {
+	c, ok := n[channel].(Confirmer)
+	return c, ok
+}
+
+// Notify delivers r through channel.
+func (n Notifiers) Notify(ctx context.Context, channel, target string, r Reminder) error {
+	notifier, ok := n[channel]
+	if !ok {
+		return fmt.Errorf("notify: channel %q is not configured", channel)
+	}
+	return notifier.Notify(ctx, target, r)
+}
@@ -0,0 +1,133 @@
+// Package sink provides local stand-ins for the services reminders are
+// delivered to: a minimal SMTP server and a webhook receiver. They accept
+// everything and hand each message to a callback, for development and tests.
+package sink
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// Mail is a message received by SMTPServer.
+type Mail struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+// SMTPServer speaks just enough SMTP for net/smtp clients without TLS or
+// authentication.
+type SMTPServer struct {
+	listener net.Listener
+	deliver  func(Mail)
+	wg       sync.WaitGroup
+}
+
+// ListenSMTP starts an SMTPServer on addr, e.g. "localhost:2525" or
+// "localhost:0" for a free port.
+func ListenSMTP(addr string, deliver func(Mail)) (*SMTPServer, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SMTPServer{listener: l, deliver: deliver}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Addr is the address the server listens on.
+func (s *SMTPServer) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Close stops accepting connections and waits for open sessions to end.
+func (s *SMTPServer) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *SMTPServer) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			s.session(textproto.NewConn(conn))
+		}()
+	}
+}
+
+func (s *SMTPServer) session(c *textproto.Conn) {
+	reply := func(code int, msg string) bool {
+		return c.PrintfLine("%d %s", code, msg) == nil
+	}
+
+	if !reply(220, "mbus notify sink") {
+		return
+	}
+
+	var mail Mail
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+
+		ok := true
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			ok = reply(250, "hello")
+		case "MAIL":
+			mail = Mail{From: address(arg)}
+			ok = reply(250, "OK")
+		case "RCPT":
+			mail.To = append(mail.To, address(arg))
+			ok = reply(250, "OK")
+		case "DATA":
+			if !reply(354, "end data with <CR><LF>.<CR><LF>") {
+				return
+			}
+			data, err := io.ReadAll(c.DotReader())
+			if err != nil {
+				return
+			}
+			mail.Data = data
+			s.deliver(mail)
+			mail = Mail{}
+			ok = reply(250, "OK")
+		case "RSET":
+			mail = Mail{}
+			ok = reply(250, "OK")
+		case "NOOP":
+			ok = reply(250, "OK")
+		case "QUIT":
+			reply(221, "bye")
+			return
+		default:
+			ok = reply(502, fmt.Sprintf("%s not implemented", verb))
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+// address extracts the mailbox from "FROM:<a@b>" or "TO:<a@b>".
+func address(arg string) string {
+	_, addr, _ := strings.Cut(arg, ":")
+	addr, _, _ = strings.Cut(strings.TrimSpace(addr), " ")
+	return strings.Trim(addr, "<>")
+}
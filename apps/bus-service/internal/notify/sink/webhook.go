@@ -0,0 +1,64 @@
+package sink
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/notify"
+)
+
+// Webhook is a received reminder. Verified is set when the receiver has a
+// secret and the signature matches it.
+type Webhook struct {
+	Reminder notify.Reminder
+	Verified bool
+}
+
+// WebhookReceiver accepts reminders posted by notify.WebhookNotifier. With a
+// secret, requests without a valid signature are rejected.
+type WebhookReceiver struct {
+	secret  []byte
+	deliver func(Webhook)
+}
+
+func NewWebhookReceiver(secret string, deliver func(Webhook)) *WebhookReceiver {
+	r := &WebhookReceiver{deliver: deliver}
+	if secret != "" {
+		r.secret = []byte(secret)
+	}
+	return r
+}
+
+func (rc *WebhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	verified := false
+	if rc.secret != nil {
+		signature := r.Header.Get(notify.SignatureHeader)
+		if !hmac.Equal([]byte(signature), []byte(notify.Sign(rc.secret, body))) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		verified = true
+	}
+
+	var reminder notify.Reminder
+	if err := json.Unmarshal(body, &reminder); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	rc.deliver(Webhook{Reminder: reminder, Verified: verified})
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,139 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SMTPOptions configure the mail server reminders are sent through.
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	Username string // empty sends without authentication
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+// SMTPNotifier emails reminders as plain text. STARTTLS is used whenever the
+// server offers it. Addresses must confirm their subscription before they are
+// sent reminders.
+type SMTPNotifier struct {
+	opts SMTPOptions
+}
+
+var (
+	_ Notifier  = (*SMTPNotifier)(nil)
+	_ Confirmer = (*SMTPNotifier)(nil)
+)
+
+func NewSMTPNotifier(opts SMTPOptions) *SMTPNotifier {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	return &SMTPNotifier{opts: opts}
+}
+
+// ValidTarget accepts a single bare email address.
+func (n *SMTPNotifier) ValidTarget(_ context.Context, target string) bool {
+	addr, err := mail.ParseAddress(target)
+	return err == nil && addr.Name == "" && addr.Address == target
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, target string, r Reminder) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "notify.SMTP",
+		attribute.Int("subscription.id", r.SubscriptionID),
+	)
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	return n.send(ctx, target, n.message(target, r.Lang, r.Subject(), r.Text()))
+}
+
+// Confirm emails the confirmation code of a new subscription to target.
+func (n *SMTPNotifier) Confirm(ctx context.Context, target string, c Confirmation) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "notify.SMTPConfirm",
+		attribute.Int("subscription.id", c.SubscriptionID),
+	)
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	return n.send(ctx, target, n.message(target, c.Lang, c.Subject(), c.Text()))
+}
+
+func (n *SMTPNotifier) send(ctx context.Context, target string, message []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, n.opts.Timeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(n.opts.Host, strconv.Itoa(n.opts.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, n.opts.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: n.opts.Host}); err != nil {
+			return fmt.Errorf("SMTP STARTTLS failed: %w", err)
+		}
+	}
+	if n.opts.Username != "" {
+		auth := smtp.PlainAuth("", n.opts.Username, n.opts.Password, n.opts.Host)
+		if err := c.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := c.Mail(n.opts.From); err != nil {
+		return fmt.Errorf("SMTP MAIL failed: %w", err)
+	}
+	if err := c.Rcpt(target); err != nil {
+		return fmt.Errorf("SMTP RCPT failed: %w", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA failed: %w", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP server rejected email: %w", err)
+	}
+	return c.Quit()
+}
+
+func (n *SMTPNotifier) message(to string, lang i18n.Lang, subject, text string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", n.opts.From)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Content-Language: %s\r\n", lang)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(text)
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
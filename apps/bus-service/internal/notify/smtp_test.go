@@ -0,0 +1,85 @@
+package notify_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/notify"
+	"github.com/perkzen/mbus/apps/bus-service/internal/notify/sink"
+)
+
+// newSMTPNotifier starts an SMTP sink and returns a notifier sending to it.
+func newSMTPNotifier(t *testing.T) (*notify.SMTPNotifier, <-chan sink.Mail) {
+	t.Helper()
+
+	mails := make(chan sink.Mail, 1)
+	server, err := sink.ListenSMTP("127.0.0.1:0", func(m sink.Mail) { mails <- m })
+	if err != nil {
+		t.Fatalf("ListenSMTP() error = %v", err)
+	}
+	t.Cleanup(func() { _ = server.Close() })
+
+	host, port, _ := net.SplitHostPort(server.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	return notify.NewSMTPNotifier(notify.SMTPOptions{
+		Host:    host,
+		Port:    portNum,
+		From:    "mbus@localhost",
+		Timeout: 5 * time.Second,
+	}), mails
+}
+
+func TestSMTPNotifyMailsReminder(t *testing.T) {
+	n, mails := newSMTPNotifier(t)
+
+	r := testReminder()
+	if err := n.Notify(context.Background(), "rider@example.com", r); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	m := <-mails
+	if m.From != "mbus@localhost" || len(m.To) != 1 || m.To[0] != "rider@example.com" {
+		t.Errorf("envelope = %s -> %v, want mbus@localhost -> [rider@example.com]", m.From, m.To)
+	}
+	data := string(m.Data)
+	for _, want := range []string{"To: rider@example.com", "Content-Language: en", r.Text()} {
+		if !strings.Contains(data, want) {
+			t.Errorf("mail does not contain %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestSMTPConfirmMailsCode(t *testing.T) {
+	n, mails := newSMTPNotifier(t)
+
+	var confirmer notify.Confirmer = n
+	c := notify.Confirmation{SubscriptionID: 7, Name: "To work", Lang: i18n.Slovenian, Code: "K7QX2M4D"}
+	if err := confirmer.Confirm(context.Background(), "rider@example.com", c); err != nil {
+		t.Fatalf("Confirm() error = %v", err)
+	}
+
+	data := string((<-mails).Data)
+	if !strings.Contains(data, "K7QX2M4D") || !strings.Contains(data, "Content-Language: sl") {
+		t.Errorf("confirmation mail lacks the code or language:\n%s", data)
+	}
+}
+
+func TestNotifiersConfirmer(t *testing.T) {
+	n, _ := newSMTPNotifier(t)
+	notifiers := notify.Notifiers{
+		notify.ChannelWebhook: notify.NewWebhookNotifier(),
+		notify.ChannelEmail:   n,
+	}
+
+	if _, ok := notifiers.Confirmer(notify.ChannelEmail); !ok {
+		t.Error("email targets must confirm their subscription")
+	}
+	if _, ok := notifiers.Confirmer(notify.ChannelWebhook); ok {
+		t.Error("webhook targets need no confirmation")
+	}
+}
@@ -0,0 +1,143 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the body, prefixed with
+// "sha256=", when a webhook secret is configured.
+const SignatureHeader = "X-Mbus-Signature"
+
+// WebhookNotifier posts reminders as JSON to the target URL. Targets must be
+// on the public internet, both when they are validated and when they are
+// dialled, so subscriptions cannot reach internal services.
+type WebhookNotifier struct {
+	secret       []byte
+	allowPrivate bool
+	client       *http.Client
+}
+
+var _ Notifier = (*WebhookNotifier)(nil)
+
+type WebhookOption func(*WebhookNotifier)
+
+// WithWebhookTimeout bounds every webhook request, including reading the
+// response.
+func WithWebhookTimeout(timeout time.Duration) WebhookOption {
+	return func(n *WebhookNotifier) {
+		n.client.Timeout = timeout
+	}
+}
+
+// WithWebhookSecret signs every body so receivers can verify its origin.
+func WithWebhookSecret(secret string) WebhookOption {
+	return func(n *WebhookNotifier) {
+		if secret != "" {
+			n.secret = []byte(secret)
+		}
+	}
+}
+
+// WithPrivateTargets allows loopback and private targets, e.g. a local
+// notify-sink. Never enable it where untrusted clients create subscriptions.
+func WithPrivateTargets() WebhookOption {
+	return func(n *WebhookNotifier) {
+		n.allowPrivate = true
+	}
+}
+
+func NewWebhookNotifier(opts ...WebhookOption) *WebhookNotifier {
+	n := &WebhookNotifier{}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			if n.allowPrivate {
+				return nil
+			}
+			return dialPublic(network, address, c)
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialled instead of the target and bypass the check.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	n.client = &http.Client{
+		Transport: otelhttp.NewTransport(transport),
+		Timeout:   10 * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	return n
+}
+
+// ValidTarget accepts absolute http and https URLs whose host resolves to
+// public addresses only.
+func (n *WebhookNotifier) ValidTarget(ctx context.Context, target string) bool {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return false
+	}
+	return n.allowPrivate || publicHost(ctx, u.Hostname())
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, target string, r Reminder) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "notify.Webhook",
+		attribute.Int("subscription.id", r.SubscriptionID),
+	)
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.secret != nil {
+		req.Header.Set(SignatureHeader, Sign(n.secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the SignatureHeader value for body.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
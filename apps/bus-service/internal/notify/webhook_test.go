@@ -0,0 +1,114 @@
+package notify_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/notify"
+	"github.com/perkzen/mbus/apps/bus-service/internal/notify/sink"
+)
+
+func testReminder() notify.Reminder {
+	loc := time.FixedZone("CEST", 2*60*60)
+	return notify.Reminder{
+		SubscriptionID: 7,
+		Name:           "To work",
+		Lang:           i18n.English,
+		Date:           "2025-10-27",
+		DepartureID:    42,
+		Line:           "G6",
+		Direction:      "Tezno",
+		From:           "Glavni trg",
+		To:             "Tezno",
+		DepartureAt:    time.Date(2025, 10, 27, 7, 15, 0, 0, loc),
+		ArriveAt:       time.Date(2025, 10, 27, 7, 32, 0, 0, loc),
+	}
+}
+
+func TestWebhookValidTarget(t *testing.T) {
+	n := notify.NewWebhookNotifier()
+
+	tests := []struct {
+		target string
+		want   bool
+	}{
+		{"https://93.184.215.14/hooks/mbus", true},
+		{"http://[2606:4700::6810:84e5]/", true},
+		{"ftp://93.184.215.14/", false},
+		{"/hooks/mbus", false},
+		{"http://127.0.0.1:8025/", false},
+		{"http://localhost:8025/", false},
+		{"http://[::1]/", false},
+		{"http://[::ffff:127.0.0.1]/", false},
+		{"http://169.254.169.254/latest/meta-data/", false},
+		{"http://10.0.0.5/", false},
+		{"http://172.16.0.1/", false},
+		{"http://192.168.1.1/", false},
+		{"http://100.64.0.1/", false},
+		{"http://0.0.0.0/", false},
+		{"http://[fd00::1]/", false},
+		{"http://[fe80::1]/", false},
+	}
+	for _, tt := range tests {
+		if got := n.ValidTarget(context.Background(), tt.target); got != tt.want {
+			t.Errorf("ValidTarget(%q) = %t, want %t", tt.target, got, tt.want)
+		}
+	}
+
+	if !notify.NewWebhookNotifier(notify.WithPrivateTargets()).ValidTarget(context.Background(), "http://127.0.0.1:8025/") {
+		t.Error("ValidTarget rejected a loopback target with private targets allowed")
+	}
+}
+
+func TestWebhookNotifyRefusesNonPublicAddresses(t *testing.T) {
+	delivered := make(chan sink.Webhook, 1)
+	server := httptest.NewServer(sink.NewWebhookReceiver("", func(w sink.Webhook) { delivered <- w }))
+	defer server.Close()
+
+	// The target is checked again when it is dialled, so a name that
+	// resolved to a public address during validation cannot be rebound.
+	err := notify.NewWebhookNotifier().Notify(context.Background(), server.URL, testReminder())
+	if !errors.Is(err, notify.ErrNonPublicAddress) {
+		t.Fatalf("Notify() error = %v, want ErrNonPublicAddress", err)
+	}
+	if len(delivered) != 0 {
+		t.Fatal("webhook was delivered to a loopback address")
+	}
+}
+
+func TestWebhookNotifyDeliversSignedReminder(t *testing.T) {
+	delivered := make(chan sink.Webhook, 1)
+	server := httptest.NewServer(sink.NewWebhookReceiver("secret", func(w sink.Webhook) { delivered <- w }))
+	defer server.Close()
+
+	n := notify.NewWebhookNotifier(notify.WithWebhookSecret("secret"), notify.WithPrivateTargets())
+	want := testReminder()
+	if err := n.Notify(context.Background(), server.URL, want); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	got := <-delivered
+	if !got.Verified {
+		t.Error("webhook signature was not verified")
+	}
+	if got.Reminder.SubscriptionID != want.SubscriptionID || got.Reminder.DepartureID != want.DepartureID ||
+		got.Reminder.Line != want.Line || !got.Reminder.DepartureAt.Equal(want.DepartureAt) {
+		t.Errorf("delivered reminder = %+v, want %+v", got.Reminder, want)
+	}
+}
+
+func TestWebhookNotifyReportsRejectedSignature(t *testing.T) {
+	server := httptest.NewServer(sink.NewWebhookReceiver("secret", func(sink.Webhook) {
+		t.Error("unsigned webhook was accepted")
+	}))
+	defer server.Close()
+
+	n := notify.NewWebhookNotifier(notify.WithPrivateTargets())
+	if err := n.Notify(context.Background(), server.URL, testReminder()); err == nil {
+		t.Fatal("Notify() succeeded although the receiver rejected the request")
+	}
+}
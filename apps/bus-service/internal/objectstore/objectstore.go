@@ -0,0 +1,23 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotExist is returned by Get when no object is stored under the key.
+var ErrNotExist = errors.New("objectstore: object does not exist")
+
+// Store keeps immutable blobs under slash-separated keys, e.g.
+// "images/ab/abcdef.../original". Objects are small enough to be held in
+// memory.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put stores data under key, replacing any previous object atomically.
+	Put(ctx context.Context, key string, data []byte) error
+	Ping(ctx context.Context) error
+}
+
+const (
+	BackendFilesystem = "filesystem"
+)
@@ -0,0 +1,153 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Sort fields shared by list endpoints. Each endpoint accepts a subset.
+const (
+	SortName         = "name"
+	SortDistance     = "distance"     // from the lat/lon given in the request
+	SortLineCount    = "lineCount"    // lines serving a station
+	SortStationCount = "stationCount" // stations served by a line
+)
+
+// Column names every paginated inner query has to select.
+const (
+	KeyColumn = "sort_key"
+	IDColumn  = "id"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidSort   = errors.New("invalid sort")
+)
+
+// Sort is a sort field with direction. "-name" sorts by name descending.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort parses raw against the allowed fields. An empty raw value sorts
+// ascending by the first allowed field.
+func ParseSort(raw string, allowed ...string) (Sort, error) {
+	if raw == "" {
+		return Sort{Field: allowed[0]}, nil
+	}
+
+	s := Sort{Field: strings.TrimPrefix(raw, "-"), Desc: strings.HasPrefix(raw, "-")}
+	if !slices.Contains(allowed, s.Field) {
+		return Sort{}, fmt.Errorf("%w: must be one of %s", ErrInvalidSort, strings.Join(allowed, ", "))
+	}
+	return s, nil
+}
+
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+// Cursor points just past the last item of a page. It is handed to clients
+// as an opaque string.
+type Cursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   int    `json:"i"`
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Request describes the requested page. With a cursor, Offset is ignored.
+type Request struct {
+	Limit     int
+	Offset    int
+	Cursor    *Cursor
+	Sort      Sort
+	WithTotal bool
+}
+
+// Validate checks that the cursor was issued for the same sort order.
+func (r Request) Validate() error {
+	if r.Cursor != nil && r.Cursor.Sort != r.Sort.String() {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+// Page is one page of a list.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+	Total      *int
+}
+
+// Query selects a page from inner, which has to select KeyColumn and
+// IDColumn. keyType is the SQL type of the sort key, used to cast the cursor.
+// One row more than the limit is fetched to detect whether a next page exists.
+func Query(qb sq.StatementBuilderType, inner sq.SelectBuilder, req Request, keyType string) sq.SelectBuilder {
+	dir, cmp := "ASC", ">"
+	if req.Sort.Desc {
+		dir, cmp = "DESC", "<"
+	}
+
+	q := qb.Select("*").
+		FromSelect(inner, "p").
+		OrderBy(fmt.Sprintf("p.%s %s", KeyColumn, dir), fmt.Sprintf("p.%s %s", IDColumn, dir))
+
+	if req.Cursor != nil {
+		q = q.Where(fmt.Sprintf("(p.%s, p.%s) %s (CAST(? AS %s), ?)", KeyColumn, IDColumn, cmp, keyType),
+			req.Cursor.Key, req.Cursor.ID)
+	} else if req.Offset > 0 {
+		q = q.Offset(uint64(req.Offset))
+	}
+
+	if req.Limit > 0 {
+		q = q.Limit(uint64(req.Limit + 1))
+	}
+	return q
+}
+
+// CountQuery counts all rows of inner, ignoring the page.
+func CountQuery(qb sq.StatementBuilderType, inner sq.SelectBuilder) sq.SelectBuilder {
+	return qb.Select("COUNT(*)").FromSelect(inner, "c")
+}
+
+// NewPage trims the extra row fetched by Query and derives the next cursor
+// from the last item. cursorOf returns the sort key and id of items[i].
+func NewPage[T any](items []T, req Request, cursorOf func(i int) (key string, id int)) *Page[T] {
+	page := &Page[T]{Items: items}
+	if req.Limit <= 0 || len(items) <= req.Limit {
+		return page
+	}
+
+	page.Items = items[:req.Limit]
+	key, id := cursorOf(req.Limit - 1)
+	page.NextCursor = Cursor{Sort: req.Sort.String(), Key: key, ID: id}.Encode()
+	return page
+}
@@ -0,0 +1,1889 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: bus/v1/bus.proto
+
+package busv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StationAttribute int32
+
+const (
+	StationAttribute_STATION_ATTRIBUTE_UNSPECIFIED           StationAttribute = 0
+	StationAttribute_STATION_ATTRIBUTE_WHEELCHAIR_ACCESSIBLE StationAttribute = 1
+	StationAttribute_STATION_ATTRIBUTE_SHELTER               StationAttribute = 2
+	StationAttribute_STATION_ATTRIBUTE_BENCH                 StationAttribute = 3
+	StationAttribute_STATION_ATTRIBUTE_REALTIME_DISPLAY      StationAttribute = 4
+	StationAttribute_STATION_ATTRIBUTE_TICKET_MACHINE        StationAttribute = 5
+	StationAttribute_STATION_ATTRIBUTE_TACTILE_PAVING        StationAttribute = 6
+)
+
+// Enum value maps for StationAttribute.
+var (
+	StationAttribute_name = map[int32]string{
+		0: "STATION_ATTRIBUTE_UNSPECIFIED",
+		1: "STATION_ATTRIBUTE_WHEELCHAIR_ACCESSIBLE",
+		2: "STATION_ATTRIBUTE_SHELTER",
+		3: "STATION_ATTRIBUTE_BENCH",
+		4: "STATION_ATTRIBUTE_REALTIME_DISPLAY",
+		5: "STATION_ATTRIBUTE_TICKET_MACHINE",
+		6: "STATION_ATTRIBUTE_TACTILE_PAVING",
+	}
+	StationAttribute_value = map[string]int32{
+		"STATION_ATTRIBUTE_UNSPECIFIED":           0,
+		"STATION_ATTRIBUTE_WHEELCHAIR_ACCESSIBLE": 1,
+		"STATION_ATTRIBUTE_SHELTER":               2,
+		"STATION_ATTRIBUTE_BENCH":                 3,
+		"STATION_ATTRIBUTE_REALTIME_DISPLAY":      4,
+		"STATION_ATTRIBUTE_TICKET_MACHINE":        5,
+		"STATION_ATTRIBUTE_TACTILE_PAVING":        6,
+	}
+)
+
+func (x StationAttribute) Enum() *StationAttribute {
+	p := new(StationAttribute)
+	*p = x
+	return p
+}
+
+func (x StationAttribute) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StationAttribute) Descriptor() protoreflect.EnumDescriptor {
+	return file_bus_v1_bus_proto_enumTypes[0].Descriptor()
+}
+
+func (StationAttribute) Type() protoreflect.EnumType {
+	return &file_bus_v1_bus_proto_enumTypes[0]
+}
+
+func (x StationAttribute) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StationAttribute.Descriptor instead.
+func (StationAttribute) EnumDescriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{0}
+}
+
+type LineCategory int32
+
+const (
+	LineCategory_LINE_CATEGORY_UNSPECIFIED LineCategory = 0
+	LineCategory_LINE_CATEGORY_CITY        LineCategory = 1
+	LineCategory_LINE_CATEGORY_SUBURBAN    LineCategory = 2
+	LineCategory_LINE_CATEGORY_OTHER       LineCategory = 3
+)
+
+// Enum value maps for LineCategory.
+var (
+	LineCategory_name = map[int32]string{
+		0: "LINE_CATEGORY_UNSPECIFIED",
+		1: "LINE_CATEGORY_CITY",
+		2: "LINE_CATEGORY_SUBURBAN",
+		3: "LINE_CATEGORY_OTHER",
+	}
+	LineCategory_value = map[string]int32{
+		"LINE_CATEGORY_UNSPECIFIED": 0,
+		"LINE_CATEGORY_CITY":        1,
+		"LINE_CATEGORY_SUBURBAN":    2,
+		"LINE_CATEGORY_OTHER":       3,
+	}
+)
+
+func (x LineCategory) Enum() *LineCategory {
+	p := new(LineCategory)
+	*p = x
+	return p
+}
+
+func (x LineCategory) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LineCategory) Descriptor() protoreflect.EnumDescriptor {
+	return file_bus_v1_bus_proto_enumTypes[1].Descriptor()
+}
+
+func (LineCategory) Type() protoreflect.EnumType {
+	return &file_bus_v1_bus_proto_enumTypes[1]
+}
+
+func (x LineCategory) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LineCategory.Descriptor instead.
+func (LineCategory) EnumDescriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{1}
+}
+
+type ScheduleType int32
+
+const (
+	ScheduleType_SCHEDULE_TYPE_UNSPECIFIED ScheduleType = 0
+	ScheduleType_SCHEDULE_TYPE_WEEKDAY     ScheduleType = 1
+	ScheduleType_SCHEDULE_TYPE_SATURDAY    ScheduleType = 2
+	ScheduleType_SCHEDULE_TYPE_SUNDAY      ScheduleType = 3
+)
+
+// Enum value maps for ScheduleType.
+var (
+	ScheduleType_name = map[int32]string{
+		0: "SCHEDULE_TYPE_UNSPECIFIED",
+		1: "SCHEDULE_TYPE_WEEKDAY",
+		2: "SCHEDULE_TYPE_SATURDAY",
+		3: "SCHEDULE_TYPE_SUNDAY",
+	}
+	ScheduleType_value = map[string]int32{
+		"SCHEDULE_TYPE_UNSPECIFIED": 0,
+		"SCHEDULE_TYPE_WEEKDAY":     1,
+		"SCHEDULE_TYPE_SATURDAY":    2,
+		"SCHEDULE_TYPE_SUNDAY":      3,
+	}
+)
+
+func (x ScheduleType) Enum() *ScheduleType {
+	p := new(ScheduleType)
+	*p = x
+	return p
+}
+
+func (x ScheduleType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ScheduleType) Descriptor() protoreflect.EnumDescriptor {
+	return file_bus_v1_bus_proto_enumTypes[2].Descriptor()
+}
+
+func (ScheduleType) Type() protoreflect.EnumType {
+	return &file_bus_v1_bus_proto_enumTypes[2]
+}
+
+func (x ScheduleType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ScheduleType.Descriptor instead.
+func (ScheduleType) EnumDescriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{2}
+}
+
+type Station struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Path of the station photo below the HTTP API; append ?size=thumb, small
+	// or medium for a JPEG thumbnail.
+	ImageUrl     string             `protobuf:"bytes,3,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	Lat          float64            `protobuf:"fixed64,4,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lon          float64            `protobuf:"fixed64,5,opt,name=lon,proto3" json:"lon,omitempty"`
+	Codes        []int32            `protobuf:"varint,6,rep,packed,name=codes,proto3" json:"codes,omitempty"`
+	Lines        []string           `protobuf:"bytes,7,rep,name=lines,proto3" json:"lines,omitempty"`
+	Attributes   *StationAttributes `protobuf:"bytes,8,opt,name=attributes,proto3" json:"attributes,omitempty"`
+	ThumbnailUrl string             `protobuf:"bytes,9,opt,name=thumbnail_url,json=thumbnailUrl,proto3" json:"thumbnail_url,omitempty"`
+	// The lines of the station with their metadata, in line order.
+	LineDetails   []*Line `protobuf:"bytes,10,rep,name=line_details,json=lineDetails,proto3" json:"line_details,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Station) Reset() {
+	*x = Station{}
+	mi := &file_bus_v1_bus_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Station) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Station) ProtoMessage() {}
+
+func (x *Station) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Station.ProtoReflect.Descriptor instead.
+func (*Station) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Station) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Station) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Station) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *Station) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *Station) GetLon() float64 {
+	if x != nil {
+		return x.Lon
+	}
+	return 0
+}
+
+func (x *Station) GetCodes() []int32 {
+	if x != nil {
+		return x.Codes
+	}
+	return nil
+}
+
+func (x *Station) GetLines() []string {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *Station) GetAttributes() *StationAttributes {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *Station) GetThumbnailUrl() string {
+	if x != nil {
+		return x.ThumbnailUrl
+	}
+	return ""
+}
+
+func (x *Station) GetLineDetails() []*Line {
+	if x != nil {
+		return x.LineDetails
+	}
+	return nil
+}
+
+// Accessibility and amenities of a station. Unset fields are unknown.
+type StationAttributes struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	WheelchairAccessible *bool                  `protobuf:"varint,1,opt,name=wheelchair_accessible,json=wheelchairAccessible,proto3,oneof" json:"wheelchair_accessible,omitempty"`
+	Shelter              *bool                  `protobuf:"varint,2,opt,name=shelter,proto3,oneof" json:"shelter,omitempty"`
+	Bench                *bool                  `protobuf:"varint,3,opt,name=bench,proto3,oneof" json:"bench,omitempty"`
+	RealtimeDisplay      *bool                  `protobuf:"varint,4,opt,name=realtime_display,json=realtimeDisplay,proto3,oneof" json:"realtime_display,omitempty"`
+	TicketMachine        *bool                  `protobuf:"varint,5,opt,name=ticket_machine,json=ticketMachine,proto3,oneof" json:"ticket_machine,omitempty"`
+	TactilePaving        *bool                  `protobuf:"varint,6,opt,name=tactile_paving,json=tactilePaving,proto3,oneof" json:"tactile_paving,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *StationAttributes) Reset() {
+	*x = StationAttributes{}
+	mi := &file_bus_v1_bus_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StationAttributes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StationAttributes) ProtoMessage() {}
+
+func (x *StationAttributes) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StationAttributes.ProtoReflect.Descriptor instead.
+func (*StationAttributes) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StationAttributes) GetWheelchairAccessible() bool {
+	if x != nil && x.WheelchairAccessible != nil {
+		return *x.WheelchairAccessible
+	}
+	return false
+}
+
+func (x *StationAttributes) GetShelter() bool {
+	if x != nil && x.Shelter != nil {
+		return *x.Shelter
+	}
+	return false
+}
+
+func (x *StationAttributes) GetBench() bool {
+	if x != nil && x.Bench != nil {
+		return *x.Bench
+	}
+	return false
+}
+
+func (x *StationAttributes) GetRealtimeDisplay() bool {
+	if x != nil && x.RealtimeDisplay != nil {
+		return *x.RealtimeDisplay
+	}
+	return false
+}
+
+func (x *StationAttributes) GetTicketMachine() bool {
+	if x != nil && x.TicketMachine != nil {
+		return *x.TicketMachine
+	}
+	return false
+}
+
+func (x *StationAttributes) GetTactilePaving() bool {
+	if x != nil && x.TactilePaving != nil {
+		return *x.TactilePaving
+	}
+	return false
+}
+
+type StationRef struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// In timetables, the stop to board at or to get off at. Its lines and
+	// directions are not set.
+	Stop          *Stop `protobuf:"bytes,3,opt,name=stop,proto3" json:"stop,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StationRef) Reset() {
+	*x = StationRef{}
+	mi := &file_bus_v1_bus_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StationRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StationRef) ProtoMessage() {}
+
+func (x *StationRef) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StationRef.ProtoReflect.Descriptor instead.
+func (*StationRef) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StationRef) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *StationRef) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StationRef) GetStop() *Stop {
+	if x != nil {
+		return x.Stop
+	}
+	return nil
+}
+
+// A stop of a station, e.g. one side of the road, with its own code.
+type Stop struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Code  int32                  `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	// Letters the stops of a station in code order: A, B, ...
+	Platform string  `protobuf:"bytes,3,opt,name=platform,proto3" json:"platform,omitempty"`
+	Lat      float64 `protobuf:"fixed64,4,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lon      float64 `protobuf:"fixed64,5,opt,name=lon,proto3" json:"lon,omitempty"`
+	// Lines and directions departing from the stop.
+	Lines         []string `protobuf:"bytes,6,rep,name=lines,proto3" json:"lines,omitempty"`
+	Directions    []string `protobuf:"bytes,7,rep,name=directions,proto3" json:"directions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Stop) Reset() {
+	*x = Stop{}
+	mi := &file_bus_v1_bus_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stop) ProtoMessage() {}
+
+func (x *Stop) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stop.ProtoReflect.Descriptor instead.
+func (*Stop) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Stop) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Stop) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *Stop) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *Stop) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *Stop) GetLon() float64 {
+	if x != nil {
+		return x.Lon
+	}
+	return 0
+}
+
+func (x *Stop) GetLines() []string {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *Stop) GetDirections() []string {
+	if x != nil {
+		return x.Directions
+	}
+	return nil
+}
+
+type Line struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Category    LineCategory           `protobuf:"varint,4,opt,name=category,proto3,enum=mbus.bus.v1.LineCategory" json:"category,omitempty"`
+	// Badge background as #RRGGBB, with text_color legible on it. Empty when
+	// unknown.
+	Color     string `protobuf:"bytes,5,opt,name=color,proto3" json:"color,omitempty"`
+	TextColor string `protobuf:"bytes,6,opt,name=text_color,json=textColor,proto3" json:"text_color,omitempty"`
+	// The termini, e.g. "Avtobusna postaja - Tezno".
+	LongName string `protobuf:"bytes,7,opt,name=long_name,json=longName,proto3" json:"long_name,omitempty"`
+	Operator string `protobuf:"bytes,8,opt,name=operator,proto3" json:"operator,omitempty"`
+	// Rank in line lists; lines of equal rank keep their natural order.
+	SortOrder     int32 `protobuf:"varint,9,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Line) Reset() {
+	*x = Line{}
+	mi := &file_bus_v1_bus_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Line) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Line) ProtoMessage() {}
+
+func (x *Line) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Line.ProtoReflect.Descriptor instead.
+func (*Line) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Line) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Line) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Line) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Line) GetCategory() LineCategory {
+	if x != nil {
+		return x.Category
+	}
+	return LineCategory_LINE_CATEGORY_UNSPECIFIED
+}
+
+func (x *Line) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *Line) GetTextColor() string {
+	if x != nil {
+		return x.TextColor
+	}
+	return ""
+}
+
+func (x *Line) GetLongName() string {
+	if x != nil {
+		return x.LongName
+	}
+	return ""
+}
+
+func (x *Line) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *Line) GetSortOrder() int32 {
+	if x != nil {
+		return x.SortOrder
+	}
+	return 0
+}
+
+type GetStationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Lookup:
+	//
+	//	*GetStationRequest_Id
+	//	*GetStationRequest_Code
+	Lookup        isGetStationRequest_Lookup `protobuf_oneof:"lookup"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStationRequest) Reset() {
+	*x = GetStationRequest{}
+	mi := &file_bus_v1_bus_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStationRequest) ProtoMessage() {}
+
+func (x *GetStationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStationRequest.ProtoReflect.Descriptor instead.
+func (*GetStationRequest) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetStationRequest) GetLookup() isGetStationRequest_Lookup {
+	if x != nil {
+		return x.Lookup
+	}
+	return nil
+}
+
+func (x *GetStationRequest) GetId() int32 {
+	if x != nil {
+		if x, ok := x.Lookup.(*GetStationRequest_Id); ok {
+			return x.Id
+		}
+	}
+	return 0
+}
+
+func (x *GetStationRequest) GetCode() int32 {
+	if x != nil {
+		if x, ok := x.Lookup.(*GetStationRequest_Code); ok {
+			return x.Code
+		}
+	}
+	return 0
+}
+
+type isGetStationRequest_Lookup interface {
+	isGetStationRequest_Lookup()
+}
+
+type GetStationRequest_Id struct {
+	Id int32 `protobuf:"varint,1,opt,name=id,proto3,oneof"`
+}
+
+type GetStationRequest_Code struct {
+	Code int32 `protobuf:"varint,2,opt,name=code,proto3,oneof"`
+}
+
+func (*GetStationRequest_Id) isGetStationRequest_Lookup() {}
+
+func (*GetStationRequest_Code) isGetStationRequest_Lookup() {}
+
+type ListStationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Prefix of the station name.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Line string `protobuf:"bytes,2,opt,name=line,proto3" json:"line,omitempty"`
+	// Defaults to 10, at most 100.
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous response.
+	PageToken string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Only stations known to have all of these attributes.
+	Has           []StationAttribute `protobuf:"varint,5,rep,packed,name=has,proto3,enum=mbus.bus.v1.StationAttribute" json:"has,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStationsRequest) Reset() {
+	*x = ListStationsRequest{}
+	mi := &file_bus_v1_bus_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStationsRequest) ProtoMessage() {}
+
+func (x *ListStationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStationsRequest.ProtoReflect.Descriptor instead.
+func (*ListStationsRequest) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListStationsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListStationsRequest) GetLine() string {
+	if x != nil {
+		return x.Line
+	}
+	return ""
+}
+
+func (x *ListStationsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListStationsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListStationsRequest) GetHas() []StationAttribute {
+	if x != nil {
+		return x.Has
+	}
+	return nil
+}
+
+type ListStationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stations      []*Station             `protobuf:"bytes,1,rep,name=stations,proto3" json:"stations,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStationsResponse) Reset() {
+	*x = ListStationsResponse{}
+	mi := &file_bus_v1_bus_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStationsResponse) ProtoMessage() {}
+
+func (x *ListStationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStationsResponse.ProtoReflect.Descriptor instead.
+func (*ListStationsResponse) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListStationsResponse) GetStations() []*Station {
+	if x != nil {
+		return x.Stations
+	}
+	return nil
+}
+
+func (x *ListStationsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type ListStationStopsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StationId     int32                  `protobuf:"varint,1,opt,name=station_id,json=stationId,proto3" json:"station_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStationStopsRequest) Reset() {
+	*x = ListStationStopsRequest{}
+	mi := &file_bus_v1_bus_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStationStopsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStationStopsRequest) ProtoMessage() {}
+
+func (x *ListStationStopsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStationStopsRequest.ProtoReflect.Descriptor instead.
+func (*ListStationStopsRequest) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListStationStopsRequest) GetStationId() int32 {
+	if x != nil {
+		return x.StationId
+	}
+	return 0
+}
+
+type ListStationStopsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stops         []*Stop                `protobuf:"bytes,1,rep,name=stops,proto3" json:"stops,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStationStopsResponse) Reset() {
+	*x = ListStationStopsResponse{}
+	mi := &file_bus_v1_bus_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStationStopsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStationStopsResponse) ProtoMessage() {}
+
+func (x *ListStationStopsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStationStopsResponse.ProtoReflect.Descriptor instead.
+func (*ListStationStopsResponse) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListStationStopsResponse) GetStops() []*Stop {
+	if x != nil {
+		return x.Stops
+	}
+	return nil
+}
+
+type ListLinesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Prefix of the line name.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Only lines serving this station, if set.
+	StationId int32 `protobuf:"varint,2,opt,name=station_id,json=stationId,proto3" json:"station_id,omitempty"`
+	// Defaults to 20, at most 100.
+	PageSize      int32  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLinesRequest) Reset() {
+	*x = ListLinesRequest{}
+	mi := &file_bus_v1_bus_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLinesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinesRequest) ProtoMessage() {}
+
+func (x *ListLinesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinesRequest.ProtoReflect.Descriptor instead.
+func (*ListLinesRequest) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListLinesRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListLinesRequest) GetStationId() int32 {
+	if x != nil {
+		return x.StationId
+	}
+	return 0
+}
+
+func (x *ListLinesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListLinesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListLinesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lines         []*Line                `protobuf:"bytes,1,rep,name=lines,proto3" json:"lines,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLinesResponse) Reset() {
+	*x = ListLinesResponse{}
+	mi := &file_bus_v1_bus_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLinesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLinesResponse) ProtoMessage() {}
+
+func (x *ListLinesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLinesResponse.ProtoReflect.Descriptor instead.
+func (*ListLinesResponse) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListLinesResponse) GetLines() []*Line {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *ListLinesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetTimetableRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromStationId int32                  `protobuf:"varint,1,opt,name=from_station_id,json=fromStationId,proto3" json:"from_station_id,omitempty"`
+	ToStationId   int32                  `protobuf:"varint,2,opt,name=to_station_id,json=toStationId,proto3" json:"to_station_id,omitempty"`
+	// Service date in YYYY-MM-DD format, defaults to the current service day,
+	// which runs until 03:00 the next morning.
+	Date string `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+	// Only departures at or after this time (HH:MM or "now").
+	After string `protobuf:"bytes,4,opt,name=after,proto3" json:"after,omitempty"`
+	// Only departures at or before this time (HH:MM).
+	Before string   `protobuf:"bytes,5,opt,name=before,proto3" json:"before,omitempty"`
+	Lines  []string `protobuf:"bytes,6,rep,name=lines,proto3" json:"lines,omitempty"`
+	// Only directions containing this text.
+	Direction string `protobuf:"bytes,7,opt,name=direction,proto3" json:"direction,omitempty"`
+	// Maximum number of departures, 0 for all.
+	Limit int32 `protobuf:"varint,8,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Only journeys between wheelchair accessible stations.
+	StepFree      bool `protobuf:"varint,9,opt,name=step_free,json=stepFree,proto3" json:"step_free,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTimetableRequest) Reset() {
+	*x = GetTimetableRequest{}
+	mi := &file_bus_v1_bus_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTimetableRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTimetableRequest) ProtoMessage() {}
+
+func (x *GetTimetableRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTimetableRequest.ProtoReflect.Descriptor instead.
+func (*GetTimetableRequest) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetTimetableRequest) GetFromStationId() int32 {
+	if x != nil {
+		return x.FromStationId
+	}
+	return 0
+}
+
+func (x *GetTimetableRequest) GetToStationId() int32 {
+	if x != nil {
+		return x.ToStationId
+	}
+	return 0
+}
+
+func (x *GetTimetableRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *GetTimetableRequest) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+func (x *GetTimetableRequest) GetBefore() string {
+	if x != nil {
+		return x.Before
+	}
+	return ""
+}
+
+func (x *GetTimetableRequest) GetLines() []string {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *GetTimetableRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *GetTimetableRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetTimetableRequest) GetStepFree() bool {
+	if x != nil {
+		return x.StepFree
+	}
+	return false
+}
+
+type GetTimetableResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Schedule      ScheduleType           `protobuf:"varint,2,opt,name=schedule,proto3,enum=mbus.bus.v1.ScheduleType" json:"schedule,omitempty"`
+	Rows          []*TimetableRow        `protobuf:"bytes,3,rep,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTimetableResponse) Reset() {
+	*x = GetTimetableResponse{}
+	mi := &file_bus_v1_bus_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTimetableResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTimetableResponse) ProtoMessage() {}
+
+func (x *GetTimetableResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTimetableResponse.ProtoReflect.Descriptor instead.
+func (*GetTimetableResponse) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetTimetableResponse) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *GetTimetableResponse) GetSchedule() ScheduleType {
+	if x != nil {
+		return x.Schedule
+	}
+	return ScheduleType_SCHEDULE_TYPE_UNSPECIFIED
+}
+
+func (x *GetTimetableResponse) GetRows() []*TimetableRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+type TimetableRow struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Line           string                 `protobuf:"bytes,2,opt,name=line,proto3" json:"line,omitempty"`
+	Direction      string                 `protobuf:"bytes,3,opt,name=direction,proto3" json:"direction,omitempty"`
+	From           *StationRef            `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+	To             *StationRef            `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`
+	DepartureTime  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=departure_time,json=departureTime,proto3" json:"departure_time,omitempty"`
+	ArrivalTime    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=arrival_time,json=arrivalTime,proto3" json:"arrival_time,omitempty"`
+	Duration       *durationpb.Duration   `protobuf:"bytes,8,opt,name=duration,proto3" json:"duration,omitempty"`
+	DistanceMeters int32                  `protobuf:"varint,9,opt,name=distance_meters,json=distanceMeters,proto3" json:"distance_meters,omitempty"`
+	// Set when distance and travel time come from the offline estimator
+	// instead of the routing provider.
+	Estimated bool `protobuf:"varint,10,opt,name=estimated,proto3" json:"estimated,omitempty"`
+	// Set when both stations are known to be wheelchair accessible.
+	StepFree bool `protobuf:"varint,11,opt,name=step_free,json=stepFree,proto3" json:"step_free,omitempty"`
+	// The line with its metadata, if known. Its description is not set.
+	LineDetails   *Line `protobuf:"bytes,12,opt,name=line_details,json=lineDetails,proto3" json:"line_details,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimetableRow) Reset() {
+	*x = TimetableRow{}
+	mi := &file_bus_v1_bus_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimetableRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimetableRow) ProtoMessage() {}
+
+func (x *TimetableRow) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimetableRow.ProtoReflect.Descriptor instead.
+func (*TimetableRow) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TimetableRow) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TimetableRow) GetLine() string {
+	if x != nil {
+		return x.Line
+	}
+	return ""
+}
+
+func (x *TimetableRow) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *TimetableRow) GetFrom() *StationRef {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *TimetableRow) GetTo() *StationRef {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *TimetableRow) GetDepartureTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DepartureTime
+	}
+	return nil
+}
+
+func (x *TimetableRow) GetArrivalTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArrivalTime
+	}
+	return nil
+}
+
+func (x *TimetableRow) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *TimetableRow) GetDistanceMeters() int32 {
+	if x != nil {
+		return x.DistanceMeters
+	}
+	return 0
+}
+
+func (x *TimetableRow) GetEstimated() bool {
+	if x != nil {
+		return x.Estimated
+	}
+	return false
+}
+
+func (x *TimetableRow) GetStepFree() bool {
+	if x != nil {
+		return x.StepFree
+	}
+	return false
+}
+
+func (x *TimetableRow) GetLineDetails() *Line {
+	if x != nil {
+		return x.LineDetails
+	}
+	return nil
+}
+
+type WatchDepartureBoardRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	StationId int32                  `protobuf:"varint,1,opt,name=station_id,json=stationId,proto3" json:"station_id,omitempty"`
+	// Number of departures on the board. Defaults to 10, at most 50.
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Only these lines, if set.
+	Lines []string `protobuf:"bytes,3,rep,name=lines,proto3" json:"lines,omitempty"`
+	// How often the board is recomputed. Defaults to 30s, at least 10s.
+	Refresh       *durationpb.Duration `protobuf:"bytes,4,opt,name=refresh,proto3" json:"refresh,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchDepartureBoardRequest) Reset() {
+	*x = WatchDepartureBoardRequest{}
+	mi := &file_bus_v1_bus_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchDepartureBoardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchDepartureBoardRequest) ProtoMessage() {}
+
+func (x *WatchDepartureBoardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchDepartureBoardRequest.ProtoReflect.Descriptor instead.
+func (*WatchDepartureBoardRequest) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *WatchDepartureBoardRequest) GetStationId() int32 {
+	if x != nil {
+		return x.StationId
+	}
+	return 0
+}
+
+func (x *WatchDepartureBoardRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *WatchDepartureBoardRequest) GetLines() []string {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *WatchDepartureBoardRequest) GetRefresh() *durationpb.Duration {
+	if x != nil {
+		return x.Refresh
+	}
+	return nil
+}
+
+type DepartureBoard struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Station       *StationRef            `protobuf:"bytes,1,opt,name=station,proto3" json:"station,omitempty"`
+	GeneratedAt   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=generated_at,json=generatedAt,proto3" json:"generated_at,omitempty"`
+	Departures    []*BoardDeparture      `protobuf:"bytes,3,rep,name=departures,proto3" json:"departures,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DepartureBoard) Reset() {
+	*x = DepartureBoard{}
+	mi := &file_bus_v1_bus_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DepartureBoard) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepartureBoard) ProtoMessage() {}
+
+func (x *DepartureBoard) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepartureBoard.ProtoReflect.Descriptor instead.
+func (*DepartureBoard) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DepartureBoard) GetStation() *StationRef {
+	if x != nil {
+		return x.Station
+	}
+	return nil
+}
+
+func (x *DepartureBoard) GetGeneratedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.GeneratedAt
+	}
+	return nil
+}
+
+func (x *DepartureBoard) GetDepartures() []*BoardDeparture {
+	if x != nil {
+		return x.Departures
+	}
+	return nil
+}
+
+type BoardDeparture struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Line      string                 `protobuf:"bytes,2,opt,name=line,proto3" json:"line,omitempty"`
+	Direction string                 `protobuf:"bytes,3,opt,name=direction,proto3" json:"direction,omitempty"`
+	// Stop code the bus leaves from.
+	Code          int32                  `protobuf:"varint,4,opt,name=code,proto3" json:"code,omitempty"`
+	DepartureTime *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=departure_time,json=departureTime,proto3" json:"departure_time,omitempty"`
+	// Platform of the stop the bus leaves from.
+	Platform      string `protobuf:"bytes,6,opt,name=platform,proto3" json:"platform,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BoardDeparture) Reset() {
+	*x = BoardDeparture{}
+	mi := &file_bus_v1_bus_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BoardDeparture) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoardDeparture) ProtoMessage() {}
+
+func (x *BoardDeparture) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_bus_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoardDeparture.ProtoReflect.Descriptor instead.
+func (*BoardDeparture) Descriptor() ([]byte, []int) {
+	return file_bus_v1_bus_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BoardDeparture) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BoardDeparture) GetLine() string {
+	if x != nil {
+		return x.Line
+	}
+	return ""
+}
+
+func (x *BoardDeparture) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *BoardDeparture) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *BoardDeparture) GetDepartureTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DepartureTime
+	}
+	return nil
+}
+
+func (x *BoardDeparture) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+var File_bus_v1_bus_proto protoreflect.FileDescriptor
+
+const file_bus_v1_bus_proto_rawDesc = "" +
+	"\n" +
+	"\x10bus/v1/bus.proto\x12\vmbus.bus.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb5\x02\n" +
+	"\aStation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1b\n" +
+	"\timage_url\x18\x03 \x01(\tR\bimageUrl\x12\x10\n" +
+	"\x03lat\x18\x04 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lon\x18\x05 \x01(\x01R\x03lon\x12\x14\n" +
+	"\x05codes\x18\x06 \x03(\x05R\x05codes\x12\x14\n" +
+	"\x05lines\x18\a \x03(\tR\x05lines\x12>\n" +
+	"\n" +
+	"attributes\x18\b \x01(\v2\x1e.mbus.bus.v1.StationAttributesR\n" +
+	"attributes\x12#\n" +
+	"\rthumbnail_url\x18\t \x01(\tR\fthumbnailUrl\x124\n" +
+	"\fline_details\x18\n" +
+	" \x03(\v2\x11.mbus.bus.v1.LineR\vlineDetails\"\xfa\x02\n" +
+	"\x11StationAttributes\x128\n" +
+	"\x15wheelchair_accessible\x18\x01 \x01(\bH\x00R\x14wheelchairAccessible\x88\x01\x01\x12\x1d\n" +
+	"\ashelter\x18\x02 \x01(\bH\x01R\ashelter\x88\x01\x01\x12\x19\n" +
+	"\x05bench\x18\x03 \x01(\bH\x02R\x05bench\x88\x01\x01\x12.\n" +
+	"\x10realtime_display\x18\x04 \x01(\bH\x03R\x0frealtimeDisplay\x88\x01\x01\x12*\n" +
+	"\x0eticket_machine\x18\x05 \x01(\bH\x04R\rticketMachine\x88\x01\x01\x12*\n" +
+	"\x0etactile_paving\x18\x06 \x01(\bH\x05R\rtactilePaving\x88\x01\x01B\x18\n" +
+	"\x16_wheelchair_accessibleB\n" +
+	"\n" +
+	"\b_shelterB\b\n" +
+	"\x06_benchB\x13\n" +
+	"\x11_realtime_displayB\x11\n" +
+	"\x0f_ticket_machineB\x11\n" +
+	"\x0f_tactile_paving\"W\n" +
+	"\n" +
+	"StationRef\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12%\n" +
+	"\x04stop\x18\x03 \x01(\v2\x11.mbus.bus.v1.StopR\x04stop\"\xa0\x01\n" +
+	"\x04Stop\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\x05R\x04code\x12\x1a\n" +
+	"\bplatform\x18\x03 \x01(\tR\bplatform\x12\x10\n" +
+	"\x03lat\x18\x04 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lon\x18\x05 \x01(\x01R\x03lon\x12\x14\n" +
+	"\x05lines\x18\x06 \x03(\tR\x05lines\x12\x1e\n" +
+	"\n" +
+	"directions\x18\a \x03(\tR\n" +
+	"directions\"\x90\x02\n" +
+	"\x04Line\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x125\n" +
+	"\bcategory\x18\x04 \x01(\x0e2\x19.mbus.bus.v1.LineCategoryR\bcategory\x12\x14\n" +
+	"\x05color\x18\x05 \x01(\tR\x05color\x12\x1d\n" +
+	"\n" +
+	"text_color\x18\x06 \x01(\tR\ttextColor\x12\x1b\n" +
+	"\tlong_name\x18\a \x01(\tR\blongName\x12\x1a\n" +
+	"\boperator\x18\b \x01(\tR\boperator\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\t \x01(\x05R\tsortOrder\"E\n" +
+	"\x11GetStationRequest\x12\x10\n" +
+	"\x02id\x18\x01 \x01(\x05H\x00R\x02id\x12\x14\n" +
+	"\x04code\x18\x02 \x01(\x05H\x00R\x04codeB\b\n" +
+	"\x06lookup\"\xaa\x01\n" +
+	"\x13ListStationsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04line\x18\x02 \x01(\tR\x04line\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12/\n" +
+	"\x03has\x18\x05 \x03(\x0e2\x1d.mbus.bus.v1.StationAttributeR\x03has\"p\n" +
+	"\x14ListStationsResponse\x120\n" +
+	"\bstations\x18\x01 \x03(\v2\x14.mbus.bus.v1.StationR\bstations\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"8\n" +
+	"\x17ListStationStopsRequest\x12\x1d\n" +
+	"\n" +
+	"station_id\x18\x01 \x01(\x05R\tstationId\"C\n" +
+	"\x18ListStationStopsResponse\x12'\n" +
+	"\x05stops\x18\x01 \x03(\v2\x11.mbus.bus.v1.StopR\x05stops\"\x81\x01\n" +
+	"\x10ListLinesRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"station_id\x18\x02 \x01(\x05R\tstationId\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"d\n" +
+	"\x11ListLinesResponse\x12'\n" +
+	"\x05lines\x18\x01 \x03(\v2\x11.mbus.bus.v1.LineR\x05lines\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x8a\x02\n" +
+	"\x13GetTimetableRequest\x12&\n" +
+	"\x0ffrom_station_id\x18\x01 \x01(\x05R\rfromStationId\x12\"\n" +
+	"\rto_station_id\x18\x02 \x01(\x05R\vtoStationId\x12\x12\n" +
+	"\x04date\x18\x03 \x01(\tR\x04date\x12\x14\n" +
+	"\x05after\x18\x04 \x01(\tR\x05after\x12\x16\n" +
+	"\x06before\x18\x05 \x01(\tR\x06before\x12\x14\n" +
+	"\x05lines\x18\x06 \x03(\tR\x05lines\x12\x1c\n" +
+	"\tdirection\x18\a \x01(\tR\tdirection\x12\x14\n" +
+	"\x05limit\x18\b \x01(\x05R\x05limit\x12\x1b\n" +
+	"\tstep_free\x18\t \x01(\bR\bstepFree\"\x90\x01\n" +
+	"\x14GetTimetableResponse\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x125\n" +
+	"\bschedule\x18\x02 \x01(\x0e2\x19.mbus.bus.v1.ScheduleTypeR\bschedule\x12-\n" +
+	"\x04rows\x18\x03 \x03(\v2\x19.mbus.bus.v1.TimetableRowR\x04rows\"\xf9\x03\n" +
+	"\fTimetableRow\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04line\x18\x02 \x01(\tR\x04line\x12\x1c\n" +
+	"\tdirection\x18\x03 \x01(\tR\tdirection\x12+\n" +
+	"\x04from\x18\x04 \x01(\v2\x17.mbus.bus.v1.StationRefR\x04from\x12'\n" +
+	"\x02to\x18\x05 \x01(\v2\x17.mbus.bus.v1.StationRefR\x02to\x12A\n" +
+	"\x0edeparture_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\rdepartureTime\x12=\n" +
+	"\farrival_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\varrivalTime\x125\n" +
+	"\bduration\x18\b \x01(\v2\x19.google.protobuf.DurationR\bduration\x12'\n" +
+	"\x0fdistance_meters\x18\t \x01(\x05R\x0edistanceMeters\x12\x1c\n" +
+	"\testimated\x18\n" +
+	" \x01(\bR\testimated\x12\x1b\n" +
+	"\tstep_free\x18\v \x01(\bR\bstepFree\x124\n" +
+	"\fline_details\x18\f \x01(\v2\x11.mbus.bus.v1.LineR\vlineDetails\"\x9c\x01\n" +
+	"\x1aWatchDepartureBoardRequest\x12\x1d\n" +
+	"\n" +
+	"station_id\x18\x01 \x01(\x05R\tstationId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x14\n" +
+	"\x05lines\x18\x03 \x03(\tR\x05lines\x123\n" +
+	"\arefresh\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\arefresh\"\xbf\x01\n" +
+	"\x0eDepartureBoard\x121\n" +
+	"\astation\x18\x01 \x01(\v2\x17.mbus.bus.v1.StationRefR\astation\x12=\n" +
+	"\fgenerated_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vgeneratedAt\x12;\n" +
+	"\n" +
+	"departures\x18\x03 \x03(\v2\x1b.mbus.bus.v1.BoardDepartureR\n" +
+	"departures\"\xc5\x01\n" +
+	"\x0eBoardDeparture\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04line\x18\x02 \x01(\tR\x04line\x12\x1c\n" +
+	"\tdirection\x18\x03 \x01(\tR\tdirection\x12\x12\n" +
+	"\x04code\x18\x04 \x01(\x05R\x04code\x12A\n" +
+	"\x0edeparture_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\rdepartureTime\x12\x1a\n" +
+	"\bplatform\x18\x06 \x01(\tR\bplatform*\x92\x02\n" +
+	"\x10StationAttribute\x12!\n" +
+	"\x1dSTATION_ATTRIBUTE_UNSPECIFIED\x10\x00\x12+\n" +
+	"'STATION_ATTRIBUTE_WHEELCHAIR_ACCESSIBLE\x10\x01\x12\x1d\n" +
+	"\x19STATION_ATTRIBUTE_SHELTER\x10\x02\x12\x1b\n" +
+	"\x17STATION_ATTRIBUTE_BENCH\x10\x03\x12&\n" +
+	"\"STATION_ATTRIBUTE_REALTIME_DISPLAY\x10\x04\x12$\n" +
+	" STATION_ATTRIBUTE_TICKET_MACHINE\x10\x05\x12$\n" +
+	" STATION_ATTRIBUTE_TACTILE_PAVING\x10\x06*z\n" +
+	"\fLineCategory\x12\x1d\n" +
+	"\x19LINE_CATEGORY_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12LINE_CATEGORY_CITY\x10\x01\x12\x1a\n" +
+	"\x16LINE_CATEGORY_SUBURBAN\x10\x02\x12\x17\n" +
+	"\x13LINE_CATEGORY_OTHER\x10\x03*~\n" +
+	"\fScheduleType\x12\x1d\n" +
+	"\x19SCHEDULE_TYPE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15SCHEDULE_TYPE_WEEKDAY\x10\x01\x12\x1a\n" +
+	"\x16SCHEDULE_TYPE_SATURDAY\x10\x02\x12\x18\n" +
+	"\x14SCHEDULE_TYPE_SUNDAY\x10\x032\x86\x04\n" +
+	"\n" +
+	"BusService\x12B\n" +
+	"\n" +
+	"GetStation\x12\x1e.mbus.bus.v1.GetStationRequest\x1a\x14.mbus.bus.v1.Station\x12S\n" +
+	"\fListStations\x12 .mbus.bus.v1.ListStationsRequest\x1a!.mbus.bus.v1.ListStationsResponse\x12_\n" +
+	"\x10ListStationStops\x12$.mbus.bus.v1.ListStationStopsRequest\x1a%.mbus.bus.v1.ListStationStopsResponse\x12J\n" +
+	"\tListLines\x12\x1d.mbus.bus.v1.ListLinesRequest\x1a\x1e.mbus.bus.v1.ListLinesResponse\x12S\n" +
+	"\fGetTimetable\x12 .mbus.bus.v1.GetTimetableRequest\x1a!.mbus.bus.v1.GetTimetableResponse\x12]\n" +
+	"\x13WatchDepartureBoard\x12'.mbus.bus.v1.WatchDepartureBoardRequest\x1a\x1b.mbus.bus.v1.DepartureBoard0\x01BCZAgithub.com/perkzen/mbus/apps/bus-service/internal/pb/bus/v1;busv1b\x06proto3"
+
+var (
+	file_bus_v1_bus_proto_rawDescOnce sync.Once
+	file_bus_v1_bus_proto_rawDescData []byte
+)
+
+func file_bus_v1_bus_proto_rawDescGZIP() []byte {
+	file_bus_v1_bus_proto_rawDescOnce.Do(func() {
+		file_bus_v1_bus_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_bus_v1_bus_proto_rawDesc), len(file_bus_v1_bus_proto_rawDesc)))
+	})
+	return file_bus_v1_bus_proto_rawDescData
+}
+
+var file_bus_v1_bus_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_bus_v1_bus_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_bus_v1_bus_proto_goTypes = []any{
+	(StationAttribute)(0),              // 0: mbus.bus.v1.StationAttribute
+	(LineCategory)(0),                  // 1: mbus.bus.v1.LineCategory
+	(ScheduleType)(0),                  // 2: mbus.bus.v1.ScheduleType
+	(*Station)(nil),                    // 3: mbus.bus.v1.Station
+	(*StationAttributes)(nil),          // 4: mbus.bus.v1.StationAttributes
+	(*StationRef)(nil),                 // 5: mbus.bus.v1.StationRef
+	(*Stop)(nil),                       // 6: mbus.bus.v1.Stop
+	(*Line)(nil),                       // 7: mbus.bus.v1.Line
+	(*GetStationRequest)(nil),          // 8: mbus.bus.v1.GetStationRequest
+	(*ListStationsRequest)(nil),        // 9: mbus.bus.v1.ListStationsRequest
+	(*ListStationsResponse)(nil),       // 10: mbus.bus.v1.ListStationsResponse
+	(*ListStationStopsRequest)(nil),    // 11: mbus.bus.v1.ListStationStopsRequest
+	(*ListStationStopsResponse)(nil),   // 12: mbus.bus.v1.ListStationStopsResponse
+	(*ListLinesRequest)(nil),           // 13: mbus.bus.v1.ListLinesRequest
+	(*ListLinesResponse)(nil),          // 14: mbus.bus.v1.ListLinesResponse
+	(*GetTimetableRequest)(nil),        // 15: mbus.bus.v1.GetTimetableRequest
+	(*GetTimetableResponse)(nil),       // 16: mbus.bus.v1.GetTimetableResponse
+	(*TimetableRow)(nil),               // 17: mbus.bus.v1.TimetableRow
+	(*WatchDepartureBoardRequest)(nil), // 18: mbus.bus.v1.WatchDepartureBoardRequest
+	(*DepartureBoard)(nil),             // 19: mbus.bus.v1.DepartureBoard
+	(*BoardDeparture)(nil),             // 20: mbus.bus.v1.BoardDeparture
+	(*timestamppb.Timestamp)(nil),      // 21: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),        // 22: google.protobuf.Duration
+}
+var file_bus_v1_bus_proto_depIdxs = []int32{
+	4,  // 0: mbus.bus.v1.Station.attributes:type_name -> mbus.bus.v1.StationAttributes
+	7,  // 1: mbus.bus.v1.Station.line_details:type_name -> mbus.bus.v1.Line
+	6,  // 2: mbus.bus.v1.StationRef.stop:type_name -> mbus.bus.v1.Stop
+	1,  // 3: mbus.bus.v1.Line.category:type_name -> mbus.bus.v1.LineCategory
+	0,  // 4: mbus.bus.v1.ListStationsRequest.has:type_name -> mbus.bus.v1.StationAttribute
+	3,  // 5: mbus.bus.v1.ListStationsResponse.stations:type_name -> mbus.bus.v1.Station
+	6,  // 6: mbus.bus.v1.ListStationStopsResponse.stops:type_name -> mbus.bus.v1.Stop
+	7,  // 7: mbus.bus.v1.ListLinesResponse.lines:type_name -> mbus.bus.v1.Line
+	2,  // 8: mbus.bus.v1.GetTimetableResponse.schedule:type_name -> mbus.bus.v1.ScheduleType
+	17, // 9: mbus.bus.v1.GetTimetableResponse.rows:type_name -> mbus.bus.v1.TimetableRow
+	5,  // 10: mbus.bus.v1.TimetableRow.from:type_name -> mbus.bus.v1.StationRef
+	5,  // 11: mbus.bus.v1.TimetableRow.to:type_name -> mbus.bus.v1.StationRef
+	21, // 12: mbus.bus.v1.TimetableRow.departure_time:type_name -> google.protobuf.Timestamp
+	21, // 13: mbus.bus.v1.TimetableRow.arrival_time:type_name -> google.protobuf.Timestamp
+	22, // 14: mbus.bus.v1.TimetableRow.duration:type_name -> google.protobuf.Duration
+	7,  // 15: mbus.bus.v1.TimetableRow.line_details:type_name -> mbus.bus.v1.Line
+	22, // 16: mbus.bus.v1.WatchDepartureBoardRequest.refresh:type_name -> google.protobuf.Duration
+	5,  // 17: mbus.bus.v1.DepartureBoard.station:type_name -> mbus.bus.v1.StationRef
+	21, // 18: mbus.bus.v1.DepartureBoard.generated_at:type_name -> google.protobuf.Timestamp
+	20, // 19: mbus.bus.v1.DepartureBoard.departures:type_name -> mbus.bus.v1.BoardDeparture
+	21, // 20: mbus.bus.v1.BoardDeparture.departure_time:type_name -> google.protobuf.Timestamp
+	8,  // 21: mbus.bus.v1.BusService.GetStation:input_type -> mbus.bus.v1.GetStationRequest
+	9,  // 22: mbus.bus.v1.BusService.ListStations:input_type -> mbus.bus.v1.ListStationsRequest
+	11, // 23: mbus.bus.v1.BusService.ListStationStops:input_type -> mbus.bus.v1.ListStationStopsRequest
+	13, // 24: mbus.bus.v1.BusService.ListLines:input_type -> mbus.bus.v1.ListLinesRequest
+	15, // 25: mbus.bus.v1.BusService.GetTimetable:input_type -> mbus.bus.v1.GetTimetableRequest
+	18, // 26: mbus.bus.v1.BusService.WatchDepartureBoard:input_type -> mbus.bus.v1.WatchDepartureBoardRequest
+	3,  // 27: mbus.bus.v1.BusService.GetStation:output_type -> mbus.bus.v1.Station
+	10, // 28: mbus.bus.v1.BusService.ListStations:output_type -> mbus.bus.v1.ListStationsResponse
+	12, // 29: mbus.bus.v1.BusService.ListStationStops:output_type -> mbus.bus.v1.ListStationStopsResponse
+	14, // 30: mbus.bus.v1.BusService.ListLines:output_type -> mbus.bus.v1.ListLinesResponse
+	16, // 31: mbus.bus.v1.BusService.GetTimetable:output_type -> mbus.bus.v1.GetTimetableResponse
+	19, // 32: mbus.bus.v1.BusService.WatchDepartureBoard:output_type -> mbus.bus.v1.DepartureBoard
+	27, // [27:33] is the sub-list for method output_type
+	21, // [21:27] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
+}
+
+func init() { file_bus_v1_bus_proto_init() }
+func file_bus_v1_bus_proto_init() {
+	if File_bus_v1_bus_proto != nil {
+		return
+	}
+	file_bus_v1_bus_proto_msgTypes[1].OneofWrappers = []any{}
+	file_bus_v1_bus_proto_msgTypes[5].OneofWrappers = []any{
+		(*GetStationRequest_Id)(nil),
+		(*GetStationRequest_Code)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_bus_v1_bus_proto_rawDesc), len(file_bus_v1_bus_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_bus_v1_bus_proto_goTypes,
+		DependencyIndexes: file_bus_v1_bus_proto_depIdxs,
+		EnumInfos:         file_bus_v1_bus_proto_enumTypes,
+		MessageInfos:      file_bus_v1_bus_proto_msgTypes,
+	}.Build()
+	File_bus_v1_bus_proto = out.File
+	file_bus_v1_bus_proto_goTypes = nil
+	file_bus_v1_bus_proto_depIdxs = nil
+}
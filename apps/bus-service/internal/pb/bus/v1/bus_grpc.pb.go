@@ -0,0 +1,349 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: bus/v1/bus.proto
+
+package busv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BusService_GetStation_FullMethodName          = "/mbus.bus.v1.BusService/GetStation"
+	BusService_ListStations_FullMethodName        = "/mbus.bus.v1.BusService/ListStations"
+	BusService_ListStationStops_FullMethodName    = "/mbus.bus.v1.BusService/ListStationStops"
+	BusService_ListLines_FullMethodName           = "/mbus.bus.v1.BusService/ListLines"
+	BusService_GetTimetable_FullMethodName        = "/mbus.bus.v1.BusService/GetTimetable"
+	BusService_WatchDepartureBoard_FullMethodName = "/mbus.bus.v1.BusService/WatchDepartureBoard"
+)
+
+// BusServiceClient is the client API for BusService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// BusService exposes the bus network to internal consumers. Messages are
+// localized from the "accept-language" metadata (sl or en).
+//
+// Errors use the canonical status codes; field violations are attached as
+// google.rpc.BadRequest details and the problem code as google.rpc.ErrorInfo.
+type BusServiceClient interface {
+	// GetStation looks up a station by id or by one of its stop codes.
+	GetStation(ctx context.Context, in *GetStationRequest, opts ...grpc.CallOption) (*Station, error)
+	// ListStations returns a page of stations ordered by name.
+	ListStations(ctx context.Context, in *ListStationsRequest, opts ...grpc.CallOption) (*ListStationsResponse, error)
+	// ListStationStops returns the stops of a station, one per stop code, in
+	// code order.
+	ListStationStops(ctx context.Context, in *ListStationStopsRequest, opts ...grpc.CallOption) (*ListStationStopsResponse, error)
+	// ListLines returns a page of lines by sort order, then in natural order
+	// (G1, G2, ..., P19).
+	ListLines(ctx context.Context, in *ListLinesRequest, opts ...grpc.CallOption) (*ListLinesResponse, error)
+	// GetTimetable returns the departures between two stations on a service
+	// date.
+	GetTimetable(ctx context.Context, in *GetTimetableRequest, opts ...grpc.CallOption) (*GetTimetableResponse, error)
+	// WatchDepartureBoard streams the next departures from a station. A board
+	// is sent immediately and then whenever it changes, checked every refresh
+	// interval, until the client cancels.
+	WatchDepartureBoard(ctx context.Context, in *WatchDepartureBoardRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DepartureBoard], error)
+}
+
+type busServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBusServiceClient(cc grpc.ClientConnInterface) BusServiceClient {
+	return &busServiceClient{cc}
+}
+
+func (c *busServiceClient) GetStation(ctx context.Context, in *GetStationRequest, opts ...grpc.CallOption) (*Station, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Station)
+	err := c.cc.Invoke(ctx, BusService_GetStation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *busServiceClient) ListStations(ctx context.Context, in *ListStationsRequest, opts ...grpc.CallOption) (*ListStationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListStationsResponse)
+	err := c.cc.Invoke(ctx, BusService_ListStations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *busServiceClient) ListStationStops(ctx context.Context, in *ListStationStopsRequest, opts ...grpc.CallOption) (*ListStationStopsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListStationStopsResponse)
+	err := c.cc.Invoke(ctx, BusService_ListStationStops_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *busServiceClient) ListLines(ctx context.Context, in *ListLinesRequest, opts ...grpc.CallOption) (*ListLinesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLinesResponse)
+	err := c.cc.Invoke(ctx, BusService_ListLines_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *busServiceClient) GetTimetable(ctx context.Context, in *GetTimetableRequest, opts ...grpc.CallOption) (*GetTimetableResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTimetableResponse)
+	err := c.cc.Invoke(ctx, BusService_GetTimetable_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *busServiceClient) WatchDepartureBoard(ctx context.Context, in *WatchDepartureBoardRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DepartureBoard], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BusService_ServiceDesc.Streams[0], BusService_WatchDepartureBoard_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchDepartureBoardRequest, DepartureBoard]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BusService_WatchDepartureBoardClient = grpc.ServerStreamingClient[DepartureBoard]
+
+// BusServiceServer is the server API for BusService service.
+// All implementations must embed UnimplementedBusServiceServer
+// for forward compatibility.
+//
+// BusService exposes the bus network to internal consumers. Messages are
+// localized from the "accept-language" metadata (sl or en).
+//
+// Errors use the canonical status codes; field violations are attached as
+// google.rpc.BadRequest details and the problem code as google.rpc.ErrorInfo.
+type BusServiceServer interface {
+	// GetStation looks up a station by id or by one of its stop codes.
+	GetStation(context.Context, *GetStationRequest) (*Station, error)
+	// ListStations returns a page of stations ordered by name.
+	ListStations(context.Context, *ListStationsRequest) (*ListStationsResponse, error)
+	// ListStationStops returns the stops of a station, one per stop code, in
+	// code order.
+	ListStationStops(context.Context, *ListStationStopsRequest) (*ListStationStopsResponse, error)
+	// ListLines returns a page of lines by sort order, then in natural order
+	// (G1, G2, ..., P19).
+	ListLines(context.Context, *ListLinesRequest) (*ListLinesResponse, error)
+	// GetTimetable returns the departures between two stations on a service
+	// date.
+	GetTimetable(context.Context, *GetTimetableRequest) (*GetTimetableResponse, error)
+	// WatchDepartureBoard streams the next departures from a station. A board
+	// is sent immediately and then whenever it changes, checked every refresh
+	// interval, until the client cancels.
+	WatchDepartureBoard(*WatchDepartureBoardRequest, grpc.ServerStreamingServer[DepartureBoard]) error
+	mustEmbedUnimplementedBusServiceServer()
+}
+
+// UnimplementedBusServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBusServiceServer struct{}
+
+func (UnimplementedBusServiceServer) GetStation(context.Context, *GetStationRequest) (*Station, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStation not implemented")
+}
+func (UnimplementedBusServiceServer) ListStations(context.Context, *ListStationsRequest) (*ListStationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStations not implemented")
+}
+func (UnimplementedBusServiceServer) ListStationStops(context.Context, *ListStationStopsRequest) (*ListStationStopsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStationStops not implemented")
+}
+func (UnimplementedBusServiceServer) ListLines(context.Context, *ListLinesRequest) (*ListLinesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLines not implemented")
+}
+func (UnimplementedBusServiceServer) GetTimetable(context.Context, *GetTimetableRequest) (*GetTimetableResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTimetable not implemented")
+}
+func (UnimplementedBusServiceServer) WatchDepartureBoard(*WatchDepartureBoardRequest, grpc.ServerStreamingServer[DepartureBoard]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchDepartureBoard not implemented")
+}
+func (UnimplementedBusServiceServer) mustEmbedUnimplementedBusServiceServer() {}
+func (UnimplementedBusServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeBusServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BusServiceServer will
+// result in compilation errors.
+type UnsafeBusServiceServer interface {
+	mustEmbedUnimplementedBusServiceServer()
+}
+
+func RegisterBusServiceServer(s grpc.ServiceRegistrar, srv BusServiceServer) {
+	// If the following call pancis, it indicates UnimplementedBusServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BusService_ServiceDesc, srv)
+}
+
+func _BusService_GetStation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusServiceServer).GetStation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusService_GetStation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusServiceServer).GetStation(ctx, req.(*GetStationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusService_ListStations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusServiceServer).ListStations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusService_ListStations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusServiceServer).ListStations(ctx, req.(*ListStationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusService_ListStationStops_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStationStopsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusServiceServer).ListStationStops(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusService_ListStationStops_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusServiceServer).ListStationStops(ctx, req.(*ListStationStopsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusService_ListLines_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLinesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusServiceServer).ListLines(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusService_ListLines_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusServiceServer).ListLines(ctx, req.(*ListLinesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusService_GetTimetable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTimetableRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusServiceServer).GetTimetable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusService_GetTimetable_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusServiceServer).GetTimetable(ctx, req.(*GetTimetableRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusService_WatchDepartureBoard_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchDepartureBoardRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BusServiceServer).WatchDepartureBoard(m, &grpc.GenericServerStream[WatchDepartureBoardRequest, DepartureBoard]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BusService_WatchDepartureBoardServer = grpc.ServerStreamingServer[DepartureBoard]
+
+// BusService_ServiceDesc is the grpc.ServiceDesc for BusService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BusService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mbus.bus.v1.BusService",
+	HandlerType: (*BusServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStation",
+			Handler:    _BusService_GetStation_Handler,
+		},
+		{
+			MethodName: "ListStations",
+			Handler:    _BusService_ListStations_Handler,
+		},
+		{
+			MethodName: "ListStationStops",
+			Handler:    _BusService_ListStationStops_Handler,
+		},
+		{
+			MethodName: "ListLines",
+			Handler:    _BusService_ListLines_Handler,
+		},
+		{
+			MethodName: "GetTimetable",
+			Handler:    _BusService_GetTimetable_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchDepartureBoard",
+			Handler:       _BusService_WatchDepartureBoard_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "bus/v1/bus.proto",
+}
@@ -0,0 +1,166 @@
+package estimator
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Modes decide when the service uses the estimator instead of the routing
+// provider.
+const (
+	ModeFallback  = "fallback"  // use the estimator only when routing fails
+	ModeExclusive = "exclusive" // never call the routing provider
+	ModeDisabled  = "disabled"  // routing errors fail the request
+)
+
+const (
+	// Calibrated speeds outside this range are treated as bad data.
+	minSpeedKmh = 8.0
+	maxSpeedKmh = 60.0
+
+	// Consecutive stops further apart than this in the first trip of the day
+	// most likely belong to different trips and are skipped.
+	maxStopGapMinutes = 15.0
+
+	calibrationTTL = 24 * time.Hour
+)
+
+type Options struct {
+	DetourFactor float64
+	SpeedKmh     float64
+}
+
+// Estimate is an offline travel-time guess between two points.
+type Estimate struct {
+	DistanceKm  float64
+	DurationMin float64
+}
+
+type calibration struct {
+	speedKmh float64
+	ok       bool
+	at       time.Time
+}
+
+// Estimator approximates bus travel times from straight-line distance, a
+// road-detour factor and an average bus speed. When timetable data is
+// available the speed is calibrated per line from the first trip of the day.
+type Estimator struct {
+	opts           Options
+	departureStore store.DepartureStore
+
+	mu           sync.Mutex
+	calibrations map[string]calibration
+}
+
+func New(departureStore store.DepartureStore, opts Options) *Estimator {
+	return &Estimator{
+		opts:           opts,
+		departureStore: departureStore,
+		calibrations:   make(map[string]calibration),
+	}
+}
+
+// Estimate returns the distance and duration between two points. lines are
+// the bus lines serving the trip; their calibrated speeds are averaged.
+func (e *Estimator) Estimate(ctx context.Context, fromLat, fromLon, toLat, toLon float64, lines []string) Estimate {
+	ctx, span := telemetry.StartSpan(ctx, "estimator.Estimate")
+	defer span.End()
+
+	distance := utils.HaversineKm(fromLat, fromLon, toLat, toLon) * e.opts.DetourFactor
+	speed := e.speedFor(ctx, lines)
+
+	span.SetAttributes(
+		attribute.Float64("estimator.distance_km", distance),
+		attribute.Float64("estimator.speed_kmh", speed),
+	)
+
+	return Estimate{
+		DistanceKm:  math.Round(distance*100) / 100,
+		DurationMin: math.Round(distance / speed * 60),
+	}
+}
+
+func (e *Estimator) speedFor(ctx context.Context, lines []string) float64 {
+	var sum float64
+	var n int
+	for _, line := range lines {
+		if speed, ok := e.lineSpeed(ctx, line); ok {
+			sum += speed
+			n++
+		}
+	}
+
+	if n == 0 {
+		return e.opts.SpeedKmh
+	}
+	return sum / float64(n)
+}
+
+func (e *Estimator) lineSpeed(ctx context.Context, line string) (float64, bool) {
+	e.mu.Lock()
+	c, found := e.calibrations[line]
+	e.mu.Unlock()
+
+	if found && time.Since(c.at) < calibrationTTL {
+		return c.speedKmh, c.ok
+	}
+
+	speed, ok, err := e.calibrate(ctx, line)
+	if err != nil {
+		// Do not remember failures; the next request retries.
+		slog.Warn("estimator calibration failed", slog.String("line", line), slog.Any("error", err))
+		return 0, false
+	}
+
+	e.mu.Lock()
+	e.calibrations[line] = calibration{speedKmh: speed, ok: ok, at: time.Now()}
+	e.mu.Unlock()
+
+	return speed, ok
+}
+
+// calibrate derives a line's average speed (including dwell time) from the
+// weekday timetable: stops of each direction are ordered by their first
+// departure and the distance covered is divided by the time taken.
+func (e *Estimator) calibrate(ctx context.Context, line string) (float64, bool, error) {
+	stopTimes, err := e.departureStore.FindLineStopTimes(ctx, line, store.ScheduleTypeWeekday)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var distanceKm, durationMin float64
+	for i := 1; i < len(stopTimes); i++ {
+		prev, cur := stopTimes[i-1], stopTimes[i]
+		if prev.DirectionID != cur.DirectionID {
+			continue
+		}
+
+		gap := float64(cur.FirstDeparture - prev.FirstDeparture)
+		if gap <= 0 || gap > maxStopGapMinutes {
+			continue
+		}
+
+		distanceKm += utils.HaversineKm(prev.Lat, prev.Lon, cur.Lat, cur.Lon) * e.opts.DetourFactor
+		durationMin += gap
+	}
+
+	if durationMin == 0 {
+		return 0, false, nil
+	}
+
+	speed := distanceKm / (durationMin / 60)
+	if speed < minSpeedKmh || speed > maxSpeedKmh {
+		return 0, false, nil
+	}
+
+	return speed, true, nil
+}
@@ -3,72 +3,167 @@ package openrouteservice
 import (
 	"bytes"
 	"context"
-	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/perkzen/mbus/apps/bus-service/internal/provider/routing"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
 	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
-	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-type API interface {
-	GetMatrix(locations [][]float64) (*MatrixResponse, error)
-}
+// DefaultProfile is the ORS routing profile used unless WithProfile is given.
+const DefaultProfile = "driving-car"
 
 type APIClient struct {
 	apiKey  string
 	baseURL string
+	profile string
 	client  *http.Client
-	cache   *redis.Client
+
+	mu      sync.RWMutex
+	quota   *Quota
+	lastErr error
 }
 
-func NewAPIClient(apiKey string, cache *redis.Client) *APIClient {
-	return &APIClient{
-		apiKey:  apiKey,
-		baseURL: "https://api.openrouteservice.org/v2",
-		client:  &http.Client{},
-		cache:   cache,
+var _ routing.Provider = (*APIClient)(nil)
+
+type Option func(*APIClient)
+
+// WithTimeout bounds every ORS HTTP request, including reading the body.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *APIClient) {
+		c.client.Timeout = timeout
 	}
 }
 
-func (c *APIClient) GetMatrix(locations [][]float64) (*MatrixResponse, error) {
-	ctx := context.Background()
+// WithProfile selects the ORS routing profile, e.g. "driving-hgv" which
+// respects the size and weight restrictions that apply to buses.
+func WithProfile(profile string) Option {
+	return func(c *APIClient) {
+		if profile != "" {
+			c.profile = profile
+		}
+	}
+}
 
-	locBytes, _ := json.Marshal(locations)
-	hash := sha256.Sum256(locBytes)
-	cacheKey := fmt.Sprintf("ors_matrix_%x", hash)
+// WithBaseURL points the client at a self-hosted ORS instance.
+func WithBaseURL(baseURL string) Option {
+	return func(c *APIClient) {
+		if baseURL != "" {
+			c.baseURL = strings.TrimSuffix(baseURL, "/")
+		}
+	}
+}
 
-	loader := func() (MatrixResponse, error) {
-		return c.fetchMatrix(locations)
+func NewAPIClient(apiKey string, opts ...Option) *APIClient {
+	c := &APIClient{
+		apiKey:  apiKey,
+		baseURL: "https://api.openrouteservice.org/v2",
+		profile: DefaultProfile,
+		client: &http.Client{
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+			Timeout:   10 * time.Second,
+		},
 	}
 
-	result, err := utils.WithCache(ctx, c.cache, cacheKey, 24*time.Hour, loader)
-	if err != nil {
-		return nil, err
+	for _, opt := range opts {
+		opt(c)
 	}
 
-	return &result, nil
+	return c
+}
+
+func (c *APIClient) Name() string {
+	return routing.ProviderORS + "/" + c.profile
 }
 
-func (c *APIClient) fetchMatrix(locations [][]float64) (MatrixResponse, error) {
+func (c *APIClient) Matrix(ctx context.Context, points []routing.Point) (_ *routing.Matrix, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "openrouteservice.Matrix",
+		attribute.Int("ors.locations", len(points)),
+		attribute.String("ors.profile", c.profile),
+	)
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	reqBody := MatrixRequest{
-		Locations:        locations,
+		Locations:        toLocations(points),
 		Metrics:          []string{"distance", "duration"},
 		ResolveLocations: true,
 		Units:            "km",
 	}
 
-	data, err := json.Marshal(reqBody)
+	var result MatrixResponse
+	if err := c.post(ctx, "/matrix/"+c.profile, reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	// ORS reports durations in seconds.
+	matrix := &routing.Matrix{
+		DistancesKm:  make([][]float64, len(result.Distances)),
+		DurationsMin: make([][]float64, len(result.Distances)),
+	}
+	for i := range result.Distances {
+		matrix.DistancesKm[i] = make([]float64, len(result.Distances[i]))
+		matrix.DurationsMin[i] = make([]float64, len(result.Distances[i]))
+		for j, distance := range result.Distances[i] {
+			duration := result.Durations[i][j]
+			if distance == nil || duration == nil {
+				matrix.SetUnreachable(i, j)
+				continue
+			}
+			matrix.DistancesKm[i][j] = *distance
+			matrix.DurationsMin[i][j] = *duration / 60
+		}
+	}
+
+	return matrix, nil
+}
+
+func (c *APIClient) Route(ctx context.Context, points []routing.Point) (_ *routing.Route, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "openrouteservice.Route",
+		attribute.Int("ors.locations", len(points)),
+		attribute.String("ors.profile", c.profile),
+	)
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	reqBody := DirectionsRequest{
+		Coordinates: toLocations(points),
+		Units:       "km",
+	}
+
+	var result DirectionsResponse
+	if err := c.post(ctx, "/directions/"+c.profile+"/geojson", reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	if len(result.Features) == 0 {
+		return nil, routing.ErrNoRoute
+	}
+
+	feature := result.Features[0]
+	return &routing.Route{
+		DistanceKm:  feature.Properties.Summary.Distance,
+		DurationMin: feature.Properties.Summary.Duration / 60,
+		Geometry:    feature.Geometry.Coordinates,
+	}, nil
+}
+
+func (c *APIClient) post(ctx context.Context, path string, body, out any) error {
+	data, err := json.Marshal(body)
 	if err != nil {
-		return MatrixResponse{}, fmt.Errorf("failed to marshal request: %w", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", c.baseURL+"/matrix/driving-car", bytes.NewBuffer(data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewBuffer(data))
 	if err != nil {
-		return MatrixResponse{}, fmt.Errorf("failed to build request: %w", err)
+		return fmt.Errorf("failed to build request: %w", err)
 	}
 
 	req.Header.Set("Authorization", c.apiKey)
@@ -77,27 +172,101 @@ func (c *APIClient) fetchMatrix(locations [][]float64) (MatrixResponse, error) {
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return MatrixResponse{}, fmt.Errorf("request error: %w", err)
+		err = fmt.Errorf("request error: %w", err)
+		c.recordResponse(nil, err)
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return MatrixResponse{}, fmt.Errorf("ORS API error: %s", resp.Status)
+		err = fmt.Errorf("ORS API error: %s", resp.Status)
+		c.recordResponse(resp, err)
+		return err
 	}
+	c.recordResponse(resp, nil)
 
-	var result MatrixResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return MatrixResponse{}, fmt.Errorf("failed to parse response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
 	}
+	return nil
+}
 
-	// Convert durations from seconds to minutes + 1min/km
-	for i := range result.Durations {
-		for j := range result.Durations[i] {
-			durationMin := result.Durations[i][j] / 60
-			extraMinutes := result.Distances[i][j]
-			result.Durations[i][j] = math.Round(durationMin + extraMinutes)
-		}
+func toLocations(points []routing.Point) [][]float64 {
+	locations := make([][]float64, len(points))
+	for i, p := range points {
+		locations[i] = []float64{p.Lon, p.Lat}
+	}
+	return locations
+}
+
+// HasAPIKey reports whether the client was configured with an API key.
+func (c *APIClient) HasAPIKey() bool {
+	return c.apiKey != ""
+}
+
+// Ping checks that an API key is set and the ORS API host is reachable. Any
+// HTTP response counts as reachable; only transport errors and 5xx responses
+// are failures.
+func (c *APIClient) Ping(ctx context.Context) error {
+	if !c.HasAPIKey() {
+		return errors.New("ORS_API_KEY is not configured")
 	}
 
-	return result, nil
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.baseURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("ORS API unavailable: %s", resp.Status)
+	}
+	return nil
+}
+
+// Quota returns the rate-limit state seen on the last request, or nil
+// if no request has been made since startup.
+func (c *APIClient) Quota() *Quota {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.quota == nil {
+		return nil
+	}
+	q := *c.quota
+	return &q
+}
+
+// LastError returns the error of the last request, if it failed.
+func (c *APIClient) LastError() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lastErr
+}
+
+func (c *APIClient) recordResponse(resp *http.Response, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lastErr = err
+	if resp == nil {
+		return
+	}
+
+	limit, errLimit := strconv.Atoi(resp.Header.Get("X-Ratelimit-Limit"))
+	remaining, errRemaining := strconv.Atoi(resp.Header.Get("X-Ratelimit-Remaining"))
+	if errLimit != nil || errRemaining != nil {
+		return
+	}
+
+	c.quota = &Quota{
+		Limit:     limit,
+		Remaining: remaining,
+		UpdatedAt: utils.Now(),
+	}
 }
@@ -1,5 +1,7 @@
 package openrouteservice
 
+import "time"
+
 type MatrixRequest struct {
 	Locations        [][]float64 `json:"locations"`
 	Metrics          []string    `json:"metrics"` // e.g. ["distance", "duration"]
@@ -8,6 +10,34 @@ type MatrixRequest struct {
 }
 
 type MatrixResponse struct {
-	Distances [][]float64 `json:"distances"`
-	Durations [][]float64 `json:"durations"`
+	// Unreachable pairs are null.
+	Distances [][]*float64 `json:"distances"`
+	Durations [][]*float64 `json:"durations"`
+}
+
+type DirectionsRequest struct {
+	Coordinates [][]float64 `json:"coordinates"`
+	Units       string      `json:"units"`
+}
+
+// DirectionsResponse is the GeoJSON flavour of the directions response.
+type DirectionsResponse struct {
+	Features []struct {
+		Geometry struct {
+			Coordinates [][2]float64 `json:"coordinates"`
+		} `json:"geometry"`
+		Properties struct {
+			Summary struct {
+				Distance float64 `json:"distance"`
+				Duration float64 `json:"duration"`
+			} `json:"summary"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+// Quota is the rate-limit state reported by the most recent ORS response.
+type Quota struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
@@ -0,0 +1,207 @@
+package osrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/provider/routing"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// DefaultProfile is the OSRM profile used unless WithProfile is given. It has
+// to match the profile the OSRM data was prepared with.
+const DefaultProfile = "driving"
+
+// APIClient talks to an OSRM HTTP server, typically self-hosted on an OSM
+// extract of the region.
+type APIClient struct {
+	baseURL string
+	profile string
+	client  *http.Client
+}
+
+var _ routing.Provider = (*APIClient)(nil)
+
+type Option func(*APIClient)
+
+// WithTimeout bounds every OSRM HTTP request, including reading the body.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *APIClient) {
+		c.client.Timeout = timeout
+	}
+}
+
+func WithProfile(profile string) Option {
+	return func(c *APIClient) {
+		if profile != "" {
+			c.profile = profile
+		}
+	}
+}
+
+func NewAPIClient(baseURL string, opts ...Option) *APIClient {
+	c := &APIClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		profile: DefaultProfile,
+		client: &http.Client{
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+			Timeout:   10 * time.Second,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *APIClient) Name() string {
+	return routing.ProviderOSRM + "/" + c.profile
+}
+
+func (c *APIClient) Matrix(ctx context.Context, points []routing.Point) (_ *routing.Matrix, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "osrm.Matrix",
+		attribute.Int("osrm.locations", len(points)),
+		attribute.String("osrm.profile", c.profile),
+	)
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	query := url.Values{"annotations": {"distance,duration"}}
+
+	var result TableResponse
+	if err := c.get(ctx, "table", points, query, &result); err != nil {
+		return nil, err
+	}
+	if err := responseError(result.Code, result.Message); err != nil {
+		return nil, err
+	}
+
+	matrix := &routing.Matrix{
+		DistancesKm:  make([][]float64, len(result.Distances)),
+		DurationsMin: make([][]float64, len(result.Distances)),
+	}
+	for i := range result.Distances {
+		matrix.DistancesKm[i] = make([]float64, len(result.Distances[i]))
+		matrix.DurationsMin[i] = make([]float64, len(result.Distances[i]))
+		for j, distance := range result.Distances[i] {
+			duration := result.Durations[i][j]
+			if distance == nil || duration == nil {
+				matrix.SetUnreachable(i, j)
+				continue
+			}
+			matrix.DistancesKm[i][j] = *distance / 1000
+			matrix.DurationsMin[i][j] = *duration / 60
+		}
+	}
+
+	return matrix, nil
+}
+
+func (c *APIClient) Route(ctx context.Context, points []routing.Point) (_ *routing.Route, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "osrm.Route",
+		attribute.Int("osrm.locations", len(points)),
+		attribute.String("osrm.profile", c.profile),
+	)
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	query := url.Values{
+		"overview":   {"full"},
+		"geometries": {"geojson"},
+	}
+
+	var result RouteResponse
+	if err := c.get(ctx, "route", points, query, &result); err != nil {
+		return nil, err
+	}
+	if err := responseError(result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	if len(result.Routes) == 0 {
+		return nil, routing.ErrNoRoute
+	}
+
+	route := result.Routes[0]
+	return &routing.Route{
+		DistanceKm:  route.Distance / 1000,
+		DurationMin: route.Duration / 60,
+		Geometry:    route.Geometry.Coordinates,
+	}, nil
+}
+
+// Ping checks that the OSRM server is reachable. OSRM has no status endpoint,
+// so any HTTP response below 500 counts as reachable.
+func (c *APIClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.baseURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("OSRM unavailable: %s", resp.Status)
+	}
+	return nil
+}
+
+// get calls an OSRM service. OSRM answers client errors with a 400 and a JSON
+// body carrying the error code, so such bodies are decoded as well.
+func (c *APIClient) get(ctx context.Context, service string, points []routing.Point, query url.Values, out any) error {
+	endpoint := fmt.Sprintf("%s/%s/v1/%s/%s?%s", c.baseURL, service, c.profile, coordinates(points), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 && resp.StatusCode != http.StatusBadRequest {
+		return fmt.Errorf("OSRM error: %s", resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+func responseError(code, message string) error {
+	switch code {
+	case codeOk:
+		return nil
+	case codeNoRoute:
+		return routing.ErrNoRoute
+	case "":
+		return errors.New("OSRM error: empty response code")
+	default:
+		return fmt.Errorf("OSRM error: %s: %s", code, message)
+	}
+}
+
+func coordinates(points []routing.Point) string {
+	parts := make([]string, len(points))
+	for i, p := range points {
+		parts[i] = strconv.FormatFloat(p.Lon, 'f', 6, 64) + "," + strconv.FormatFloat(p.Lat, 'f', 6, 64)
+	}
+	return strings.Join(parts, ";")
+}
@@ -0,0 +1,27 @@
+package osrm
+
+// Response codes, see http://project-osrm.org/docs/v5.24.0/api/#responses
+const (
+	codeOk      = "Ok"
+	codeNoRoute = "NoRoute"
+)
+
+type TableResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Unreachable pairs are null.
+	Distances [][]*float64 `json:"distances"` // meters
+	Durations [][]*float64 `json:"durations"` // seconds
+}
+
+type RouteResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Routes  []struct {
+		Distance float64 `json:"distance"` // meters
+		Duration float64 `json:"duration"` // seconds
+		Geometry struct {
+			Coordinates [][2]float64 `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"routes"`
+}
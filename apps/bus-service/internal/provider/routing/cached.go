@@ -0,0 +1,63 @@
+package routing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/cache"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+// CachedProvider caches matrix and route results of another provider.
+// Routing results depend only on coordinates, not on timetable data, so they
+// are not scoped to the data generation.
+type CachedProvider struct {
+	Provider
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+func NewCachedProvider(p Provider, c cache.Cache, ttl time.Duration) *CachedProvider {
+	return &CachedProvider{Provider: p, cache: c, ttl: ttl}
+}
+
+func (c *CachedProvider) Matrix(ctx context.Context, points []Point) (*Matrix, error) {
+	loader := func(ctx context.Context) (Matrix, error) {
+		m, err := c.Provider.Matrix(ctx, points)
+		if err != nil {
+			return Matrix{}, err
+		}
+		return *m, nil
+	}
+
+	result, err := utils.WithCache(ctx, c.cache, c.key("matrix", points), c.ttl, loader)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *CachedProvider) Route(ctx context.Context, points []Point) (*Route, error) {
+	loader := func(ctx context.Context) (Route, error) {
+		r, err := c.Provider.Route(ctx, points)
+		if err != nil {
+			return Route{}, err
+		}
+		return *r, nil
+	}
+
+	result, err := utils.WithCache(ctx, c.cache, c.key("route", points), c.ttl, loader)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *CachedProvider) key(kind string, points []Point) string {
+	data, _ := json.Marshal(points)
+	hash := sha256.Sum256(data)
+	return cache.Key("routing", c.Provider.Name(), kind, fmt.Sprintf("%x", hash))
+}
@@ -0,0 +1,80 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	ProviderORS      = "ors"
+	ProviderOSRM     = "osrm"
+	ProviderValhalla = "valhalla"
+)
+
+// ErrNoRoute is returned when the engine cannot connect the given points.
+var ErrNoRoute = errors.New("routing: no route found")
+
+// Unreachable reports that the engine found no route from point i to point
+// j of a matrix. Matrix.Cell returns it rather than a zero cell, so callers
+// fall back to an estimate.
+func Unreachable(i, j int) error {
+	return fmt.Errorf("%w from point %d to point %d", ErrNoRoute, i, j)
+}
+
+// Point is a WGS84 coordinate.
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// Matrix holds travel distances and durations between every pair of points,
+// indexed [from][to]. Durations are pure driving time without stops.
+// Unreachable marks the pairs the engine found no route for, whose distance
+// and duration are zero; read cells with Cell.
+type Matrix struct {
+	DistancesKm  [][]float64 `json:"distancesKm"`
+	DurationsMin [][]float64 `json:"durationsMin"`
+	Unreachable  [][]bool    `json:"unreachable,omitempty"`
+}
+
+// SetUnreachable marks the pair from point i to point j as unreachable.
+func (m *Matrix) SetUnreachable(i, j int) {
+	if m.Unreachable == nil {
+		m.Unreachable = make([][]bool, len(m.DistancesKm))
+	}
+	if m.Unreachable[i] == nil {
+		m.Unreachable[i] = make([]bool, len(m.DistancesKm[i]))
+	}
+	m.Unreachable[i][j] = true
+}
+
+// Cell returns the distance and duration from point i to point j, or an
+// ErrNoRoute error when the engine found no route between them.
+func (m *Matrix) Cell(i, j int) (distanceKm, durationMin float64, err error) {
+	if i < len(m.Unreachable) && j < len(m.Unreachable[i]) && m.Unreachable[i][j] {
+		return 0, 0, Unreachable(i, j)
+	}
+	return m.DistancesKm[i][j], m.DurationsMin[i][j], nil
+}
+
+// Route is the path through a list of points in order. Geometry is a list of
+// [lon, lat] pairs, as in GeoJSON.
+type Route struct {
+	DistanceKm  float64      `json:"distanceKm"`
+	DurationMin float64      `json:"durationMin"`
+	Geometry    [][2]float64 `json:"geometry"`
+}
+
+// Provider is a road routing engine.
+type Provider interface {
+	// Name identifies the engine and profile, e.g. "osrm/driving". It is part
+	// of cache keys, so results of different engines never mix.
+	Name() string
+	// Matrix marks unreachable pairs of points instead of failing, so a
+	// caller only fails on the cells it reads.
+	Matrix(ctx context.Context, points []Point) (*Matrix, error)
+	Route(ctx context.Context, points []Point) (*Route, error)
+	// Ping checks that the engine is reachable and configured.
+	Ping(ctx context.Context) error
+}
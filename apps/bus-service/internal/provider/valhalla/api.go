@@ -0,0 +1,209 @@
+package valhalla
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/provider/routing"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// DefaultCosting is the Valhalla costing model used unless WithCosting is
+// given. The bus model prefers roads open to buses and avoids turns they
+// cannot make.
+const DefaultCosting = "bus"
+
+// APIClient talks to a Valhalla HTTP server, typically self-hosted on an OSM
+// extract of the region.
+type APIClient struct {
+	baseURL string
+	costing string
+	client  *http.Client
+}
+
+var _ routing.Provider = (*APIClient)(nil)
+
+type Option func(*APIClient)
+
+// WithTimeout bounds every Valhalla HTTP request, including reading the body.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *APIClient) {
+		c.client.Timeout = timeout
+	}
+}
+
+func WithCosting(costing string) Option {
+	return func(c *APIClient) {
+		if costing != "" {
+			c.costing = costing
+		}
+	}
+}
+
+func NewAPIClient(baseURL string, opts ...Option) *APIClient {
+	c := &APIClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		costing: DefaultCosting,
+		client: &http.Client{
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+			Timeout:   10 * time.Second,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *APIClient) Name() string {
+	return routing.ProviderValhalla + "/" + c.costing
+}
+
+func (c *APIClient) Matrix(ctx context.Context, points []routing.Point) (_ *routing.Matrix, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "valhalla.Matrix",
+		attribute.Int("valhalla.locations", len(points)),
+		attribute.String("valhalla.costing", c.costing),
+	)
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	locations := toLocations(points)
+	reqBody := MatrixRequest{
+		Sources: locations,
+		Targets: locations,
+		Costing: c.costing,
+		Units:   "kilometers",
+	}
+
+	var result MatrixResponse
+	if err := c.post(ctx, "/sources_to_targets", reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	matrix := &routing.Matrix{
+		DistancesKm:  make([][]float64, len(result.SourcesToTargets)),
+		DurationsMin: make([][]float64, len(result.SourcesToTargets)),
+	}
+	for i, row := range result.SourcesToTargets {
+		matrix.DistancesKm[i] = make([]float64, len(row))
+		matrix.DurationsMin[i] = make([]float64, len(row))
+		for j, cell := range row {
+			if cell.Distance == nil || cell.Time == nil {
+				matrix.SetUnreachable(i, j)
+				continue
+			}
+			matrix.DistancesKm[i][j] = *cell.Distance
+			matrix.DurationsMin[i][j] = *cell.Time / 60
+		}
+	}
+
+	return matrix, nil
+}
+
+func (c *APIClient) Route(ctx context.Context, points []routing.Point) (_ *routing.Route, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "valhalla.Route",
+		attribute.Int("valhalla.locations", len(points)),
+		attribute.String("valhalla.costing", c.costing),
+	)
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	reqBody := RouteRequest{
+		Locations: toLocations(points),
+		Costing:   c.costing,
+		Units:     "kilometers",
+	}
+
+	var result RouteResponse
+	if err := c.post(ctx, "/route", reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	route := &routing.Route{
+		DistanceKm:  result.Trip.Summary.Length,
+		DurationMin: result.Trip.Summary.Time / 60,
+	}
+	for _, leg := range result.Trip.Legs {
+		shape, err := decodePolyline(leg.Shape, 1e6)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode route shape: %w", err)
+		}
+		// Consecutive legs share their connecting point.
+		if len(route.Geometry) > 0 && len(shape) > 0 {
+			shape = shape[1:]
+		}
+		route.Geometry = append(route.Geometry, shape...)
+	}
+
+	return route, nil
+}
+
+// Ping checks that the Valhalla server is up via its status endpoint.
+func (c *APIClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/status", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Valhalla unavailable: %s", resp.Status)
+	}
+	return nil
+}
+
+func (c *APIClient) post(ctx context.Context, path string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var apiErr ErrorResponse
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.ErrorCode != 0 {
+			if apiErr.ErrorCode == errNoPath {
+				return routing.ErrNoRoute
+			}
+			return fmt.Errorf("Valhalla error %d: %s", apiErr.ErrorCode, apiErr.Error)
+		}
+		return fmt.Errorf("Valhalla error: %s", resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+func toLocations(points []routing.Point) []Location {
+	locations := make([]Location, len(points))
+	for i, p := range points {
+		locations[i] = Location{Lat: p.Lat, Lon: p.Lon}
+	}
+	return locations
+}
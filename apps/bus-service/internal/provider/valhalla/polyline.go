@@ -0,0 +1,42 @@
+package valhalla
+
+import "errors"
+
+var errInvalidPolyline = errors.New("invalid encoded polyline")
+
+// decodePolyline decodes a Google encoded polyline into [lon, lat] pairs.
+// Valhalla encodes shapes with a precision of 1e6 instead of the usual 1e5.
+func decodePolyline(encoded string, precision float64) ([][2]float64, error) {
+	var coords [][2]float64
+	var lat, lon int
+
+	for i := 0; i < len(encoded); {
+		var deltas [2]int
+		for k := range deltas {
+			var result, shift int
+			for {
+				if i >= len(encoded) {
+					return nil, errInvalidPolyline
+				}
+				b := int(encoded[i]) - 63
+				i++
+				result |= (b & 0x1f) << shift
+				shift += 5
+				if b < 0x20 {
+					break
+				}
+			}
+			if result&1 != 0 {
+				deltas[k] = ^(result >> 1)
+			} else {
+				deltas[k] = result >> 1
+			}
+		}
+
+		lat += deltas[0]
+		lon += deltas[1]
+		coords = append(coords, [2]float64{float64(lon) / precision, float64(lat) / precision})
+	}
+
+	return coords, nil
+}
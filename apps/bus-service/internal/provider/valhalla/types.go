@@ -0,0 +1,46 @@
+package valhalla
+
+// errNoPath is the Valhalla error code for "No path could be found for input".
+const errNoPath = 442
+
+type Location struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+type MatrixRequest struct {
+	Sources []Location `json:"sources"`
+	Targets []Location `json:"targets"`
+	Costing string     `json:"costing"`
+	Units   string     `json:"units"`
+}
+
+type MatrixResponse struct {
+	SourcesToTargets [][]struct {
+		Distance *float64 `json:"distance"` // kilometers, null if unreachable
+		Time     *float64 `json:"time"`     // seconds, null if unreachable
+	} `json:"sources_to_targets"`
+}
+
+type RouteRequest struct {
+	Locations []Location `json:"locations"`
+	Costing   string     `json:"costing"`
+	Units     string     `json:"units"`
+}
+
+type RouteResponse struct {
+	Trip struct {
+		Summary struct {
+			Length float64 `json:"length"` // kilometers
+			Time   float64 `json:"time"`   // seconds
+		} `json:"summary"`
+		Legs []struct {
+			Shape string `json:"shape"` // encoded polyline, precision 6
+		} `json:"legs"`
+	} `json:"trip"`
+}
+
+type ErrorResponse struct {
+	ErrorCode int    `json:"error_code"`
+	Error     string `json:"error"`
+}
@@ -6,30 +6,144 @@ import (
 	"github.com/perkzen/mbus/apps/bus-service/internal/app"
 	"github.com/perkzen/mbus/apps/bus-service/internal/middleware"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"net/http"
+	"time"
 )
 
 func RegisterRoutes(app *app.Application) *chi.Mux {
 	r := chi.NewRouter()
 
-	middleware.Init(r)
+	middleware.Init(r, app.Env.RequestTimeout)
 
-	r.Mount("/swagger", httpSwagger.WrapHandler)
+	r.NotFound(api.NotFound)
+	r.MethodNotAllowed(api.MethodNotAllowed)
 
-	r.Get("/health", api.MakeHandlerFunc(app.HealthCheck))
+	r.Route("/swagger", func(r chi.Router) {
+		latest := http.RedirectHandler("/swagger/v2/index.html", http.StatusFound).ServeHTTP
+		r.Get("/", latest)
+		r.Get("/index.html", latest)
+		r.Get("/v1/*", httpSwagger.Handler(httpSwagger.InstanceName("v1"), httpSwagger.URL("/swagger/v1/doc.json")))
+		r.Get("/v2/*", httpSwagger.Handler(httpSwagger.InstanceName("v2"), httpSwagger.URL("/swagger/v2/doc.json")))
+	})
+
+	versions := app.CacheNamespace
+	// Reference data only changes with the data generation; the ETag lets
+	// clients revalidate cheaply after the max-age has passed.
+	static := middleware.CachePolicy{MaxAge: 5 * time.Minute, SharedMaxAge: time.Hour, StaleWhileRevalidate: time.Minute}
+	timetable := middleware.CachePolicy{MaxAge: time.Minute, SharedMaxAge: 5 * time.Minute, KeyParts: api.TimetableKeyParts}
+
+	r.Route("/health", func(r chi.Router) {
+		r.Use(middleware.NoStore)
+
+		r.Get("/", api.MakeHandlerFunc(app.HealthHandler.Live))
+		r.Get("/live", api.MakeHandlerFunc(app.HealthHandler.Live))
+		r.Get("/ready", api.MakeHandlerFunc(app.HealthHandler.Ready))
+	})
 
 	r.Route("/api", func(r chi.Router) {
-		r.Route("/bus-stations", func(r chi.Router) {
-			r.Get("/", api.MakeHandlerFunc(app.BusStationHandler.GetBusStations))
-			r.Get("/{id}", api.MakeHandlerFunc(app.BusStationHandler.GetBusStationByID))
+		// v1 keeps its unversioned paths until the sunset date.
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Deprecated(app.Env.APIV1DeprecatedAt, app.Env.APIV1Sunset, "/api/v2"))
+
+			r.Route("/bus-stations", func(r chi.Router) {
+				r.Use(middleware.Conditional(versions, static))
+				r.Get("/", api.MakeHandlerFunc(app.BusStationHandler.GetBusStations))
+				r.Get("/{id}", api.MakeHandlerFunc(app.BusStationHandler.GetBusStationByID))
+				r.Get("/{id}/stops", api.MakeHandlerFunc(app.BusStationHandler.GetBusStationStops))
+			})
+
+			r.Route("/bus-lines", func(r chi.Router) {
+				r.Use(middleware.Conditional(versions, static))
+				r.Get("/", api.MakeHandlerFunc(app.BusLineHandler.GetBusLines))
+			})
+
+			r.Route("/departures", func(r chi.Router) {
+				r.Use(middleware.Conditional(versions, timetable))
+				r.Get("/", api.MakeHandlerFunc(app.DepartureHandler.GetDepartures))
+			})
+
+			r.Route("/geojson", func(r chi.Router) {
+				r.Use(middleware.Conditional(versions, static))
+				r.Get("/bus-stations", api.MakeHandlerFunc(app.GeoHandler.GetStations))
+				r.Get("/bus-lines", api.MakeHandlerFunc(app.GeoHandler.GetLines))
+			})
+		})
+
+		r.Route("/v2", func(r chi.Router) {
+			r.Route("/bus-stations", func(r chi.Router) {
+				r.Use(middleware.Conditional(versions, static))
+				r.Get("/", api.MakeHandlerFunc(app.V2.BusStationHandler.GetBusStations))
+				r.Get("/{id}", api.MakeHandlerFunc(app.V2.BusStationHandler.GetBusStationByID))
+				r.Get("/{id}/stops", api.MakeHandlerFunc(app.V2.BusStationHandler.GetBusStationStops))
+			})
+
+			r.Route("/bus-lines", func(r chi.Router) {
+				r.Use(middleware.Conditional(versions, static))
+				r.Get("/", api.MakeHandlerFunc(app.V2.BusLineHandler.GetBusLines))
+			})
 
+			r.Route("/departures", func(r chi.Router) {
+				r.Use(middleware.Conditional(versions, timetable))
+				r.Get("/", api.MakeHandlerFunc(app.V2.DepartureHandler.GetDepartures))
+			})
+
+			r.Route("/subscriptions", func(r chi.Router) {
+				r.Use(middleware.NoStore)
+				r.Post("/", api.MakeHandlerFunc(app.V2.SubscriptionHandler.CreateSubscription))
+				r.Get("/{id}", api.MakeHandlerFunc(app.V2.SubscriptionHandler.GetSubscription))
+				r.Delete("/{id}", api.MakeHandlerFunc(app.V2.SubscriptionHandler.DeleteSubscription))
+				r.Post("/{id}/confirm", api.MakeHandlerFunc(app.V2.SubscriptionHandler.ConfirmSubscription))
+			})
+
+			// Alerts depend on the clock, not only on the data generation.
+			r.Route("/alerts", func(r chi.Router) {
+				r.Use(middleware.NoStore)
+				r.Get("/", api.MakeHandlerFunc(app.V2.AlertHandler.GetAlerts))
+			})
+
+			// GeoJSON is a standard format and is served unchanged.
+			r.Route("/geojson", func(r chi.Router) {
+				r.Use(middleware.Conditional(versions, static))
+				r.Get("/bus-stations", api.MakeHandlerFunc(app.GeoHandler.GetStations))
+				r.Get("/bus-lines", api.MakeHandlerFunc(app.GeoHandler.GetLines))
+			})
 		})
 
-		r.Route("/bus-lines", func(r chi.Router) {
-			r.Get("/", api.MakeHandlerFunc(app.BusLineHandler.GetBusLines))
+		// Images are addressed by content hash and set their own cache headers.
+		r.Route("/images", func(r chi.Router) {
+			r.Get("/stations/{id}", api.MakeHandlerFunc(app.ImageHandler.GetStationImage))
+			r.Get("/{hash}", api.MakeHandlerFunc(app.ImageHandler.GetImage))
 		})
 
-		r.Route("/departures", func(r chi.Router) {
-			r.Get("/", api.MakeHandlerFunc(app.DepartureHandler.GetDepartures))
+		r.Route("/graphql", func(r chi.Router) {
+			r.Use(middleware.NoStore)
+			r.Post("/", api.MakeHandlerFunc(app.GraphQLHandler.Query))
+		})
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(middleware.NoStore)
+			r.Use(middleware.RequireAdminToken(app.Env.AdminToken))
+
+			r.Get("/cache/keys", api.MakeHandlerFunc(app.AdminHandler.GetCacheKeys))
+			r.Delete("/cache/keys", api.MakeHandlerFunc(app.AdminHandler.PurgeCacheKeys))
+			r.Post("/cache/invalidate", api.MakeHandlerFunc(app.AdminHandler.InvalidateCache))
+
+			r.Get("/data-version", api.MakeHandlerFunc(app.AdminHandler.GetDataVersion))
+			r.Post("/data-version", api.MakeHandlerFunc(app.AdminHandler.BumpDataVersion))
+
+			r.Put("/translations", api.MakeHandlerFunc(app.AdminHandler.PutTranslation))
+			r.Delete("/translations", api.MakeHandlerFunc(app.AdminHandler.DeleteTranslation))
+
+			r.Put("/bus-stations/{id}/attributes", api.MakeHandlerFunc(app.AdminHandler.PutStationAttributes))
+			r.Post("/bus-stations/attributes/import", api.MakeHandlerFunc(app.AdminHandler.ImportStationAttributes))
+
+			r.Put("/bus-lines/{id}/metadata", api.MakeHandlerFunc(app.AdminHandler.PutLineMetadata))
+
+			r.Post("/alerts", api.MakeHandlerFunc(app.AdminHandler.CreateAlert))
+			r.Delete("/alerts/{id}", api.MakeHandlerFunc(app.AdminHandler.DeleteAlert))
+
+			r.Get("/images/sync", api.MakeHandlerFunc(app.AdminHandler.GetImageSync))
+			r.Post("/images/sync", api.MakeHandlerFunc(app.AdminHandler.SyncImages))
 		})
 	})
 
@@ -0,0 +1,302 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	busv1 "github.com/perkzen/mbus/apps/bus-service/internal/pb/bus/v1"
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/departure"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Departure board limits.
+const (
+	defaultBoardSize    = 10
+	maxBoardSize        = 50
+	defaultBoardRefresh = 30 * time.Second
+	minBoardRefresh     = 10 * time.Second
+)
+
+type BusService struct {
+	busv1.UnimplementedBusServiceServer
+
+	busStationStore  store.BusStationStore
+	busLineStore     store.BusLineStore
+	departureStore   store.DepartureStore
+	departureService *departure.Service
+	logger           *slog.Logger
+}
+
+func NewBusService(
+	busStationStore store.BusStationStore,
+	busLineStore store.BusLineStore,
+	departureStore store.DepartureStore,
+	departureService *departure.Service,
+	logger *slog.Logger,
+) *BusService {
+	return &BusService{
+		busStationStore:  busStationStore,
+		busLineStore:     busLineStore,
+		departureStore:   departureStore,
+		departureService: departureService,
+		logger:           logger.With(slog.String("service", "rpc.BusService")),
+	}
+}
+
+func (s *BusService) GetStation(ctx context.Context, req *busv1.GetStationRequest) (*busv1.Station, error) {
+	var stationID int
+	switch lookup := req.GetLookup().(type) {
+	case *busv1.GetStationRequest_Id:
+		stationID = int(lookup.Id)
+	case *busv1.GetStationRequest_Code:
+		code, err := s.busStationStore.FindBusStationIDByCode(ctx, strconv.Itoa(int(lookup.Code)))
+		if err != nil {
+			return nil, err
+		}
+		if code == nil {
+			return nil, errs.NotFoundError("Stop code %d does not exist", lookup.Code)
+		}
+		stationID = code.StationID
+	default:
+		f := &fields{}
+		f.violation("lookup", errs.FieldRequired, "Either 'id' or 'code' is required")
+		return nil, f.err()
+	}
+
+	station, err := s.busStationStore.FindBusStationByID(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+	if station == nil {
+		return nil, errs.BusStationNotFoundError(stationID)
+	}
+	return newStation(*station), nil
+}
+
+func (s *BusService) ListStations(ctx context.Context, req *busv1.ListStationsRequest) (*busv1.ListStationsResponse, error) {
+	f := &fields{}
+	page := f.page("page_size", req.GetPageSize(), "page_token", req.GetPageToken(), 10)
+	attributes := f.attributes("has", req.GetHas())
+	if err := f.err(); err != nil {
+		return nil, err
+	}
+
+	stations, err := s.busStationStore.ListBusStations(ctx, &store.BusStationFilterOptions{
+		Name:       req.GetName(),
+		Line:       req.GetLine(),
+		Attributes: attributes,
+	}, page)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &busv1.ListStationsResponse{
+		Stations:      make([]*busv1.Station, len(stations.Items)),
+		NextPageToken: stations.NextCursor,
+	}
+	for i, station := range stations.Items {
+		resp.Stations[i] = newStation(station)
+	}
+	return resp, nil
+}
+
+func (s *BusService) ListStationStops(ctx context.Context, req *busv1.ListStationStopsRequest) (*busv1.ListStationStopsResponse, error) {
+	f := &fields{}
+	stationID := f.required("station_id", req.GetStationId())
+	if err := f.err(); err != nil {
+		return nil, err
+	}
+
+	station, err := s.busStationStore.FindBusStationByID(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+	if station == nil {
+		return nil, errs.BusStationNotFoundError(stationID)
+	}
+
+	stops, err := s.busStationStore.FindStopsByStationID(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &busv1.ListStationStopsResponse{Stops: make([]*busv1.Stop, len(stops))}
+	for i, stop := range stops {
+		resp.Stops[i] = newStop(stop)
+	}
+	return resp, nil
+}
+
+func (s *BusService) ListLines(ctx context.Context, req *busv1.ListLinesRequest) (*busv1.ListLinesResponse, error) {
+	f := &fields{}
+	page := f.page("page_size", req.GetPageSize(), "page_token", req.GetPageToken(), 20)
+	if err := f.err(); err != nil {
+		return nil, err
+	}
+
+	lines, err := s.busLineStore.ListBusLines(ctx, &store.BusLineFilterOptions{
+		Name:      req.GetName(),
+		StationID: int(req.GetStationId()),
+		Lang:      i18n.FromContext(ctx),
+	}, page)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &busv1.ListLinesResponse{
+		Lines:         make([]*busv1.Line, len(lines.Items)),
+		NextPageToken: lines.NextCursor,
+	}
+	for i, line := range lines.Items {
+		resp.Lines[i] = newLine(line)
+	}
+	return resp, nil
+}
+
+func (s *BusService) GetTimetable(ctx context.Context, req *busv1.GetTimetableRequest) (*busv1.GetTimetableResponse, error) {
+	f := &fields{}
+	fromID := f.required("from_station_id", req.GetFromStationId())
+	toID := f.required("to_station_id", req.GetToStationId())
+	date := f.date("date", req.GetDate())
+	filter := departure.Filter{
+		Lines:     req.GetLines(),
+		Direction: req.GetDirection(),
+		After:     f.serviceTime("after", req.GetAfter()),
+		Before:    f.serviceTime("before", req.GetBefore()),
+		Limit:     f.size("limit", req.GetLimit(), 0, maxPageSize),
+		StepFree:  req.GetStepFree(),
+	}
+	if err := f.err(); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.departureService.GenerateTimetable(ctx, fromID, toID, date, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &busv1.GetTimetableResponse{
+		Date:     date,
+		Schedule: scheduleTypes[store.ScheduleTyp(date)],
+		Rows:     make([]*busv1.TimetableRow, len(rows)),
+	}
+	for i, row := range rows {
+		if resp.Rows[i], err = newTimetableRow(date, row); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func (s *BusService) WatchDepartureBoard(req *busv1.WatchDepartureBoardRequest, stream grpc.ServerStreamingServer[busv1.DepartureBoard]) error {
+	ctx := stream.Context()
+
+	f := &fields{}
+	stationID := f.required("station_id", req.GetStationId())
+	size := f.size("limit", req.GetLimit(), defaultBoardSize, maxBoardSize)
+	refresh := f.duration("refresh", req.GetRefresh(), defaultBoardRefresh, minBoardRefresh)
+	if err := f.err(); err != nil {
+		return err
+	}
+
+	station, err := s.busStationStore.FindBusStationByID(ctx, stationID)
+	if err != nil {
+		return err
+	}
+	if station == nil {
+		return errs.BusStationNotFoundError(stationID)
+	}
+	codes, err := s.busStationStore.FindStationCodesByStationIDs(ctx, []int{station.ID})
+	if err != nil {
+		return err
+	}
+	stops := make(map[int]store.StationCode, len(codes))
+	for _, c := range codes {
+		stops[c.Code] = c
+	}
+
+	ticker := time.NewTicker(refresh)
+	defer ticker.Stop()
+
+	var sent []*busv1.BoardDeparture
+	for {
+		departures, err := s.nextDepartures(ctx, station, stops, req.GetLines(), size)
+		if err != nil {
+			return err
+		}
+
+		if sent == nil || !slices.EqualFunc(sent, departures, func(a, b *busv1.BoardDeparture) bool { return proto.Equal(a, b) }) {
+			err := stream.Send(&busv1.DepartureBoard{
+				Station:     &busv1.StationRef{Id: int32(station.ID), Name: station.Name},
+				GeneratedAt: timestamppb.New(utils.Now()),
+				Departures:  departures,
+			})
+			if err != nil {
+				return err
+			}
+			sent = departures
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// nextDepartures returns the next size departures of the current service day
+// from any code of station. stops maps the codes to their stop.
+func (s *BusService) nextDepartures(ctx context.Context, station *store.BusStation, stops map[int]store.StationCode, lines []string, size int) ([]*busv1.BoardDeparture, error) {
+	date := utils.ServiceDate()
+	now := utils.NowServiceTime()
+
+	byCode, err := s.departureStore.FindDeparturesByStationCodes(ctx, station.Codes, store.ScheduleTyp(date))
+	if err != nil {
+		return nil, err
+	}
+
+	type codeDeparture struct {
+		code int
+		dep  store.Departure
+	}
+	var upcoming []codeDeparture
+	for code, deps := range byCode {
+		for _, d := range deps {
+			if d.DepartureTime < now {
+				continue
+			}
+			if len(lines) > 0 && !slices.ContainsFunc(lines, func(l string) bool { return strings.EqualFold(l, d.Line.Name) }) {
+				continue
+			}
+			upcoming = append(upcoming, codeDeparture{code: code, dep: d})
+		}
+	}
+	slices.SortFunc(upcoming, func(a, b codeDeparture) int {
+		if a.dep.DepartureTime != b.dep.DepartureTime {
+			return int(a.dep.DepartureTime - b.dep.DepartureTime)
+		}
+		return a.dep.ID - b.dep.ID
+	})
+	if len(upcoming) > size {
+		upcoming = upcoming[:size]
+	}
+
+	departures := make([]*busv1.BoardDeparture, len(upcoming))
+	for i, u := range upcoming {
+		if departures[i], err = newBoardDeparture(date, stops[u.code], u.dep); err != nil {
+			return nil, err
+		}
+	}
+	return departures, nil
+}
@@ -0,0 +1,152 @@
+package rpc
+
+import (
+	"fmt"
+	"math"
+
+	busv1 "github.com/perkzen/mbus/apps/bus-service/internal/pb/bus/v1"
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/departure"
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/images"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var scheduleTypes = map[store.ScheduleType]busv1.ScheduleType{
+	store.ScheduleTypeWeekday:  busv1.ScheduleType_SCHEDULE_TYPE_WEEKDAY,
+	store.ScheduleTypeSaturday: busv1.ScheduleType_SCHEDULE_TYPE_SATURDAY,
+	store.ScheduleTypeSunday:   busv1.ScheduleType_SCHEDULE_TYPE_SUNDAY,
+}
+
+var lineCategories = map[string]busv1.LineCategory{
+	store.LineCategoryCity:     busv1.LineCategory_LINE_CATEGORY_CITY,
+	store.LineCategorySuburban: busv1.LineCategory_LINE_CATEGORY_SUBURBAN,
+	store.LineCategoryOther:    busv1.LineCategory_LINE_CATEGORY_OTHER,
+}
+
+// stationAttributes maps StationAttribute values to attribute names.
+var stationAttributes = map[busv1.StationAttribute]string{
+	busv1.StationAttribute_STATION_ATTRIBUTE_WHEELCHAIR_ACCESSIBLE: store.AttrWheelchairAccessible,
+	busv1.StationAttribute_STATION_ATTRIBUTE_SHELTER:               store.AttrShelter,
+	busv1.StationAttribute_STATION_ATTRIBUTE_BENCH:                 store.AttrBench,
+	busv1.StationAttribute_STATION_ATTRIBUTE_REALTIME_DISPLAY:      store.AttrRealtimeDisplay,
+	busv1.StationAttribute_STATION_ATTRIBUTE_TICKET_MACHINE:        store.AttrTicketMachine,
+	busv1.StationAttribute_STATION_ATTRIBUTE_TACTILE_PAVING:        store.AttrTactilePaving,
+}
+
+func newStation(s store.BusStation) *busv1.Station {
+	codes := make([]int32, len(s.Codes))
+	for i, c := range s.Codes {
+		codes[i] = int32(c)
+	}
+	lines := make([]*busv1.Line, len(s.LineDetails))
+	for i, l := range s.LineDetails {
+		lines[i] = newLineRef(l)
+	}
+	return &busv1.Station{
+		Id:           int32(s.ID),
+		Name:         s.Name,
+		ImageUrl:     s.ImageURL,
+		ThumbnailUrl: s.ImageSizeURL(images.SizeThumb),
+		Lat:          s.Lat,
+		Lon:          s.Lon,
+		Codes:        codes,
+		Lines:        s.Lines,
+		LineDetails:  lines,
+		Attributes: &busv1.StationAttributes{
+			WheelchairAccessible: s.Attributes.WheelchairAccessible,
+			Shelter:              s.Attributes.Shelter,
+			Bench:                s.Attributes.Bench,
+			RealtimeDisplay:      s.Attributes.RealtimeDisplay,
+			TicketMachine:        s.Attributes.TicketMachine,
+			TactilePaving:        s.Attributes.TactilePaving,
+		},
+	}
+}
+
+func newLine(l store.BusLine) *busv1.Line {
+	line := newLineRef(store.LineRef{ID: l.ID, Name: l.Name, LineMetadata: l.LineMetadata})
+	line.Description = l.Description
+	return line
+}
+
+func newLineRef(l store.LineRef) *busv1.Line {
+	return &busv1.Line{
+		Id:        int32(l.ID),
+		Name:      l.Name,
+		Category:  lineCategories[l.Category],
+		Color:     l.Color,
+		TextColor: l.TextColor,
+		LongName:  l.LongName,
+		Operator:  l.Operator,
+		SortOrder: int32(l.SortOrder),
+	}
+}
+
+func newTimetableRow(date string, row departure.TimetableRow) (*busv1.TimetableRow, error) {
+	departureTime, err := utils.ServiceDateTime(date, row.GetDepartureAt())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve departure time of %d: %w", row.ID, err)
+	}
+	arrivalTime, err := utils.ServiceDateTime(date, row.GetArriveAt())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve arrival time of %d: %w", row.ID, err)
+	}
+
+	var line *busv1.Line
+	if row.LineDetails != nil {
+		line = newLineRef(*row.LineDetails)
+	}
+
+	return &busv1.TimetableRow{
+		Id:             int32(row.ID),
+		Line:           row.Line,
+		Direction:      row.Direction,
+		From:           newStationRef(row.FromStation),
+		To:             newStationRef(row.ToStation),
+		DepartureTime:  timestamppb.New(departureTime),
+		ArrivalTime:    timestamppb.New(arrivalTime),
+		Duration:       durationpb.New(arrivalTime.Sub(departureTime)),
+		DistanceMeters: int32(math.Round(row.Distance * 1000)),
+		Estimated:      row.Estimated,
+		StepFree:       row.StepFree,
+		LineDetails:    line,
+	}, nil
+}
+
+func newStationRef(s departure.Station) *busv1.StationRef {
+	ref := &busv1.StationRef{Id: int32(s.ID), Name: s.Name}
+	if s.Stop != nil {
+		ref.Stop = newStop(store.Stop{StationCode: *s.Stop})
+	}
+	return ref
+}
+
+func newStop(s store.Stop) *busv1.Stop {
+	return &busv1.Stop{
+		Id:         int32(s.ID),
+		Code:       int32(s.Code),
+		Platform:   s.Platform,
+		Lat:        s.Lat,
+		Lon:        s.Lon,
+		Lines:      s.Lines,
+		Directions: s.Directions,
+	}
+}
+
+func newBoardDeparture(date string, stop store.StationCode, d store.Departure) (*busv1.BoardDeparture, error) {
+	departureTime, err := utils.ServiceDateTime(date, d.DepartureTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve departure time of %d: %w", d.ID, err)
+	}
+
+	return &busv1.BoardDeparture{
+		Id:            int32(d.ID),
+		Line:          d.Line.Name,
+		Direction:     d.Direction,
+		Code:          int32(stop.Code),
+		Platform:      stop.Platform,
+		DepartureTime: timestamppb.New(departureTime),
+	}, nil
+}
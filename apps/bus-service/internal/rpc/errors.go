@@ -0,0 +1,70 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the ErrorInfo domain of problem codes.
+const errorDomain = "mbus"
+
+// codeByStatus maps the HTTP status of an errs.APIError to a gRPC code.
+var codeByStatus = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusMethodNotAllowed:    codes.Unimplemented,
+	http.StatusGatewayTimeout:      codes.DeadlineExceeded,
+	http.StatusInternalServerError: codes.Internal,
+}
+
+// toStatus converts an error returned by a handler to a status in the
+// language negotiated for ctx, like api.MakeHandlerFunc does for problem
+// documents. Unexpected errors are logged and masked.
+func toStatus(ctx context.Context, logger *slog.Logger, method string, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var apiErr errs.APIError
+	switch {
+	case errors.As(err, &apiErr):
+	case errors.Is(err, context.Canceled) && ctx.Err() != nil:
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		apiErr = errs.GatewayTimeoutError()
+	default:
+		logger.Error(err.Error(), slog.String("method", method))
+		apiErr = errs.InternalServerError()
+	}
+
+	apiErr = apiErr.Localize(i18n.FromContext(ctx))
+	code, ok := codeByStatus[apiErr.StatusCode]
+	if !ok {
+		code = codes.Unknown
+	}
+
+	st := status.New(code, apiErr.Message)
+	if withInfo, err := st.WithDetails(&errdetails.ErrorInfo{Reason: apiErr.Code, Domain: errorDomain}); err == nil {
+		st = withInfo
+	}
+
+	if len(apiErr.Errors) > 0 {
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(apiErr.Errors))
+		for i, v := range apiErr.Errors {
+			violations[i] = &errdetails.BadRequest_FieldViolation{Field: v.Field, Description: v.Message}
+		}
+		if withViolations, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+			st = withViolations
+		}
+	}
+	return st.Err()
+}
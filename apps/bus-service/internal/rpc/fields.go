@@ -0,0 +1,136 @@
+package rpc
+
+import (
+	"strings"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"github.com/perkzen/mbus/apps/bus-service/internal/pagination"
+	busv1 "github.com/perkzen/mbus/apps/bus-service/internal/pb/bus/v1"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// maxPageSize bounds the page_size of list calls.
+const maxPageSize = 100
+
+// fields collects the violations of a request message, like api.Binder does
+// for query parameters.
+type fields struct {
+	violations []errs.FieldError
+}
+
+func (f *fields) violation(field, code, format string, args ...any) {
+	f.violations = append(f.violations, errs.NewFieldError(errs.InBody, field, code, format, args...))
+}
+
+func (f *fields) err() error {
+	if len(f.violations) == 0 {
+		return nil
+	}
+	return errs.ValidationError(f.violations)
+}
+
+func (f *fields) required(name string, id int32) int {
+	if id <= 0 {
+		f.violation(name, errs.FieldRequired, "%s is required", name)
+	}
+	return int(id)
+}
+
+// size returns value, or def when it is unset.
+func (f *fields) size(name string, value int32, def, max int) int {
+	if value == 0 {
+		return def
+	}
+	if value < 0 || int(value) > max {
+		f.violation(name, errs.FieldOutOfRange, "%s must be between %d and %d", name, 1, max)
+		return def
+	}
+	return int(value)
+}
+
+// date returns a date in YYYY-MM-DD format, or the current service date when
+// it is missing, so "now" in the same request refers to the same day.
+func (f *fields) date(name, v string) string {
+	if v == "" {
+		return utils.ServiceDate()
+	}
+	if !utils.ValidateDate(v) {
+		f.violation(name, errs.FieldInvalidFormat, "%s must be a date in YYYY-MM-DD format", name)
+		return utils.ServiceDate()
+	}
+	return v
+}
+
+// serviceTime parses a time of day given as HH:MM or "now", like
+// api.Binder.QueryServiceTime.
+func (f *fields) serviceTime(name, v string) *utils.ServiceTime {
+	if v == "" {
+		return nil
+	}
+	if v == "now" {
+		t := utils.NowServiceTime()
+		return &t
+	}
+
+	t, err := utils.ServiceTimeFromClock(v)
+	if err != nil {
+		if t, err = utils.ParseServiceTime(v); err != nil {
+			f.violation(name, errs.FieldInvalidFormat, "%s must be a time in HH:MM format or 'now'", name)
+			return nil
+		}
+	}
+	return &t
+}
+
+// attributes returns the names of the given station attributes.
+func (f *fields) attributes(name string, values []busv1.StationAttribute) []string {
+	names := make([]string, 0, len(values))
+	for _, v := range values {
+		attr, ok := stationAttributes[v]
+		if !ok {
+			f.violation(name, errs.FieldInvalidValue, "%s must be one of: %s", name, strings.Join(store.StationAttributeNames, ", "))
+			return nil
+		}
+		names = append(names, attr)
+	}
+	return names
+}
+
+// duration returns d, or def when it is unset, raised to min.
+func (f *fields) duration(name string, d *durationpb.Duration, def, min time.Duration) time.Duration {
+	if d == nil {
+		return def
+	}
+	if err := d.CheckValid(); err != nil {
+		f.violation(name, errs.FieldInvalidFormat, "%s must be a %s", name, "duration")
+		return def
+	}
+	return max(d.AsDuration(), min)
+}
+
+// page builds a request for the first page or the page after token, sorted
+// by name.
+func (f *fields) page(sizeField string, size int32, tokenField, token string, def int) pagination.Request {
+	page := pagination.Request{
+		Limit: f.size(sizeField, size, def, maxPageSize),
+		Sort:  pagination.Sort{Field: pagination.SortName},
+	}
+	if token == "" {
+		return page
+	}
+
+	cursor, err := pagination.DecodeCursor(token)
+	if err != nil {
+		f.violation(tokenField, errs.FieldInvalidFormat, "%s is not a valid page token", tokenField)
+		return page
+	}
+	page.Cursor = cursor
+	if err := page.Validate(); err != nil {
+		f.violation(tokenField, errs.FieldInvalidValue, "%s is not a valid page token", tokenField)
+		page.Cursor = nil
+	}
+	return page
+}
@@ -0,0 +1,38 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/health"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// ReportHealth keeps the serving status of services in srv in sync with the
+// readiness checks, re-running them every interval until ctx is done. A
+// degraded report still serves, as /health/ready does.
+func ReportHealth(ctx context.Context, srv *grpchealth.Server, checker *health.Checker, interval time.Duration, logger *slog.Logger, services ...string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report := checker.Run(ctx)
+		status := healthpb.HealthCheckResponse_SERVING
+		if report.Status == health.StatusDown {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+			logger.Warn("readiness check failed", slog.Any("checks", report.Checks))
+		}
+		for _, service := range services {
+			srv.SetServingStatus(service, status)
+		}
+
+		select {
+		case <-ctx.Done():
+			srv.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryInterceptor gives unary calls what the HTTP middleware gives requests:
+// a span, the negotiated language, a deadline of at most timeout, panic
+// recovery and status errors.
+func UnaryInterceptor(timeout time.Duration, logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		ctx, span := telemetry.StartSpan(ctx, "rpc "+info.FullMethod, attribute.String("rpc.method", info.FullMethod))
+		defer func() { telemetry.EndSpan(span, err) }()
+
+		ctx = withLanguage(ctx)
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		defer func() {
+			if p := recover(); p != nil {
+				err = toStatus(ctx, logger, info.FullMethod, panicError(p))
+			}
+		}()
+
+		resp, err = handler(ctx, req)
+		if err != nil {
+			err = toStatus(ctx, logger, info.FullMethod, err)
+		}
+		return resp, err
+	}
+}
+
+// StreamInterceptor is UnaryInterceptor for streams, which are not bounded by
+// a deadline.
+func StreamInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		ctx, span := telemetry.StartSpan(ss.Context(), "rpc "+info.FullMethod, attribute.String("rpc.method", info.FullMethod))
+		defer func() { telemetry.EndSpan(span, err) }()
+
+		ctx = withLanguage(ctx)
+		defer func() {
+			if p := recover(); p != nil {
+				err = toStatus(ctx, logger, info.FullMethod, panicError(p))
+			}
+		}()
+
+		err = handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		if err != nil {
+			err = toStatus(ctx, logger, info.FullMethod, err)
+		}
+		return err
+	}
+}
+
+// withLanguage negotiates the language from the accept-language metadata.
+func withLanguage(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var acceptLanguage string
+	if values := md.Get("accept-language"); len(values) > 0 {
+		acceptLanguage = values[0]
+	}
+	return i18n.WithLang(ctx, i18n.Negotiate(acceptLanguage))
+}
+
+func panicError(p any) error {
+	return fmt.Errorf("panic: %v\n%s", p, debug.Stack())
+}
+
+// serverStream overrides the context of a stream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/app"
+	busv1 "github.com/perkzen/mbus/apps/bus-service/internal/pb/bus/v1"
+	"github.com/perkzen/mbus/apps/bus-service/internal/rpc"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// healthInterval is how often the gRPC health status re-runs the readiness
+// checks.
+const healthInterval = 15 * time.Second
+
+// NewGrpcServer serves the BusService together with the standard health and
+// reflection services. The health status follows the readiness checks until
+// ctx is done.
+func NewGrpcServer(ctx context.Context, app *app.Application) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(rpc.UnaryInterceptor(app.Env.RequestTimeout, app.Logger)),
+		grpc.ChainStreamInterceptor(rpc.StreamInterceptor(app.Logger)),
+	)
+
+	busv1.RegisterBusServiceServer(srv, app.BusService)
+
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(srv, healthServer)
+	go rpc.ReportHealth(ctx, healthServer, app.HealthChecker, healthInterval, app.Logger,
+		"", busv1.BusService_ServiceDesc.ServiceName)
+
+	reflection.Register(srv)
+	return srv
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/perkzen/mbus/apps/bus-service/internal/app"
 	"github.com/perkzen/mbus/apps/bus-service/internal/routes"
+	"google.golang.org/grpc"
 	"log"
 	"net/http"
 	"os/signal"
@@ -14,12 +15,16 @@ import (
 
 func NewHttpServer(app *app.Application) *http.Server {
 	return &http.Server{
-		Addr:    fmt.Sprintf(":%d", app.Env.Port),
-		Handler: routes.RegisterRoutes(app),
+		Addr:              fmt.Sprintf(":%d", app.Env.Port),
+		Handler:           routes.RegisterRoutes(app),
+		ReadHeaderTimeout: 5 * time.Second,
+		// Leave room for the handler to write its 504 after REQUEST_TIMEOUT.
+		WriteTimeout: app.Env.RequestTimeout + 5*time.Second,
+		IdleTimeout:  60 * time.Second,
 	}
 }
 
-func GracefulShutdown(server *http.Server, done chan bool) {
+func GracefulShutdown(server *http.Server, grpcServer *grpc.Server, done chan bool) {
 	// Create context that listens for the interrupt signal from the OS.
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -34,10 +39,26 @@ func GracefulShutdown(server *http.Server, done chan bool) {
 	// the request it is currently handling
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
+
+	// Departure board streams only end when their clients leave, so gRPC
+	// gets the same deadline before remaining calls are cancelled.
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+
 	if err := server.Shutdown(ctx); err != nil {
 		log.Printf("Server forced to shutdown with error: %v", err)
 	}
 
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+		log.Println("gRPC server forced to stop")
+	}
+
 	log.Println("Server exiting")
 
 	// Notify the main goroutine that the shutdown is complete
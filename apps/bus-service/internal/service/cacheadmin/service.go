@@ -0,0 +1,154 @@
+package cacheadmin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/cache"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+)
+
+// maxListedKeys caps how many keys ListKeys returns in one response.
+const maxListedKeys = 1000
+
+// datePattern matches the YYYY-MM-DD service date that follows the station
+// ids in a timetable key.
+const datePattern = "[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]"
+
+type KeyList struct {
+	Pattern   string   `json:"pattern"`
+	Count     int      `json:"count"`
+	Truncated bool     `json:"truncated"`
+	Keys      []string `json:"keys"`
+} // @name CacheKeyList
+
+type PurgeResult struct {
+	Pattern string `json:"pattern,omitempty"`
+	Deleted int    `json:"deleted"`
+} // @name CachePurgeResult
+
+// Service inspects and invalidates cached entries and manages the data
+// generation that namespaces every versioned key.
+type Service struct {
+	cache            cache.Cache
+	namespace        *cache.Namespace
+	dataVersionStore store.DataVersionStore
+	busStationStore  store.BusStationStore
+}
+
+func NewService(
+	c cache.Cache,
+	namespace *cache.Namespace,
+	dataVersionStore store.DataVersionStore,
+	busStationStore store.BusStationStore,
+) *Service {
+	return &Service{
+		cache:            c,
+		namespace:        namespace,
+		dataVersionStore: dataVersionStore,
+		busStationStore:  busStationStore,
+	}
+}
+
+// BumpDataVersion starts a new data generation. Existing keys are not deleted;
+// they become unreachable and expire on their own.
+func (s *Service) BumpDataVersion(ctx context.Context, reason string) (*store.DataVersion, error) {
+	version, err := s.dataVersionStore.BumpDataVersion(ctx, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bump data version: %w", err)
+	}
+
+	s.namespace.SetVersion(version.Generation, version.UpdatedAt)
+	return version, nil
+}
+
+func (s *Service) ListKeys(ctx context.Context, pattern string) (*KeyList, error) {
+	pattern = scopePattern(pattern)
+
+	keys, err := s.cache.Keys(ctx, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cache keys: %w", err)
+	}
+
+	list := &KeyList{Pattern: pattern, Count: len(keys), Keys: keys}
+	if len(keys) > maxListedKeys {
+		list.Keys = keys[:maxListedKeys]
+		list.Truncated = true
+	}
+	return list, nil
+}
+
+func (s *Service) Purge(ctx context.Context, pattern string) (*PurgeResult, error) {
+	pattern = scopePattern(pattern)
+
+	deleted, err := s.deleteMatching(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &PurgeResult{Pattern: pattern, Deleted: deleted}, nil
+}
+
+// InvalidateStation drops every cached timetable of the current generation
+// that starts or ends at the station. Keys are "timetable:<from>:<to>:<date>"
+// followed by optional filter parts, so the destination is anchored on the
+// date after it rather than on a wildcard that could span the filter parts.
+func (s *Service) InvalidateStation(ctx context.Context, stationID int) (*PurgeResult, error) {
+	prefix := s.namespace.Prefix(ctx)
+
+	total := 0
+	for _, pattern := range []string{
+		fmt.Sprintf("%stimetable:%d:*", prefix, stationID),
+		fmt.Sprintf("%stimetable:*:%d:%s*", prefix, stationID, datePattern),
+	} {
+		deleted, err := s.deleteMatching(ctx, pattern)
+		if err != nil {
+			return nil, err
+		}
+		total += deleted
+	}
+
+	return &PurgeResult{Deleted: total}, nil
+}
+
+// InvalidateLine drops cached timetables for every station the line serves.
+func (s *Service) InvalidateLine(ctx context.Context, line string) (*PurgeResult, error) {
+	stationIDs, err := s.busStationStore.FindBusStationIDsByLine(ctx, line)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stations of line %s: %w", line, err)
+	}
+
+	total := 0
+	for _, id := range stationIDs {
+		res, err := s.InvalidateStation(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		total += res.Deleted
+	}
+
+	return &PurgeResult{Deleted: total}, nil
+}
+
+func (s *Service) deleteMatching(ctx context.Context, pattern string) (int, error) {
+	keys, err := s.cache.Keys(ctx, pattern)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list cache keys: %w", err)
+	}
+
+	if err := s.cache.Delete(ctx, keys...); err != nil {
+		return 0, fmt.Errorf("failed to delete cache keys: %w", err)
+	}
+	return len(keys), nil
+}
+
+// scopePattern keeps admin patterns inside the service's own key prefix.
+func scopePattern(pattern string) string {
+	if pattern == "" {
+		pattern = "*"
+	}
+	if !strings.HasPrefix(pattern, cache.KeyPrefix) {
+		pattern = cache.KeyPrefix + pattern
+	}
+	return pattern
+}
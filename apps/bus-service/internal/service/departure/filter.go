@@ -0,0 +1,86 @@
+package departure
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+// Filter narrows a timetable. Lines and Direction select which departures are
+// loaded and are part of the cache key; the time window, limit and step-free
+// preference are cheap to apply and vary per request, so with caching enabled
+// they are applied to the cached rows instead of multiplying cache entries.
+type Filter struct {
+	Lines     []string
+	Direction string
+	After     *utils.ServiceTime
+	Before    *utils.ServiceTime
+	Limit     int
+	// StepFree keeps only journeys between wheelchair accessible stations.
+	StepFree bool
+}
+
+// normalize trims, lower-cases the direction and sorts and de-duplicates the
+// lines, so equivalent filters share one cache entry.
+func (f Filter) normalize() Filter {
+	lines := make([]string, 0, len(f.Lines))
+	for _, l := range f.Lines {
+		if l = strings.ToUpper(strings.TrimSpace(l)); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	slices.Sort(lines)
+
+	f.Lines = slices.Compact(lines)
+	f.Direction = strings.ToLower(strings.TrimSpace(f.Direction))
+	return f
+}
+
+// cacheKeyParts identifies the loaded departures. Unfiltered timetables keep
+// the key they had before filters existed.
+func (f Filter) cacheKeyParts() []any {
+	if len(f.Lines) == 0 && f.Direction == "" {
+		return nil
+	}
+	return []any{"l=" + strings.Join(f.Lines, ","), "d=" + f.Direction}
+}
+
+// departureFilter returns the SQL filter for the departures at the origin.
+// withWindow includes the time window and limit.
+func (f Filter) departureFilter(withWindow bool) *store.DepartureFilter {
+	df := &store.DepartureFilter{
+		Lines:     f.Lines,
+		Direction: f.Direction,
+	}
+	if withWindow {
+		df.After = f.After
+		df.Before = f.Before
+		df.Limit = f.Limit
+	}
+	return df
+}
+
+// applyWindow keeps the rows inside the time window that match the step-free
+// preference, up to the limit. rows must be sorted by departure.
+func (f Filter) applyWindow(rows []TimetableRow) []TimetableRow {
+	filtered := make([]TimetableRow, 0, len(rows))
+	for _, row := range rows {
+		if f.StepFree && !row.StepFree {
+			continue
+		}
+		at := row.GetDepartureAt()
+		if f.After != nil && at < *f.After {
+			continue
+		}
+		if f.Before != nil && at > *f.Before {
+			continue
+		}
+		filtered = append(filtered, row)
+		if f.Limit > 0 && len(filtered) == f.Limit {
+			break
+		}
+	}
+	return filtered
+}
@@ -2,26 +2,39 @@ package departure
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/perkzen/mbus/apps/bus-service/internal/cache"
 	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
-	"github.com/perkzen/mbus/apps/bus-service/internal/provider/openrouteservice"
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/provider/estimator"
+	"github.com/perkzen/mbus/apps/bus-service/internal/provider/routing"
 	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
 	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
-	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"slices"
 	"strings"
 	"time"
 )
 
 type Service struct {
-	orsApiClient    *openrouteservice.APIClient
-	cache           *redis.Client
+	router          routing.Provider
+	cache           cache.Cache
+	namespace       *cache.Namespace
 	busStationStore store.BusStationStore
 	departureStore  store.DepartureStore
 	busLineStore    store.BusLineStore
 	directionStore  store.DirectionStore
 	enableCache     bool
+	cacheOptions    []utils.CacheOption
+	estimator       *estimator.Estimator
+	estimatorMode   string
+	segmentStore    store.SegmentStore
 }
 
+var errNoDepartures = errors.New("no departures found between given station codes")
+
 type Option func(*Service)
 
 func WithCache(enabled bool) Option {
@@ -30,9 +43,35 @@ func WithCache(enabled bool) Option {
 	}
 }
 
+// WithEstimator enables offline travel-time estimation. mode is one of
+// estimator.ModeFallback, estimator.ModeExclusive or estimator.ModeDisabled.
+func WithEstimator(e *estimator.Estimator, mode string) Option {
+	return func(s *Service) {
+		s.estimator = e
+		s.estimatorMode = mode
+	}
+}
+
+// WithSegments sums travel times from precomputed stop-to-stop segments
+// where they exist instead of routing between the two stations.
+func WithSegments(segmentStore store.SegmentStore) Option {
+	return func(s *Service) {
+		s.segmentStore = segmentStore
+	}
+}
+
+// WithCacheOptions tunes how timetables are cached, e.g. stale-while-revalidate
+// or negative caching of empty timetables.
+func WithCacheOptions(opts ...utils.CacheOption) Option {
+	return func(s *Service) {
+		s.cacheOptions = append(s.cacheOptions, opts...)
+	}
+}
+
 func NewService(
-	orsApiClient *openrouteservice.APIClient,
-	cache *redis.Client,
+	router routing.Provider,
+	cache cache.Cache,
+	namespace *cache.Namespace,
 	busStationStore store.BusStationStore,
 	departureStore store.DepartureStore,
 	busLineStore store.BusLineStore,
@@ -41,8 +80,9 @@ func NewService(
 
 ) *Service {
 	s := &Service{
-		orsApiClient:    orsApiClient,
+		router:          router,
 		cache:           cache,
+		namespace:       namespace,
 		busStationStore: busStationStore,
 		departureStore:  departureStore,
 		busLineStore:    busLineStore,
@@ -57,53 +97,101 @@ func NewService(
 	return s
 }
 
-func (s *Service) GenerateTimetable(fromID, toID int, date string) ([]TimetableRow, error) {
-	ctx := context.Background()
-	cacheKey := fmt.Sprintf("timetable_%d_%d_%s", fromID, toID, date)
+func (s *Service) GenerateTimetable(ctx context.Context, fromID, toID int, date string, filter Filter) (_ []TimetableRow, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "departure.GenerateTimetable",
+		attribute.Int("departure.from_id", fromID),
+		attribute.Int("departure.to_id", toID),
+		attribute.String("departure.date", date),
+		attribute.StringSlice("departure.lines", filter.Lines),
+		attribute.String("departure.direction", filter.Direction),
+	)
+	defer func() { telemetry.EndSpan(span, err) }()
 
-	loader := func() ([]TimetableRow, error) {
-		return s.buildDeparturesTimetable(fromID, toID, date)
+	filter = filter.normalize()
+
+	if !s.enableCache {
+		rows, err := s.buildDeparturesTimetable(ctx, fromID, toID, date, filter.departureFilter(true))
+		if err != nil {
+			return nil, err
+		}
+		return filter.applyWindow(rows), nil
 	}
 
-	if s.enableCache {
-		return utils.WithCache(ctx, s.cache, cacheKey, 24*time.Hour, loader)
+	keyParts := append([]any{"timetable", fromID, toID, date}, filter.cacheKeyParts()...)
+	cacheKey := s.namespace.Key(ctx, keyParts...)
+
+	loader := func(ctx context.Context) ([]TimetableRow, error) {
+		return s.buildDeparturesTimetable(ctx, fromID, toID, date, filter.departureFilter(false))
 	}
 
-	return loader()
+	opts := s.cacheOptions
+	if s.estimator != nil && s.estimatorMode == estimator.ModeFallback {
+		opts = append(slices.Clip(opts), utils.WithDegradedTTL(estimatedTTL, hasEstimatedRows))
+	}
+
+	rows, err := utils.WithCache(ctx, s.cache, cacheKey, 24*time.Hour, loader, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter.applyWindow(rows), nil
+}
+
+// hasEstimatedRows reports whether a cached timetable holds travel times the
+// estimator filled in for a failing routing provider.
+func hasEstimatedRows(value any) bool {
+	rows, _ := value.([]TimetableRow)
+	return slices.ContainsFunc(rows, func(row TimetableRow) bool { return row.Estimated })
 }
 
-func (s *Service) buildDeparturesTimetable(fromID, toID int, date string) ([]TimetableRow, error) {
-	fromStation, err := s.busStationStore.FindBusStationByID(fromID)
+func (s *Service) buildDeparturesTimetable(ctx context.Context, fromID, toID int, date string, filter *store.DepartureFilter) (_ []TimetableRow, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "departure.buildDeparturesTimetable")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	fromStation, err := s.busStationStore.FindBusStationByID(ctx, fromID)
 	if err != nil {
+		return nil, fmt.Errorf("failed to find bus station %d: %w", fromID, err)
+	}
+	if fromStation == nil {
 		return nil, errs.BusStationNotFoundError(fromID)
 	}
 
-	toStation, err := s.busStationStore.FindBusStationByID(toID)
+	toStation, err := s.busStationStore.FindBusStationByID(ctx, toID)
 	if err != nil {
+		return nil, fmt.Errorf("failed to find bus station %d: %w", toID, err)
+	}
+	if toStation == nil {
 		return nil, errs.BusStationNotFoundError(toID)
 	}
 
-	locs := [][]float64{{fromStation.Lon, fromStation.Lat}, {toStation.Lon, toStation.Lat}}
-	matrix, err := s.orsApiClient.GetMatrix(locs)
+	schedule := store.ScheduleTyp(date)
+
+	fromCode, toCode, departures, err := s.findValidDeparturePair(ctx, fromStation, toStation, date, filter)
+	if errors.Is(err, errNoDepartures) {
+		return []TimetableRow{}, nil
+	}
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to find valid departure pair: %w", err)
 	}
 
-	distance := matrix.Distances[0][1]
-	duration := matrix.Durations[0][1]
-	schedule := store.ScheduleTyp(date)
+	fromStop, toStop, err := s.findStops(ctx, fromStation.ID, toStation.ID, fromCode, toCode)
+	if err != nil {
+		return nil, err
+	}
 
-	fromCode, toCode, departures, err := s.findValidDeparturePair(fromStation, toStation, date)
+	lines, err := s.findLineRefs(ctx, departures)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find valid departure pair: %w", err)
+		return nil, err
 	}
 
-	directions, err := s.directionStore.FindSharedDirectionsByCodes(fromCode, toCode)
+	travel := s.newJourneyTravel(fromStation, toStation, fromCode, toCode, departures)
+
+	directions, err := s.directionStore.FindSharedDirectionsByCodes(ctx, fromCode, toCode)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find shared directions: %w", err)
 	}
 
-	toDeparturesMap, err := s.buildToDeparturesMap(toCode, directions, toStation, departures, schedule)
+	toDeparturesMap, err := s.buildToDeparturesMap(ctx, toCode, directions, toStation, departures, schedule)
 	if err != nil {
 		return nil, err
 	}
@@ -112,24 +200,29 @@ func (s *Service) buildDeparturesTimetable(fromID, toID int, date string) ([]Tim
 	for _, dep := range departures {
 		toDepTimes := toDeparturesMap[dep.Direction]
 
-		arriveAt, err := getArriveAt(dep.DepartureTime, dep.Direction, toDepTimes, duration)
+		routeTravel, err := travel.get(ctx, dep.Line.Name, dep.Direction)
 		if err != nil {
-			continue
+			return nil, err
 		}
 
-		start, _ := utils.ParseClock(dep.DepartureTime)
-		end, _ := utils.ParseClock(arriveAt)
+		arriveAt := getArriveAt(dep.DepartureTime, dep.Direction, toDepTimes, routeTravel.durationMin)
 
 		rows = append(rows, TimetableRow{
 			ID:          dep.ID,
 			Direction:   dep.Direction,
 			Line:        dep.Line.Name,
-			FromStation: Station{Name: fromStation.Name, ID: fromStation.ID},
-			ToStation:   Station{Name: toStation.Name, ID: toStation.ID},
-			DepartureAt: dep.DepartureTime,
-			ArriveAt:    arriveAt,
-			Duration:    utils.FormatDuration(start, end),
-			Distance:    distance,
+			LineDetails: lines[dep.Line.ID],
+			FromStation: Station{Name: fromStation.Name, ID: fromStation.ID, Stop: fromStop},
+			ToStation:   Station{Name: toStation.Name, ID: toStation.ID, Stop: toStop},
+			Duration:    utils.FormatDuration(dep.DepartureTime, arriveAt),
+			Distance:    routeTravel.distanceKm,
+			Estimated:   routeTravel.estimated,
+			StepFree:    fromStation.Attributes.StepFree() && toStation.Attributes.StepFree(),
+
+			DepartureAt:        dep.DepartureTime.Clock(),
+			DepartureDayOffset: dep.DepartureTime.DayOffset(),
+			ArriveAt:           arriveAt.Clock(),
+			ArriveDayOffset:    arriveAt.DayOffset(),
 		})
 	}
 
@@ -137,13 +230,16 @@ func (s *Service) buildDeparturesTimetable(fromID, toID int, date string) ([]Tim
 	return rows, nil
 }
 
-func (s *Service) findValidDeparturePair(fromStation, toStation *store.BusStation, date string) (int, int, []store.Departure, error) {
+func (s *Service) findValidDeparturePair(ctx context.Context, fromStation, toStation *store.BusStation, date string, filter *store.DepartureFilter) (_ int, _ int, _ []store.Departure, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "departure.findValidDeparturePair")
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	schedule := store.ScheduleTyp(date)
 
 	// Try to find departures where toStation is final stop
 	for _, fromCode := range fromStation.Codes {
 		for _, toCode := range toStation.Codes {
-			if departures, err := s.findDeparturesViaDirection(fromCode, toStation.SanitizedName(), schedule); err == nil && len(departures) > 0 {
+			if departures, err := s.findDeparturesViaDirection(ctx, fromCode, toStation.SanitizedName(), schedule, filter); err == nil && len(departures) > 0 {
 				return fromCode, toCode, departures, nil
 			}
 		}
@@ -152,7 +248,7 @@ func (s *Service) findValidDeparturePair(fromStation, toStation *store.BusStatio
 	// Fallback to direct departure lookup
 	for _, fromCode := range fromStation.Codes {
 		for _, toCode := range toStation.Codes {
-			departures, err := s.departureStore.FindDepartures(fromCode, toCode, schedule)
+			departures, err := s.departureStore.FindDepartures(ctx, fromCode, toCode, schedule, filter)
 			if err != nil {
 				return 0, 0, nil, fmt.Errorf("failed to fetch departures from %d to %d: %w", fromCode, toCode, err)
 			}
@@ -162,34 +258,78 @@ func (s *Service) findValidDeparturePair(fromStation, toStation *store.BusStatio
 		}
 	}
 
-	return 0, 0, nil, fmt.Errorf("no departures found between given station codes")
+	return 0, 0, nil, errNoDepartures
+}
+
+// findStops returns the stops with the codes a journey departs from and
+// arrives at.
+func (s *Service) findStops(ctx context.Context, fromID, toID, fromCode, toCode int) (fromStop, toStop *store.StationCode, err error) {
+	codes, err := s.busStationStore.FindStationCodesByStationIDs(ctx, []int{fromID, toID})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find stops: %w", err)
+	}
+	for i := range codes {
+		switch codes[i].Code {
+		case fromCode:
+			fromStop = &codes[i]
+		case toCode:
+			toStop = &codes[i]
+		}
+	}
+	return fromStop, toStop, nil
+}
+
+// findLineRefs returns the lines of departures with their metadata by id.
+func (s *Service) findLineRefs(ctx context.Context, departures []store.Departure) (map[int]*store.LineRef, error) {
+	ids := make([]int, 0)
+	for _, dep := range departures {
+		if !slices.Contains(ids, dep.Line.ID) {
+			ids = append(ids, dep.Line.ID)
+		}
+	}
+
+	lines, err := s.busLineStore.FindBusLinesByIDs(ctx, ids, i18n.Default)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find lines: %w", err)
+	}
+	refs := make(map[int]*store.LineRef, len(lines))
+	for _, l := range lines {
+		refs[l.ID] = &store.LineRef{ID: l.ID, Name: l.Name, LineMetadata: l.LineMetadata}
+	}
+	return refs, nil
 }
 
-func (s *Service) findDeparturesViaDirection(fromCode int, sanitizedToName string, schedule store.ScheduleType) ([]store.Departure, error) {
-	directions, err := s.directionStore.FindDirectionsByStationCode(fromCode)
+func (s *Service) findDeparturesViaDirection(ctx context.Context, fromCode int, sanitizedToName string, schedule store.ScheduleType, filter *store.DepartureFilter) ([]store.Departure, error) {
+	directions, err := s.directionStore.FindDirectionsByStationCode(ctx, fromCode)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find directions by station code %d: %w", fromCode, err)
 	}
 
 	for _, dir := range directions {
 		if strings.HasSuffix(dir.Name, sanitizedToName) {
-			return s.departureStore.FindDeparturesByStationCodeAndDirection(fromCode, dir.Name, schedule)
+			return s.departureStore.FindDeparturesByStationCodeAndDirection(ctx, fromCode, dir.Name, schedule, filter)
 		}
 	}
 	return nil, nil
 }
 
 func (s *Service) buildToDeparturesMap(
+	ctx context.Context,
 	toCode int,
 	directions []string,
 	toStation *store.BusStation,
 	departures []store.Departure,
 	schedule store.ScheduleType,
-) (map[string][]store.Departure, error) {
+) (_ map[string][]store.Departure, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "departure.buildToDeparturesMap",
+		attribute.Int("departure.directions", len(directions)),
+	)
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	toDeparturesMap := make(map[string][]store.Departure)
 
 	for _, dir := range directions {
-		toDepartures, err := s.departureStore.FindDeparturesByStationCodeAndDirection(toCode, dir, schedule)
+		toDepartures, err := s.departureStore.FindDeparturesByStationCodeAndDirection(ctx, toCode, dir, schedule, nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find departures for direction %s: %w", dir, err)
 		}
@@ -209,26 +349,23 @@ func ensureEmptyDirectionSlotIfFinalStop(toStation *store.BusStation, departures
 	}
 }
 
-func getArriveAt(fromDepTime, direction string, toDeps []store.Departure, durationMin float64) (string, error) {
-	fromTime, err := utils.ParseClock(fromDepTime)
-	if err != nil {
-		return "", fmt.Errorf("invalid from departure time: %w", err)
-	}
-
+// getArriveAt returns the first departure of the same direction at the
+// destination after fromTime. Service times keep counting past midnight, so a
+// night trip's arrival never sorts before its departure.
+func getArriveAt(fromTime utils.ServiceTime, direction string, toDeps []store.Departure, durationMin float64) utils.ServiceTime {
+	arrival := utils.ServiceTime(-1)
 	for _, toDep := range toDeps {
-		if toDep.Direction != direction {
+		if toDep.Direction != direction || toDep.DepartureTime <= fromTime {
 			continue
 		}
-		toTime, err := utils.ParseClock(toDep.DepartureTime)
-		if err != nil {
-			continue
-		}
-		if toTime.After(fromTime) {
-			return toDep.DepartureTime, nil
+		if arrival < 0 || toDep.DepartureTime < arrival {
+			arrival = toDep.DepartureTime
 		}
 	}
+	if arrival >= 0 {
+		return arrival
+	}
 
 	// Fallback: add travel duration to departure time
-	arrival := fromTime.Add(time.Duration(durationMin * float64(time.Minute)))
-	return arrival.Format("15:04"), nil
+	return fromTime.AddMinutes(durationMin)
 }
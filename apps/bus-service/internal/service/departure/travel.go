@@ -0,0 +1,178 @@
+package departure
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/provider/estimator"
+	"github.com/perkzen/mbus/apps/bus-service/internal/provider/routing"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+)
+
+const (
+	// stopDwellMinutes is the time a bus spends at each intermediate stop when
+	// travel time is summed from segments.
+	stopDwellMinutes = 0.5
+
+	// stopSpacingKm is the typical road distance between consecutive stops;
+	// the median in the seed timetable is about 530 m.
+	stopSpacingKm = 0.5
+
+	// dwellMinutesPerKm spreads stopDwellMinutes over the stops a
+	// point-to-point route passes without stopping at them, which routing
+	// engines do not model, so it agrees with times summed from segments.
+	dwellMinutesPerKm = stopDwellMinutes / stopSpacingKm
+
+	// estimatedTTL bounds how long a timetable with estimated travel times is
+	// cached when the estimator stands in for a failing routing provider, so
+	// routed times return soon after the provider recovers.
+	estimatedTTL = 5 * time.Minute
+)
+
+type travelInfo struct {
+	distanceKm  float64
+	durationMin float64
+	estimated   bool
+}
+
+// journeyTravel resolves travel info per line and direction of a journey.
+// Precomputed segments are preferred; the point-to-point metrics between the
+// two stations are fetched at most once, when some route has no segments.
+type journeyTravel struct {
+	service          *Service
+	from, to         *store.BusStation
+	fromCode, toCode int
+	lines            []string
+
+	byRoute map[[2]string]travelInfo
+	direct  *travelInfo
+}
+
+func (s *Service) newJourneyTravel(from, to *store.BusStation, fromCode, toCode int, departures []store.Departure) *journeyTravel {
+	return &journeyTravel{
+		service:  s,
+		from:     from,
+		to:       to,
+		fromCode: fromCode,
+		toCode:   toCode,
+		lines:    departureLines(departures),
+		byRoute:  make(map[[2]string]travelInfo),
+	}
+}
+
+func (j *journeyTravel) get(ctx context.Context, line, direction string) (travelInfo, error) {
+	key := [2]string{line, direction}
+	if travel, ok := j.byRoute[key]; ok {
+		return travel, nil
+	}
+
+	travel, ok, err := j.service.segmentTravel(ctx, line, direction, j.fromCode, j.toCode)
+	if err != nil {
+		return travelInfo{}, err
+	}
+
+	if !ok {
+		if j.direct == nil {
+			direct, err := j.service.travelMetrics(ctx, j.from, j.to, j.lines)
+			if err != nil {
+				return travelInfo{}, err
+			}
+			j.direct = &direct
+		}
+		travel = *j.direct
+	}
+
+	j.byRoute[key] = travel
+	return travel, nil
+}
+
+// segmentTravel sums the precomputed segments of a line between two stop
+// codes. ok is false when segments are disabled or do not cover the trip.
+func (s *Service) segmentTravel(ctx context.Context, line, direction string, fromCode, toCode int) (_ travelInfo, ok bool, err error) {
+	if s.segmentStore == nil {
+		return travelInfo{}, false, nil
+	}
+
+	segments, err := s.segmentStore.FindSegments(ctx, line, direction)
+	if err != nil {
+		return travelInfo{}, false, fmt.Errorf("failed to find segments of line %s: %w", line, err)
+	}
+
+	var travel travelInfo
+	var stops int
+	started := false
+	for _, seg := range segments {
+		if !started && seg.FromCode != fromCode {
+			continue
+		}
+		started = true
+
+		travel.distanceKm += seg.DistanceKm
+		travel.durationMin += seg.DurationMin
+		stops++
+
+		if seg.ToCode == toCode {
+			travel.distanceKm = math.Round(travel.distanceKm*100) / 100
+			travel.durationMin = math.Round(travel.durationMin + float64(stops-1)*stopDwellMinutes)
+			return travel, true, nil
+		}
+	}
+
+	return travelInfo{}, false, nil
+}
+
+// travelMetrics resolves distance and travel time between two stations,
+// falling back to the offline estimator according to the configured mode.
+func (s *Service) travelMetrics(ctx context.Context, from, to *store.BusStation, lines []string) (travelInfo, error) {
+	if s.estimator != nil && s.estimatorMode == estimator.ModeExclusive {
+		return s.estimate(ctx, from, to, lines), nil
+	}
+
+	points := []routing.Point{{Lat: from.Lat, Lon: from.Lon}, {Lat: to.Lat, Lon: to.Lon}}
+	matrix, err := s.router.Matrix(ctx, points)
+	if err == nil {
+		var distance, duration float64
+		if distance, duration, err = matrix.Cell(0, 1); err == nil {
+			return travelInfo{
+				distanceKm:  distance,
+				durationMin: math.Round(duration + distance*dwellMinutesPerKm),
+			}, nil
+		}
+	}
+
+	if s.estimator == nil || s.estimatorMode == estimator.ModeDisabled || ctx.Err() != nil {
+		return travelInfo{}, fmt.Errorf("failed to get routing matrix: %w", err)
+	}
+
+	slog.Warn("routing provider failed, using offline estimate",
+		slog.Int("from", from.ID),
+		slog.Int("to", to.ID),
+		slog.Any("error", err),
+	)
+	return s.estimate(ctx, from, to, lines), nil
+}
+
+func (s *Service) estimate(ctx context.Context, from, to *store.BusStation, lines []string) travelInfo {
+	est := s.estimator.Estimate(ctx, from.Lat, from.Lon, to.Lat, to.Lon, lines)
+	return travelInfo{
+		distanceKm:  est.DistanceKm,
+		durationMin: est.DurationMin,
+		estimated:   true,
+	}
+}
+
+func departureLines(departures []store.Departure) []string {
+	seen := make(map[string]struct{})
+	lines := make([]string, 0)
+	for _, dep := range departures {
+		if _, ok := seen[dep.Line.Name]; ok {
+			continue
+		}
+		seen[dep.Line.Name] = struct{}{}
+		lines = append(lines, dep.Line.Name)
+	}
+	return lines
+}
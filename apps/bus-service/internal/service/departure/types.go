@@ -1,8 +1,16 @@
 package departure
 
+import (
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
 type Station struct {
 	Name string `json:"name"`
 	ID   int    `json:"id"`
+	// Stop is the platform to board at, or to get off at for the
+	// destination.
+	Stop *store.StationCode `json:"stop,omitempty"`
 } // @name TimetableRow.Station
 
 type TimetableRow struct {
@@ -13,10 +21,29 @@ type TimetableRow struct {
 	ToStation   Station `json:"toStation"`
 	Duration    string  `json:"duration"`
 	Distance    float64 `json:"distance"`
-	DepartureAt string  `json:"departureAt"`
-	ArriveAt    string  `json:"arriveAt"`
+	// Estimated is set when distance and travel time come from the offline
+	// estimator instead of the routing provider.
+	Estimated bool `json:"estimated"`
+	// StepFree is set when both stations are known to be wheelchair
+	// accessible.
+	StepFree bool `json:"stepFree"`
+	// LineDetails is the line with its metadata.
+	LineDetails *store.LineRef `json:"lineDetails,omitempty"`
+	// DepartureAt and ArriveAt are wall-clock times. The day offsets count the
+	// midnights passed since the start of the requested service day, e.g. a
+	// night bus arriving at 00:10 has an arriveDayOffset of 1.
+	DepartureAt        string `json:"departureAt"`
+	DepartureDayOffset int    `json:"departureDayOffset"`
+	ArriveAt           string `json:"arriveAt"`
+	ArriveDayOffset    int    `json:"arriveDayOffset"`
 } // @name TimetableRow
 
-func (t TimetableRow) GetDepartureAt() string {
-	return t.DepartureAt
+func (t TimetableRow) GetDepartureAt() utils.ServiceTime {
+	clock, _ := utils.ParseServiceTime(t.DepartureAt)
+	return clock + utils.ServiceTime(t.DepartureDayOffset*utils.MinutesPerDay)
+}
+
+func (t TimetableRow) GetArriveAt() utils.ServiceTime {
+	clock, _ := utils.ParseServiceTime(t.ArriveAt)
+	return clock + utils.ServiceTime(t.ArriveDayOffset*utils.MinutesPerDay)
 }
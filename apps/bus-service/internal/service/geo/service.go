@@ -0,0 +1,227 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/cache"
+	"github.com/perkzen/mbus/apps/bus-service/internal/geojson"
+	"github.com/perkzen/mbus/apps/bus-service/internal/provider/routing"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Geometry sources for line shapes.
+const (
+	GeometryStops  = "stops"  // straight lines between consecutive stops
+	GeometryRouted = "routed" // road geometry from the routing provider
+)
+
+// maxRouteWaypoints keeps route requests within the waypoint limit of hosted
+// routing providers. Longer lines are routed in overlapping chunks.
+const maxRouteWaypoints = 50
+
+var ErrUnknownGeometry = errors.New("unknown geometry source")
+
+type StationFilter struct {
+	Line string
+	BBox *store.BBox
+}
+
+type LineFilter struct {
+	Line     string
+	BBox     *store.BBox
+	Geometry string
+}
+
+// lineShape is the cached shape of one line in one direction.
+type lineShape struct {
+	Line        string       `json:"line"`
+	DirectionID int          `json:"directionId"`
+	Direction   string       `json:"direction"`
+	Codes       []int        `json:"codes"`
+	Stops       [][2]float64 `json:"stops"`
+	Coordinates [][2]float64 `json:"coordinates"`
+	Source      string       `json:"source"`
+}
+
+// Service exports stations and line shapes as GeoJSON for map layers.
+type Service struct {
+	busStationStore store.BusStationStore
+	segmentStore    store.SegmentStore
+	router          routing.Provider
+	cache           cache.Cache
+	namespace       *cache.Namespace
+}
+
+func NewService(
+	busStationStore store.BusStationStore,
+	segmentStore store.SegmentStore,
+	router routing.Provider,
+	c cache.Cache,
+	namespace *cache.Namespace,
+) *Service {
+	return &Service{
+		busStationStore: busStationStore,
+		segmentStore:    segmentStore,
+		router:          router,
+		cache:           c,
+		namespace:       namespace,
+	}
+}
+
+// Stations returns every matching station as a Point feature.
+func (s *Service) Stations(ctx context.Context, filter StationFilter) (_ *geojson.FeatureCollection, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "geo.Stations")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	stations, err := s.busStationStore.ListAllBusStations(ctx, &store.BusStationFilterOptions{
+		Line: filter.Line,
+		BBox: filter.BBox,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bus stations: %w", err)
+	}
+
+	features := make([]geojson.Feature, 0, len(stations))
+	for _, st := range stations {
+		features = append(features, geojson.NewFeature(st.ID, geojson.NewPoint(st.Lon, st.Lat), map[string]any{
+			"name":     st.Name,
+			"codes":    st.Codes,
+			"lines":    st.Lines,
+			"imageUrl": st.ImageURL,
+		}))
+	}
+
+	return geojson.NewFeatureCollection(features), nil
+}
+
+// LineShapes returns one LineString feature per line and direction. With a
+// bounding box only shapes with at least one stop inside it are returned.
+func (s *Service) LineShapes(ctx context.Context, filter LineFilter) (_ *geojson.FeatureCollection, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "geo.LineShapes",
+		attribute.String("geo.geometry", filter.Geometry),
+	)
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	if filter.Geometry != GeometryStops && filter.Geometry != GeometryRouted {
+		return nil, ErrUnknownGeometry
+	}
+
+	loader := func(ctx context.Context) ([]lineShape, error) {
+		return s.buildLineShapes(ctx, filter.Geometry)
+	}
+
+	shapes, err := utils.WithCache(ctx, s.cache, s.namespace.Key(ctx, "geojson", "lines", filter.Geometry), 24*time.Hour, loader)
+	if err != nil {
+		return nil, err
+	}
+
+	features := make([]geojson.Feature, 0, len(shapes))
+	for _, shape := range shapes {
+		if filter.Line != "" && shape.Line != filter.Line {
+			continue
+		}
+		if filter.BBox != nil && !anyInside(filter.BBox, shape.Stops) {
+			continue
+		}
+
+		id := fmt.Sprintf("%s:%d", shape.Line, shape.DirectionID)
+		features = append(features, geojson.NewFeature(id, geojson.NewLineString(shape.Coordinates), map[string]any{
+			"line":      shape.Line,
+			"direction": shape.Direction,
+			"codes":     shape.Codes,
+			"stopCount": len(shape.Codes),
+			"geometry":  shape.Source,
+		}))
+	}
+
+	return geojson.NewFeatureCollection(features), nil
+}
+
+func (s *Service) buildLineShapes(ctx context.Context, geometry string) ([]lineShape, error) {
+	stops, err := s.segmentStore.ListLineStops(ctx, store.ScheduleTypeWeekday)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list line stops: %w", err)
+	}
+
+	shapes := make([]lineShape, 0)
+	for _, stop := range stops {
+		n := len(shapes)
+		if n == 0 || shapes[n-1].Line != stop.Line || shapes[n-1].DirectionID != stop.DirectionID {
+			shapes = append(shapes, lineShape{
+				Line:        stop.Line,
+				DirectionID: stop.DirectionID,
+				Direction:   stop.Direction,
+				Source:      GeometryStops,
+			})
+			n++
+		}
+		shapes[n-1].Codes = append(shapes[n-1].Codes, stop.Code)
+		shapes[n-1].Stops = append(shapes[n-1].Stops, [2]float64{stop.Lon, stop.Lat})
+	}
+
+	for i := range shapes {
+		shapes[i].Coordinates = shapes[i].Stops
+		if geometry != GeometryRouted || len(shapes[i].Stops) < 2 {
+			continue
+		}
+
+		coords, err := s.route(ctx, shapes[i].Stops)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			slog.Warn("routing line shape failed, using stop geometry",
+				slog.String("line", shapes[i].Line),
+				slog.String("direction", shapes[i].Direction),
+				slog.Any("error", err),
+			)
+			continue
+		}
+		shapes[i].Coordinates = coords
+		shapes[i].Source = GeometryRouted
+	}
+
+	return shapes, nil
+}
+
+// route fetches road geometry through all stops in order.
+func (s *Service) route(ctx context.Context, stops [][2]float64) ([][2]float64, error) {
+	var coords [][2]float64
+	for start := 0; start < len(stops)-1; start += maxRouteWaypoints - 1 {
+		end := min(start+maxRouteWaypoints, len(stops))
+
+		points := make([]routing.Point, 0, end-start)
+		for _, stop := range stops[start:end] {
+			points = append(points, routing.Point{Lon: stop[0], Lat: stop[1]})
+		}
+
+		route, err := s.router.Route(ctx, points)
+		if err != nil {
+			return nil, err
+		}
+
+		geometry := route.Geometry
+		// Consecutive chunks share their connecting stop.
+		if len(coords) > 0 && len(geometry) > 0 {
+			geometry = geometry[1:]
+		}
+		coords = append(coords, geometry...)
+	}
+	return coords, nil
+}
+
+func anyInside(bbox *store.BBox, coords [][2]float64) bool {
+	for _, c := range coords {
+		if bbox.Contains(c[1], c[0]) {
+			return true
+		}
+	}
+	return false
+}
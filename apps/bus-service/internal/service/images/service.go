@@ -0,0 +1,466 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/errs"
+	"github.com/perkzen/mbus/apps/bus-service/internal/objectstore"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
+)
+
+// maxPixels rejects images that would take too much memory to decode.
+const maxPixels = 40_000_000
+
+// syncConcurrency bounds the parallel downloads of Sync.
+const syncConcurrency = 4
+
+// syncTimeout bounds a sync started with StartSync. It runs detached from the
+// request that started it, which is cancelled long before all stations are
+// fetched.
+const syncTimeout = 30 * time.Minute
+
+var hashPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// ValidHash reports whether hash has the form of an image content hash.
+func ValidHash(hash string) bool {
+	return hashPattern.MatchString(hash)
+}
+
+// contentTypes are the source formats that are copied.
+var contentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// Image is a stored image or thumbnail. Its bytes never change, so the hash
+// and size identify it.
+type Image struct {
+	Data        []byte
+	ContentType string
+	Hash        string
+	Size        string // empty for the original
+}
+
+// SyncResult counts the outcome of a Sync.
+type SyncResult struct {
+	Stations int `json:"stations"` // stations with a source image
+	Fetched  int `json:"fetched"`
+	Missing  int `json:"missing"` // not an image or gone at the operator
+	Failed   int `json:"failed"`  // to be retried
+	Skipped  int `json:"skipped"` // already stored
+} // @name ImageSyncResult
+
+// SyncStatus describes the running or last sync started with StartSync.
+type SyncStatus struct {
+	Running    bool        `json:"running"`
+	Force      bool        `json:"force"`
+	StartedAt  time.Time   `json:"startedAt"`
+	FinishedAt *time.Time  `json:"finishedAt,omitempty"`
+	Result     *SyncResult `json:"result,omitempty"` // counts so far once finished, also after an error
+	Error      string      `json:"error,omitempty"`
+} // @name ImageSyncStatus
+
+// Service copies station photos from the operator into an object store and
+// serves them with thumbnails. Images are stored under their SHA-256, so
+// every URL that carries the hash can be cached forever.
+type Service struct {
+	objects         objectstore.Store
+	imageStore      store.StationImageStore
+	busStationStore store.BusStationStore
+	client          *http.Client
+	baseURL         *url.URL
+	maxBytes        int64
+	retryAfter      time.Duration
+	group           singleflight.Group
+	logger          *slog.Logger
+
+	syncMu   sync.Mutex
+	lastSync *SyncStatus
+}
+
+type Option func(*Service)
+
+// WithFetchTimeout bounds each download from the operator.
+func WithFetchTimeout(timeout time.Duration) Option {
+	return func(s *Service) {
+		if timeout > 0 {
+			s.client.Timeout = timeout
+		}
+	}
+}
+
+// WithMaxBytes rejects source images larger than n bytes.
+func WithMaxBytes(n int64) Option {
+	return func(s *Service) {
+		if n > 0 {
+			s.maxBytes = n
+		}
+	}
+}
+
+// WithRetryAfter sets how long an image found missing is not fetched again.
+func WithRetryAfter(d time.Duration) Option {
+	return func(s *Service) {
+		s.retryAfter = d
+	}
+}
+
+// WithSourceBaseURL resolves relative source image URLs against base.
+func WithSourceBaseURL(base string) Option {
+	return func(s *Service) {
+		if u, err := url.Parse(base); err == nil && base != "" {
+			s.baseURL = u
+		}
+	}
+}
+
+func NewService(
+	objects objectstore.Store,
+	imageStore store.StationImageStore,
+	busStationStore store.BusStationStore,
+	logger *slog.Logger,
+	opts ...Option,
+) *Service {
+	s := &Service{
+		objects:         objects,
+		imageStore:      imageStore,
+		busStationStore: busStationStore,
+		client: &http.Client{
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+			Timeout:   10 * time.Second,
+		},
+		baseURL:    &url.URL{Scheme: "https", Host: "vozniredi.marprom.si", Path: "/"},
+		maxBytes:   10 << 20,
+		retryAfter: 24 * time.Hour,
+		logger:     logger.With(slog.String("service", "images.Service")),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// StationImageHash returns the content hash of a station's photo, copying it
+// from the operator on first use. Stations without a usable photo are
+// reported as not found; a photo found missing is only tried again after
+// the retry interval.
+func (s *Service) StationImageHash(ctx context.Context, stationID int) (_ string, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "images.StationImageHash", attribute.Int("station.id", stationID))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	station, err := s.busStationStore.FindBusStationByID(ctx, stationID)
+	if err != nil {
+		return "", err
+	}
+	if station == nil {
+		return "", errs.BusStationNotFoundError(stationID)
+	}
+	if station.ImageHash != "" {
+		return station.ImageHash, nil
+	}
+	if station.SourceImageURL == "" {
+		return "", errs.NotFoundError("Bus station with ID %d has no image", stationID)
+	}
+
+	// Concurrent requests for the same station share one download, which
+	// must not be cut short when the first of them goes away.
+	v, err, _ := s.group.Do("station:"+strconv.Itoa(stationID), func() (any, error) {
+		ctx := context.WithoutCancel(ctx)
+		img, err := s.imageStore.FindStationImage(ctx, stationID)
+		if err != nil {
+			return nil, err
+		}
+		if img != nil && img.SourceURL == station.SourceImageURL && img.Hash == "" && time.Since(img.FetchedAt) < s.retryAfter {
+			return img, nil
+		}
+		return s.fetch(ctx, *station)
+	})
+	if err != nil {
+		s.logger.Warn("failed to fetch station image", slog.Int("station", stationID), slog.Any("error", err))
+		return "", errs.BadGatewayError("Image of bus station with ID %d is temporarily unavailable", stationID)
+	}
+
+	img := v.(*store.StationImage)
+	if img.Hash == "" {
+		return "", errs.NotFoundError("Bus station with ID %d has no image", stationID)
+	}
+	return img.Hash, nil
+}
+
+// Image returns the stored image with the given hash, or one of its Sizes.
+// Thumbnails are generated on first request and stored next to the
+// original.
+func (s *Service) Image(ctx context.Context, hash, size string) (_ *Image, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "images.Image",
+		attribute.String("image.hash", hash),
+		attribute.String("image.size", size))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	if size == "" {
+		data, err := s.objects.Get(ctx, originalKey(hash))
+		if errors.Is(err, objectstore.ErrNotExist) {
+			return nil, errs.NotFoundError("Image %s does not exist", hash)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &Image{Data: data, ContentType: http.DetectContentType(data), Hash: hash}, nil
+	}
+
+	width, ok := sizeWidths[size]
+	if !ok {
+		return nil, fmt.Errorf("unknown image size %q", size)
+	}
+
+	key := thumbnailKey(hash, size)
+	data, err := s.objects.Get(ctx, key)
+	if errors.Is(err, objectstore.ErrNotExist) {
+		var v any
+		v, err, _ = s.group.Do(key, func() (any, error) {
+			return s.generate(context.WithoutCancel(ctx), hash, key, width)
+		})
+		data, _ = v.([]byte)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Image{Data: data, ContentType: "image/jpeg", Hash: hash, Size: size}, nil
+}
+
+func (s *Service) generate(ctx context.Context, hash, key string, width int) ([]byte, error) {
+	original, err := s.objects.Get(ctx, originalKey(hash))
+	if errors.Is(err, objectstore.ErrNotExist) {
+		return nil, errs.NotFoundError("Image %s does not exist", hash)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(original))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image %s: %w", hash, err)
+	}
+	data, err := thumbnail(img, width)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail of %s: %w", hash, err)
+	}
+	if err := s.objects.Put(ctx, key, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Sync copies the photos of all stations that are not stored yet, or of all
+// stations when force is set, e.g. after the operator replaced photos. One
+// failing station does not stop the others.
+func (s *Service) Sync(ctx context.Context, force bool) (_ *SyncResult, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "images.Sync", attribute.Bool("images.force", force))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	stations, err := s.busStationStore.ListAllBusStations(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	result := &SyncResult{}
+	g := errgroup.Group{}
+	g.SetLimit(syncConcurrency)
+	for _, station := range stations {
+		if station.SourceImageURL == "" {
+			continue
+		}
+		result.Stations++
+		if station.ImageHash != "" && !force {
+			result.Skipped++
+			continue
+		}
+
+		g.Go(func() error {
+			img, err := s.fetch(ctx, station)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				result.Failed++
+				s.logger.Warn("failed to fetch station image", slog.Int("station", station.ID), slog.Any("error", err))
+			case img.Hash == "":
+				result.Missing++
+			default:
+				result.Fetched++
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return result, ctx.Err()
+}
+
+// StartSync runs Sync in the background with its own deadline and calls done
+// with its outcome, which may be a partial result together with an error.
+// done gets a fresh context, as the sync may have used up its deadline. It
+// reports false with the status of the running sync when one is already
+// running in this process.
+func (s *Service) StartSync(ctx context.Context, force bool, done func(ctx context.Context, result *SyncResult, err error)) (SyncStatus, bool) {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	if s.lastSync != nil && s.lastSync.Running {
+		return *s.lastSync, false
+	}
+
+	status := &SyncStatus{Running: true, Force: force, StartedAt: utils.Now()}
+	s.lastSync = status
+
+	detached := context.WithoutCancel(ctx)
+	go func() {
+		syncCtx, cancel := context.WithTimeout(detached, syncTimeout)
+		result, err := s.Sync(syncCtx, force)
+		cancel()
+
+		doneCtx, cancel := context.WithTimeout(detached, time.Minute)
+		defer cancel()
+		done(doneCtx, result, err)
+
+		s.syncMu.Lock()
+		defer s.syncMu.Unlock()
+		finished := utils.Now()
+		status.Running = false
+		status.FinishedAt = &finished
+		status.Result = result
+		if err != nil {
+			status.Error = err.Error()
+		}
+	}()
+
+	return *status, true
+}
+
+// SyncStatus returns the status of the running or last sync started with
+// StartSync, or false when none was started since the process started.
+func (s *Service) SyncStatus() (SyncStatus, bool) {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	if s.lastSync == nil {
+		return SyncStatus{}, false
+	}
+	return *s.lastSync, true
+}
+
+// fetch copies the photo of station into the object store and records the
+// outcome. A photo the operator does not have or that is not a usable image
+// is recorded as missing; network and server errors are returned without a
+// record, so the next request tries again.
+func (s *Service) fetch(ctx context.Context, station store.BusStation) (*store.StationImage, error) {
+	source, err := s.baseURL.Parse(station.SourceImageURL)
+	if err != nil {
+		return s.missing(ctx, station, "invalid source URL")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusGone, resp.StatusCode == http.StatusForbidden:
+		return s.missing(ctx, station, resp.Status)
+	default:
+		return nil, fmt.Errorf("fetching %s: %s", source, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, s.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxBytes {
+		return s.missing(ctx, station, "larger than "+strconv.FormatInt(s.maxBytes, 10)+" bytes")
+	}
+
+	contentType := http.DetectContentType(data)
+	if !contentTypes[contentType] {
+		return s.missing(ctx, station, "unsupported content type "+contentType)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return s.missing(ctx, station, err.Error())
+	}
+	if config.Width*config.Height > maxPixels {
+		return s.missing(ctx, station, "too many pixels")
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	if err := s.objects.Put(ctx, originalKey(hash), data); err != nil {
+		return nil, err
+	}
+
+	img := &store.StationImage{
+		StationID:   station.ID,
+		SourceURL:   station.SourceImageURL,
+		Hash:        hash,
+		ContentType: contentType,
+		Width:       config.Width,
+		Height:      config.Height,
+	}
+	if err := s.imageStore.UpsertStationImage(ctx, img); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+func (s *Service) missing(ctx context.Context, station store.BusStation, reason string) (*store.StationImage, error) {
+	s.logger.Info("station image is missing",
+		slog.Int("station", station.ID),
+		slog.String("source", station.SourceImageURL),
+		slog.String("reason", reason))
+
+	img := &store.StationImage{StationID: station.ID, SourceURL: station.SourceImageURL}
+	if err := s.imageStore.UpsertStationImage(ctx, img); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// Images are sharded by the first two hex digits of their hash so no
+// directory grows too large.
+func originalKey(hash string) string {
+	return "images/" + hash[:2] + "/" + hash + "/original"
+}
+
+func thumbnailKey(hash, size string) string {
+	return "images/" + hash[:2] + "/" + hash + "/" + size + ".jpg"
+}
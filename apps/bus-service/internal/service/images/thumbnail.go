@@ -0,0 +1,88 @@
+package images
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+)
+
+// Thumbnail sizes, by the width in pixels they are scaled down to.
+const (
+	SizeThumb  = "thumb"
+	SizeSmall  = "small"
+	SizeMedium = "medium"
+)
+
+// Sizes lists the thumbnail sizes from small to large.
+var Sizes = []string{SizeThumb, SizeSmall, SizeMedium}
+
+var sizeWidths = map[string]int{
+	SizeThumb:  160,
+	SizeSmall:  320,
+	SizeMedium: 640,
+}
+
+const thumbnailQuality = 82
+
+// thumbnail scales img down to width, keeping its aspect ratio, and encodes
+// it as JPEG. Smaller images keep their size. Transparent areas become white.
+func thumbnail(img image.Image, width int) ([]byte, error) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > width {
+		h = max(1, h*width/w)
+		w = width
+	}
+
+	src := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, downscale(src, w, h), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// downscale resizes src to w×h by averaging the source pixels covered by
+// each target pixel, which avoids the aliasing of nearest-neighbour sampling
+// when shrinking photos.
+func downscale(src *image.RGBA, w, h int) *image.RGBA {
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	if sw == w && sh == h {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0 := y * sh / h
+		y1 := max((y+1)*sh/h, y0+1)
+		for x := 0; x < w; x++ {
+			x0 := x * sw / w
+			x1 := max((x+1)*sw/w, x0+1)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += int(src.Pix[i])
+					g += int(src.Pix[i+1])
+					b += int(src.Pix[i+2])
+					a += int(src.Pix[i+3])
+					i += 4
+					n++
+				}
+			}
+
+			j := dst.PixOffset(x, y)
+			dst.Pix[j] = uint8(r / n)
+			dst.Pix[j+1] = uint8(g / n)
+			dst.Pix[j+2] = uint8(b / n)
+			dst.Pix[j+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,172 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/notify"
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/departure"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+// Timetables generates the departures of a trip, e.g. *departure.Service.
+type Timetables interface {
+	GenerateTimetable(ctx context.Context, fromID, toID int, date string, filter departure.Filter) ([]departure.TimetableRow, error)
+}
+
+// Scheduler reminds subscribers of their departures. Every interval it
+// evaluates the subscriptions of the current service day and notifies each
+// departure inside a subscription's window once it is at most LeadMinutes
+// away. Reminders are claimed in the database first, so several instances
+// can run side by side.
+type Scheduler struct {
+	subscriptionStore store.SubscriptionStore
+	timetables        Timetables
+	notifiers         notify.Notifiers
+	interval          time.Duration
+	logger            *slog.Logger
+}
+
+func NewScheduler(
+	subscriptionStore store.SubscriptionStore,
+	timetables Timetables,
+	notifiers notify.Notifiers,
+	interval time.Duration,
+	logger *slog.Logger,
+) *Scheduler {
+	return &Scheduler{
+		subscriptionStore: subscriptionStore,
+		timetables:        timetables,
+		notifiers:         notifiers,
+		interval:          interval,
+		logger:            logger.With(slog.String("service", "reminder.Scheduler")),
+	}
+}
+
+// Run evaluates the subscriptions every interval until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Tick(ctx); err != nil {
+			s.logger.Error("failed to evaluate subscriptions", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick sends the reminders due now. A failing subscription is logged and
+// does not hold up the others.
+func (s *Scheduler) Tick(ctx context.Context) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "reminder.Tick")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	date := utils.ServiceDate()
+	now := utils.NowServiceTime()
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return err
+	}
+
+	subscriptions, err := s.subscriptionStore.ListSubscriptionsForWeekday(ctx, store.WeekdayName(day.Weekday()))
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subscriptions {
+		if err := s.remind(ctx, sub, date, now); err != nil {
+			s.logger.Error("failed to send reminders",
+				slog.Int("subscription", sub.ID),
+				slog.String("channel", sub.Channel),
+				slog.Any("error", err))
+		}
+	}
+	return nil
+}
+
+// remind notifies the departures of sub leaving between now and LeadMinutes
+// from now.
+func (s *Scheduler) remind(ctx context.Context, sub store.Subscription, date string, now utils.ServiceTime) error {
+	after := max(sub.WindowStart, now)
+	before := min(sub.WindowEnd, now+utils.ServiceTime(sub.LeadMinutes))
+	if after > before {
+		return nil
+	}
+
+	// The whole day is requested, as it is served from the timetable cache,
+	// and the window is applied here.
+	rows, err := s.timetables.GenerateTimetable(ctx, sub.FromStationID, sub.ToStationID, date, departure.Filter{
+		Lines: sub.Lines,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if at := row.GetDepartureAt(); at < after || at > before {
+			continue
+		}
+
+		claimed, err := s.subscriptionStore.ClaimReminder(ctx, sub.ID, date, row.ID)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.send(ctx, sub, date, row); err != nil {
+			// Let the next tick retry while the departure is still ahead.
+			if releaseErr := s.subscriptionStore.ReleaseReminder(ctx, sub.ID, date, row.ID); releaseErr != nil {
+				s.logger.Error("failed to release reminder", slog.Int("subscription", sub.ID), slog.Any("error", releaseErr))
+			}
+			return err
+		}
+		s.logger.Info("reminder sent",
+			slog.Int("subscription", sub.ID),
+			slog.Int("departure", row.ID),
+			slog.String("channel", sub.Channel))
+	}
+	return nil
+}
+
+func (s *Scheduler) send(ctx context.Context, sub store.Subscription, date string, row departure.TimetableRow) error {
+	departureAt, err := utils.ServiceDateTime(date, row.GetDepartureAt())
+	if err != nil {
+		return fmt.Errorf("failed to resolve departure time of %d: %w", row.ID, err)
+	}
+	arriveAt, err := utils.ServiceDateTime(date, row.GetArriveAt())
+	if err != nil {
+		return fmt.Errorf("failed to resolve arrival time of %d: %w", row.ID, err)
+	}
+
+	lang, ok := i18n.Parse(sub.Lang)
+	if !ok {
+		lang = i18n.Default
+	}
+
+	return s.notifiers.Notify(ctx, sub.Channel, sub.Target, notify.Reminder{
+		SubscriptionID: sub.ID,
+		Name:           sub.Name,
+		Lang:           lang,
+		Date:           date,
+		DepartureID:    row.ID,
+		Line:           row.Line,
+		Direction:      row.Direction,
+		From:           row.FromStation.Name,
+		To:             row.ToStation.Name,
+		DepartureAt:    departureAt,
+		ArriveAt:       arriveAt,
+	})
+}
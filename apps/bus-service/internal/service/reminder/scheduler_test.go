@@ -0,0 +1,204 @@
+package reminder_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/notify"
+	"github.com/perkzen/mbus/apps/bus-service/internal/notify/sink"
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/departure"
+	"github.com/perkzen/mbus/apps/bus-service/internal/service/reminder"
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+// memorySubscriptions keeps subscriptions and claimed reminders in memory.
+type memorySubscriptions struct {
+	store.SubscriptionStore
+
+	mu            sync.Mutex
+	subscriptions []store.Subscription
+	claimed       map[[3]any]bool
+	released      int
+}
+
+func (m *memorySubscriptions) ListSubscriptionsForWeekday(context.Context, string) ([]store.Subscription, error) {
+	return m.subscriptions, nil
+}
+
+func (m *memorySubscriptions) ClaimReminder(_ context.Context, id int, date string, departureID int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := [3]any{id, date, departureID}
+	if m.claimed[key] {
+		return false, nil
+	}
+	m.claimed[key] = true
+	return true, nil
+}
+
+func (m *memorySubscriptions) ReleaseReminder(_ context.Context, id int, date string, departureID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.claimed, [3]any{id, date, departureID})
+	m.released++
+	return nil
+}
+
+// fixedTimetable returns the same rows for every trip and records the
+// filters it was asked for.
+type fixedTimetable struct {
+	rows    []departure.TimetableRow
+	filters []departure.Filter
+}
+
+func (f *fixedTimetable) GenerateTimetable(_ context.Context, _, _ int, _ string, filter departure.Filter) ([]departure.TimetableRow, error) {
+	f.filters = append(f.filters, filter)
+	return f.rows, nil
+}
+
+// subscription covers the whole service day, so Tick considers it at any
+// time the test runs.
+func subscription(target string) store.Subscription {
+	return store.Subscription{
+		ID:            1,
+		Name:          "To work",
+		FromStationID: 10,
+		ToStationID:   20,
+		Lines:         []string{"G6"},
+		WindowStart:   0,
+		WindowEnd:     2 * utils.MinutesPerDay,
+		LeadMinutes:   10,
+		Channel:       notify.ChannelWebhook,
+		Target:        target,
+		Lang:          "en",
+	}
+}
+
+// timetableRow departs at the given service time and arrives 17 minutes
+// later.
+func timetableRow(id int, at utils.ServiceTime) departure.TimetableRow {
+	arrive := at + 17
+	return departure.TimetableRow{
+		ID:                 id,
+		Line:               "G6",
+		Direction:          "Tezno",
+		FromStation:        departure.Station{Name: "Glavni trg"},
+		ToStation:          departure.Station{Name: "Tezno"},
+		DepartureAt:        at.Clock(),
+		DepartureDayOffset: at.DayOffset(),
+		ArriveAt:           arrive.Clock(),
+		ArriveDayOffset:    arrive.DayOffset(),
+	}
+}
+
+func newScheduler(subs *memorySubscriptions, timetable *fixedTimetable) *reminder.Scheduler {
+	notifiers := notify.Notifiers{
+		notify.ChannelWebhook: notify.NewWebhookNotifier(notify.WithPrivateTargets()),
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return reminder.NewScheduler(subs, timetable, notifiers, time.Minute, logger)
+}
+
+func TestTickSendsEachReminderOnce(t *testing.T) {
+	var mu sync.Mutex
+	var received []notify.Reminder
+	server := httptest.NewServer(sink.NewWebhookReceiver("", func(w sink.Webhook) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, w.Reminder)
+	}))
+	defer server.Close()
+
+	subs := &memorySubscriptions{
+		subscriptions: []store.Subscription{subscription(server.URL)},
+		claimed:       make(map[[3]any]bool),
+	}
+	// Two departures within the 10 minute lead, one after it.
+	now := utils.NowServiceTime()
+	timetable := &fixedTimetable{rows: []departure.TimetableRow{
+		timetableRow(100, now+4),
+		timetableRow(101, now+5),
+		timetableRow(102, now+30),
+	}}
+	s := newScheduler(subs, timetable)
+
+	for range 2 {
+		if err := s.Tick(context.Background()); err != nil {
+			t.Fatalf("Tick() error = %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 {
+		t.Fatalf("received %d reminders over two ticks, want 2", len(received))
+	}
+	for i, r := range received {
+		if r.SubscriptionID != 1 || r.DepartureID != 100+i || r.Line != "G6" || r.From != "Glavni trg" {
+			t.Errorf("reminder %d = %+v", i, r)
+		}
+		if want := timetable.rows[i].DepartureAt; r.Date != utils.ServiceDate() || r.DepartureAt.Format("15:04") != want {
+			t.Errorf("reminder %d departs at %s on %s, want %s", i, r.DepartureAt, r.Date, want)
+		}
+	}
+
+	// The day timetable is requested, so it is served from the cache.
+	f := timetable.filters[0]
+	if f.After != nil || f.Before != nil || f.Limit != 0 {
+		t.Errorf("timetable window = %v..%v limit %d, want the whole day", f.After, f.Before, f.Limit)
+	}
+	if len(f.Lines) != 1 || f.Lines[0] != "G6" {
+		t.Errorf("timetable lines = %v, want [G6]", f.Lines)
+	}
+}
+
+func TestTickReleasesReminderWhenDeliveryFails(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+
+	delivered := make(chan notify.Reminder, 1)
+	receiver := sink.NewWebhookReceiver("", func(w sink.Webhook) { delivered <- w.Reminder })
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		receiver.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	subs := &memorySubscriptions{
+		subscriptions: []store.Subscription{subscription(server.URL)},
+		claimed:       make(map[[3]any]bool),
+	}
+	s := newScheduler(subs, &fixedTimetable{rows: []departure.TimetableRow{timetableRow(100, utils.NowServiceTime()+5)}})
+
+	if err := s.Tick(context.Background()); err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+	if subs.released != 1 || len(subs.claimed) != 0 {
+		t.Fatalf("released %d reminders, %d still claimed; want the failed one released", subs.released, len(subs.claimed))
+	}
+
+	failing.Store(false)
+
+	if err := s.Tick(context.Background()); err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+	select {
+	case r := <-delivered:
+		if r.DepartureID != 100 {
+			t.Errorf("retried reminder is for departure %d, want 100", r.DepartureID)
+		}
+	default:
+		t.Fatal("failed reminder was not retried on the next tick")
+	}
+}
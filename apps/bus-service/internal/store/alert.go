@@ -0,0 +1,157 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+// Alert is a service notice such as a detour or a stop closure. Title and
+// Body are the Slovenian source text when written and the localized text when
+// listed. An alert without Lines applies to the whole network.
+type Alert struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title" example:"Zapora Glavnega trga"`
+	Body        string     `json:"body"`
+	Lines       []string   `json:"lines"`
+	ActiveFrom  time.Time  `json:"activeFrom"`
+	ActiveUntil *time.Time `json:"activeUntil,omitempty"`
+} // @name Alert
+
+type AlertFilterOptions struct {
+	// Line limits the alerts to those affecting the line, including alerts
+	// for the whole network.
+	Line string
+	// At selects the alerts active at that instant.
+	At   time.Time
+	Lang i18n.Lang
+}
+
+type AlertStore interface {
+	ListAlerts(ctx context.Context, filter *AlertFilterOptions) ([]Alert, error)
+	CreateAlert(ctx context.Context, a *Alert) error
+	DeleteAlert(ctx context.Context, id int) (bool, error)
+}
+
+type PostgresAlertStore struct {
+	db *sql.DB
+}
+
+func NewPostgresAlertStore(db *sql.DB) *PostgresAlertStore {
+	return &PostgresAlertStore{db: db}
+}
+
+// ListAlerts returns the matching alerts, newest first.
+func (store *PostgresAlertStore) ListAlerts(ctx context.Context, filter *AlertFilterOptions) (_ []Alert, err error) {
+	ctx, span := startSpan(ctx, "ListAlerts")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	q := Qb.Select("a.id").
+		Column(translatedOr(EntityAlert, "title", "a.id", "a.title", filter.Lang)).
+		Column(translatedOr(EntityAlert, "body", "a.id", "a.body", filter.Lang)).
+		Columns("a.lines", "a.active_from", "a.active_until").
+		From("alerts a").
+		Where("a.active_from <= ?", filter.At).
+		Where("(a.active_until IS NULL OR a.active_until > ?)", filter.At).
+		OrderBy("a.active_from DESC", "a.id DESC")
+
+	if filter.Line != "" {
+		q = q.Where("(cardinality(a.lines) = 0 OR ? = ANY(a.lines))", filter.Line)
+	}
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := make([]Alert, 0)
+	for rows.Next() {
+		var a Alert
+		var lines pq.StringArray
+		var until sql.NullTime
+		if err := rows.Scan(&a.ID, &a.Title, &a.Body, &lines, &a.ActiveFrom, &until); err != nil {
+			return nil, err
+		}
+		a.Lines = lines
+		a.ActiveFrom = utils.InLocation(a.ActiveFrom)
+		if until.Valid {
+			t := utils.InLocation(until.Time)
+			a.ActiveUntil = &t
+		}
+		alerts = append(alerts, a)
+	}
+
+	return alerts, rows.Err()
+}
+
+// CreateAlert inserts a and sets its ID.
+func (store *PostgresAlertStore) CreateAlert(ctx context.Context, a *Alert) (err error) {
+	ctx, span := startSpan(ctx, "CreateAlert")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	query, args, err := Qb.Insert("alerts").
+		Columns("title", "body", "lines", "active_from", "active_until").
+		Values(a.Title, a.Body, pq.StringArray(a.Lines), a.ActiveFrom, a.ActiveUntil).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	traceQuery(span, query)
+	return store.db.QueryRowContext(ctx, query, args...).Scan(&a.ID)
+}
+
+// DeleteAlert removes an alert together with its translations.
+func (store *PostgresAlertStore) DeleteAlert(ctx context.Context, id int) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "DeleteAlert")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query, args, err := Qb.Delete("translations").
+		Where(sq.Eq{"entity_type": EntityAlert, "entity_id": id}).
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+	traceQuery(span, query)
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return false, err
+	}
+
+	query, args, err = Qb.Delete("alerts").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+	traceQuery(span, query)
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, tx.Commit()
+}
@@ -1,18 +1,136 @@
 package store
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
+
 	sq "github.com/Masterminds/squirrel"
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/pagination"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
 )
 
-type BusLine struct {
+// Line categories. G-lines run in the city, P-lines to the suburbs.
+const (
+	LineCategoryCity     = "city"
+	LineCategorySuburban = "suburban"
+	LineCategoryOther    = "other"
+)
+
+// LineCategories lists the categories a line can have.
+var LineCategories = []string{LineCategoryCity, LineCategorySuburban, LineCategoryOther}
+
+// LineMetadata describes how a line is presented. The seeder derives it from
+// the timetables; values set by an admin take precedence.
+type LineMetadata struct {
+	Category string `json:"category" enums:"city,suburban,other"`
+	// Color is the #RRGGBB background of the line badge and TextColor the
+	// colour legible on it.
+	Color     string `json:"color,omitempty"`
+	TextColor string `json:"textColor,omitempty"`
+	// LongName names the termini, e.g. "Avtobusna postaja - Tezno".
+	LongName string `json:"longName,omitempty"`
+	Operator string `json:"operator,omitempty"`
+	// SortOrder ranks lines in lists; lines of equal rank keep their
+	// natural order.
+	SortOrder int `json:"sortOrder"`
+}
+
+// LineOverrides are the metadata set by an admin. Nil fields keep the value
+// derived by the seeder.
+type LineOverrides struct {
+	Category  *string `json:"category" enums:"city,suburban,other"`
+	Color     *string `json:"color"`
+	TextColor *string `json:"textColor"`
+	LongName  *string `json:"longName"`
+	Operator  *string `json:"operator"`
+	SortOrder *int    `json:"sortOrder"`
+} // @name LineOverrides
+
+// LineRef is a line serving a station or a timetable row, with its metadata.
+type LineRef struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
+	LineMetadata
+} // @name LineRef
+
+type BusLine struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	LineMetadata
+	// Distance in kilometres from the requested origin to the closest station
+	// of the line, if an origin was given.
+	Distance *float64 `json:"distance,omitempty"`
 } // @name BusLine
 
+type BusLineFilterOptions struct {
+	Name      string
+	StationID int
+	Origin    *Point    // reference point for distances
+	Lang      i18n.Lang // language of descriptions
+}
+
+// lineOverridesJoin joins the admin overrides blo of the bus_lines row bl.
+const lineOverridesJoin = "bus_line_overrides blo ON blo.bus_line_id = bl.id"
+
+// lineOrder sorts bus_lines rows bl by their sort order and then naturally:
+// G1, G2, ..., P7, ..., P19.
+const lineOrder = "COALESCE(blo.sort_order, bl.sort_order), lpad(regexp_replace(bl.name, '[^0-9]', '', 'g'), 4, '0') || bl.name"
+
+// lineMetadataColumns select the metadata of the bus_lines row bl with the
+// overrides of lineOverridesJoin, as read by LineMetadata.scanDest.
+func lineMetadataColumns() []string {
+	return []string{
+		"COALESCE(blo.category, bl.category)",
+		"COALESCE(blo.color, bl.color, '')",
+		"COALESCE(blo.text_color, bl.text_color, '')",
+		"COALESCE(blo.long_name, bl.long_name, '')",
+		"COALESCE(blo.operator, bl.operator, '')",
+		"COALESCE(blo.sort_order, bl.sort_order)",
+	}
+}
+
+func (m *LineMetadata) scanDest() []any {
+	return []any{&m.Category, &m.Color, &m.TextColor, &m.LongName, &m.Operator, &m.SortOrder}
+}
+
+// stationLinesColumn selects the lines serving the station row bs as a JSON
+// array of LineRef in line order, as read by setLineDetails.
+func stationLinesColumn() string {
+	return `COALESCE((
+		SELECT json_agg(json_build_object(
+			'id', bl.id,
+			'name', bl.name,
+			'category', COALESCE(blo.category, bl.category),
+			'color', COALESCE(blo.color, bl.color),
+			'textColor', COALESCE(blo.text_color, bl.text_color),
+			'longName', COALESCE(blo.long_name, bl.long_name),
+			'operator', COALESCE(blo.operator, bl.operator),
+			'sortOrder', COALESCE(blo.sort_order, bl.sort_order)
+		) ORDER BY ` + lineOrder + `)
+		FROM bus_stations_bus_lines sl
+		JOIN bus_lines bl ON bl.id = sl.bus_line_id
+		LEFT JOIN ` + lineOverridesJoin + `
+		WHERE sl.bus_station_id = bs.id
+	), '[]') AS line_details`
+}
+
+func (s *BusStation) setLineDetails(raw []byte) error {
+	if err := json.Unmarshal(raw, &s.LineDetails); err != nil {
+		return fmt.Errorf("invalid lines of station %d: %w", s.ID, err)
+	}
+	return nil
+}
+
 type BusLineStore interface {
-	ListBusLines() ([]BusLine, error)
-	FindSharedLinesByStations(fromId, toId int) ([]BusLine, error)
+	ListBusLines(ctx context.Context, opts *BusLineFilterOptions, page pagination.Request) (*pagination.Page[BusLine], error)
+	FindSharedLinesByStations(ctx context.Context, fromId, toId int) ([]BusLine, error)
+	FindBusLinesByIDs(ctx context.Context, ids []int, lang i18n.Lang) ([]BusLine, error)
+	FindBusLinesByStationIDs(ctx context.Context, stationIDs []int, lang i18n.Lang) (map[int][]BusLine, error)
+	UpsertLineOverrides(ctx context.Context, lineID int, o LineOverrides) error
 }
 
 type PostgresBusLinesStore struct {
@@ -25,39 +143,119 @@ func NewPostgresBusLineStore(db *sql.DB) *PostgresBusLinesStore {
 	}
 }
 
-func (store *PostgresBusLinesStore) ListBusLines() ([]BusLine, error) {
-	queryBuilder := Qb.Select("id", "name").
-		From("bus_lines").
-		OrderBy("regexp_replace(name, '[^0-9]', '', 'g')::int")
+func (store *PostgresBusLinesStore) ListBusLines(ctx context.Context, opts *BusLineFilterOptions, page pagination.Request) (_ *pagination.Page[BusLine], err error) {
+	ctx, span := startSpan(ctx, "ListBusLines")
+	defer func() { telemetry.EndSpan(span, err) }()
 
-	query, args, err := queryBuilder.ToSql()
+	// The distance of a line is that of its closest station.
+	distance := sq.Sqlizer(sq.Expr("NULL::double precision"))
+	if opts != nil && opts.Origin != nil {
+		distance = sq.Expr("MIN(?)", distanceKm(*opts.Origin, "bs.lat", "bs.lng"))
+	}
+
+	var sortKey sq.Sqlizer
+	var keyType string
+	switch page.Sort.Field {
+	case pagination.SortDistance:
+		if opts == nil || opts.Origin == nil {
+			return nil, ErrOriginRequired
+		}
+		sortKey, keyType = distance, "double precision"
+	case pagination.SortStationCount:
+		sortKey, keyType = sq.Expr("COUNT(DISTINCT bsl.bus_station_id)"), "bigint"
+	default:
+		// Sort order, then natural order: G1, G2, ..., P7, ..., P19.
+		sortKey, keyType = sq.Expr("lpad(COALESCE(blo.sort_order, bl.sort_order)::text, 10, '0') || lpad(regexp_replace(bl.name, '[^0-9]', '', 'g'), 4, '0') || bl.name"), "text"
+	}
+
+	lang := i18n.Default
+	if opts != nil && opts.Lang != "" {
+		lang = opts.Lang
+	}
+
+	builder := Qb.Select("bl.id AS id", "bl.name").
+		Column(sq.Alias(translated(EntityBusLine, "description", "bl.id", lang), "description")).
+		Columns(lineMetadataColumns()...).
+		Column(sq.Alias(distance, "distance")).
+		Column(sq.Alias(sortKey, pagination.KeyColumn)).
+		From("bus_lines bl").
+		LeftJoin(lineOverridesJoin).
+		LeftJoin("bus_stations_bus_lines bsl ON bsl.bus_line_id = bl.id").
+		LeftJoin("bus_stations bs ON bs.id = bsl.bus_station_id").
+		GroupBy("bl.id", "blo.bus_line_id")
+
+	if opts != nil {
+		if opts.Name != "" {
+			builder = builder.Where(sq.ILike{"bl.name": opts.Name + "%"})
+		}
+		if opts.StationID != 0 {
+			builder = builder.Where(`
+				EXISTS (
+					SELECT 1 FROM bus_stations_bus_lines f
+					WHERE f.bus_line_id = bl.id AND f.bus_station_id = ?
+				)
+			`, opts.StationID)
+		}
+	}
+
+	query, args, err := pagination.Query(Qb, builder, page, keyType).ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := store.db.Query(query, args...)
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var lines []BusLine
+	lines := make([]BusLine, 0)
+	var keys []string
 	for rows.Next() {
 		var line BusLine
-		if err := rows.Scan(&line.ID, &line.Name); err != nil {
+		var dist sql.NullFloat64
+		var key string
+		dest := append([]any{&line.ID, &line.Name, &line.Description}, line.scanDest()...)
+		if err := rows.Scan(append(dest, &dist, &key)...); err != nil {
 			return nil, err
 		}
+		if dist.Valid {
+			line.Distance = &dist.Float64
+		}
 		lines = append(lines, line)
+		keys = append(keys, key)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return lines, nil
+	result := pagination.NewPage(lines, page, func(i int) (string, int) {
+		return keys[i], lines[i].ID
+	})
+
+	if page.WithTotal {
+		query, args, err := pagination.CountQuery(Qb, builder).ToSql()
+		if err != nil {
+			return nil, err
+		}
+
+		traceQuery(span, query)
+		var total int
+		if err := store.db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+			return nil, err
+		}
+		result.Total = &total
+	}
+
+	return result, nil
 }
 
-func (store *PostgresBusLinesStore) FindSharedLinesByStations(fromId, toId int) ([]BusLine, error) {
+func (store *PostgresBusLinesStore) FindSharedLinesByStations(ctx context.Context, fromId, toId int) (_ []BusLine, err error) {
+	ctx, span := startSpan(ctx, "FindSharedLinesByStations")
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	queryBuilder := Qb.Select("bl.id", "bl.name").
 		From("bus_lines bl").
 		Join("bus_stations_bus_lines bsl1 ON bsl1.bus_line_id = bl.id").
@@ -73,7 +271,8 @@ func (store *PostgresBusLinesStore) FindSharedLinesByStations(fromId, toId int)
 		return nil, err
 	}
 
-	rows, err := store.db.Query(query, args...)
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -95,3 +294,107 @@ func (store *PostgresBusLinesStore) FindSharedLinesByStations(fromId, toId int)
 
 	return lines, nil
 }
+
+// FindBusLinesByIDs returns the lines with the given ids, with descriptions in
+// lang. Unknown ids are skipped.
+func (store *PostgresBusLinesStore) FindBusLinesByIDs(ctx context.Context, ids []int, lang i18n.Lang) (_ []BusLine, err error) {
+	ctx, span := startSpan(ctx, "FindBusLinesByIDs")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	queryBuilder := Qb.Select("bl.id", "bl.name").
+		Column(translated(EntityBusLine, "description", "bl.id", lang)).
+		Columns(lineMetadataColumns()...).
+		From("bus_lines bl").
+		LeftJoin(lineOverridesJoin).
+		Where(sq.Eq{"bl.id": ids})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := make([]BusLine, 0, len(ids))
+	for rows.Next() {
+		var line BusLine
+		if err := rows.Scan(append([]any{&line.ID, &line.Name, &line.Description}, line.scanDest()...)...); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+// FindBusLinesByStationIDs returns the lines serving each of the given
+// stations, with descriptions in lang.
+func (store *PostgresBusLinesStore) FindBusLinesByStationIDs(ctx context.Context, stationIDs []int, lang i18n.Lang) (_ map[int][]BusLine, err error) {
+	ctx, span := startSpan(ctx, "FindBusLinesByStationIDs")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	queryBuilder := Qb.Select("bsl.bus_station_id", "bl.id", "bl.name").
+		Column(translated(EntityBusLine, "description", "bl.id", lang)).
+		Columns(lineMetadataColumns()...).
+		From("bus_stations_bus_lines bsl").
+		Join("bus_lines bl ON bl.id = bsl.bus_line_id").
+		LeftJoin(lineOverridesJoin).
+		Where(sq.Eq{"bsl.bus_station_id": stationIDs}).
+		OrderBy("bsl.bus_station_id", lineOrder)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := make(map[int][]BusLine, len(stationIDs))
+	for rows.Next() {
+		var stationID int
+		var line BusLine
+		dest := append([]any{&stationID, &line.ID, &line.Name, &line.Description}, line.scanDest()...)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		lines[stationID] = append(lines[stationID], line)
+	}
+
+	return lines, rows.Err()
+}
+
+// UpsertLineOverrides replaces the metadata an admin set for a line.
+func (store *PostgresBusLinesStore) UpsertLineOverrides(ctx context.Context, lineID int, o LineOverrides) (err error) {
+	ctx, span := startSpan(ctx, "UpsertLineOverrides")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	query, args, err := Qb.Insert("bus_line_overrides").
+		Columns("bus_line_id", "category", "color", "text_color", "long_name", "operator", "sort_order").
+		Values(lineID, o.Category, o.Color, o.TextColor, o.LongName, o.Operator, o.SortOrder).
+		Suffix(`ON CONFLICT (bus_line_id) DO UPDATE SET
+			category = EXCLUDED.category,
+			color = EXCLUDED.color,
+			text_color = EXCLUDED.text_color,
+			long_name = EXCLUDED.long_name,
+			operator = EXCLUDED.operator,
+			sort_order = EXCLUDED.sort_order,
+			updated_at = CURRENT_TIMESTAMP`).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	traceQuery(span, query)
+	_, err = store.db.ExecContext(ctx, query, args...)
+	return err
+}
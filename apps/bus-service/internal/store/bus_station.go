@@ -1,44 +1,86 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/lib/pq"
 	_ "github.com/lib/pq"
+	"github.com/perkzen/mbus/apps/bus-service/internal/pagination"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
 	"strings"
 )
 
 type BusStation struct {
-	ID       int      `json:"id"`
-	Name     string   `json:"name"`
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// ImageURL is our copy of the station photo; append ?size= for a
+	// thumbnail. It is empty when the station has no usable photo.
 	ImageURL string   `json:"imageUrl"`
 	Lat      float64  `json:"lat"`
 	Lon      float64  `json:"lon"`
 	Codes    []int    `json:"codes,omitempty"`
 	Lines    []string `json:"lines,omitempty"`
+	// LineDetails are the lines serving the station with their metadata, in
+	// line order.
+	LineDetails []LineRef `json:"lineDetails,omitempty"`
+	// SourceImageURL is the photo on the operator's server.
+	SourceImageURL string `json:"-"`
+	// ImageHash is the content hash of the stored photo, once fetched.
+	ImageHash string `json:"-"`
+	// Attributes describe accessibility and amenities; null fields are
+	// unknown.
+	Attributes StationAttributes `json:"attributes"`
+	// Distance in kilometres from the requested origin, if one was given.
+	Distance *float64 `json:"distance,omitempty"`
 } // @name BusStation
 
 func (s *BusStation) SanitizedName() string {
 	return strings.ReplaceAll(s.Name, "- ", "")
 }
 
+// StationCode is one stop of a station, e.g. one side of the road, with the
+// code printed on its sign.
 type StationCode struct {
 	ID        int `json:"id"`
 	StationID int `json:"stationId"`
 	Code      int `json:"code"`
-}
+	// Platform letters the stops of a station in code order.
+	Platform string  `json:"platform"`
+	Lat      float64 `json:"lat"`
+	Lon      float64 `json:"lon"`
+} // @name StationCode
+
+// Stop is a station code with the lines and directions departing from it.
+type Stop struct {
+	StationCode
+	Lines      []string `json:"lines"`
+	Directions []string `json:"directions"`
+} // @name Stop
 
 type BusStationFilterOptions struct {
-	Name string
-	Line string
+	Name       string
+	Line       string
+	BBox       *BBox
+	Origin     *Point   // reference point for distances
+	Attributes []string // StationAttributeNames that must be true
 }
 
+// ErrOriginRequired is returned when sorting by distance without an origin.
+var ErrOriginRequired = errors.New("sorting by distance requires an origin")
+
 type BusStationStore interface {
-	ListBusStations(limit, offset int, opts *BusStationFilterOptions) ([]BusStation, error)
-	FindBusStationByID(id int) (*BusStation, error)
-	FindBusStationIDByCode(code string) (*StationCode, error)
+	ListBusStations(ctx context.Context, opts *BusStationFilterOptions, page pagination.Request) (*pagination.Page[BusStation], error)
+	FindBusStationByID(ctx context.Context, id int) (*BusStation, error)
+	FindBusStationIDByCode(ctx context.Context, code string) (*StationCode, error)
+	FindBusStationIDsByLine(ctx context.Context, line string) ([]int, error)
+	ListAllBusStations(ctx context.Context, opts *BusStationFilterOptions) ([]BusStation, error)
+	FindBusStationsByIDs(ctx context.Context, ids []int) ([]BusStation, error)
+	FindStationCodesByStationIDs(ctx context.Context, stationIDs []int) ([]StationCode, error)
+	FindStopsByStationID(ctx context.Context, stationID int) ([]Stop, error)
+	FindBusStationIDsByLineIDs(ctx context.Context, lineIDs []int) (map[int][]int, error)
 }
 
 type PostgresBusStationStore struct {
@@ -51,22 +93,48 @@ func NewPostgresBusStationStore(db *sql.DB) *PostgresBusStationStore {
 	}
 }
 
-func (store *PostgresBusStationStore) ListBusStations(limit, offset int, opts *BusStationFilterOptions) ([]BusStation, error) {
+func (store *PostgresBusStationStore) ListBusStations(ctx context.Context, opts *BusStationFilterOptions, page pagination.Request) (_ *pagination.Page[BusStation], err error) {
+	ctx, span := startSpan(ctx, "ListBusStations")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	distance := sq.Sqlizer(sq.Expr("NULL::double precision"))
+	if opts != nil && opts.Origin != nil {
+		distance = distanceKm(*opts.Origin, "bs.lat", "bs.lng")
+	}
+
+	var sortKey sq.Sqlizer
+	var keyType string
+	switch page.Sort.Field {
+	case pagination.SortDistance:
+		if opts == nil || opts.Origin == nil {
+			return nil, ErrOriginRequired
+		}
+		sortKey, keyType = distance, "double precision"
+	case pagination.SortLineCount:
+		sortKey, keyType = sq.Expr("COUNT(DISTINCT bl.id)"), "bigint"
+	default:
+		sortKey, keyType = sq.Expr("bs.name"), "text"
+	}
+
 	builder := Qb.Select(
-		"bs.id",
+		"bs.id AS id",
 		"bs.name",
-		"bs.image_url",
 		"bs.lat",
 		"bs.lng",
-		"COALESCE(array_agg(DISTINCT bl.name ORDER BY bl.name), '{}') AS lines",
+		"COALESCE((SELECT array_agg(sc.code ORDER BY sc.code) FROM station_codes sc WHERE sc.station_id = bs.id), '{}') AS codes",
+		"COALESCE(array_agg(DISTINCT bl.name ORDER BY bl.name) FILTER (WHERE bl.name IS NOT NULL), '{}') AS lines",
+		stationLinesColumn(),
 	).
+		Columns(imageColumns()...).
+		Columns(attributeColumns("sa")...).
+		Column(sq.Alias(distance, "distance")).
+		Column(sq.Alias(sortKey, pagination.KeyColumn)).
 		From("bus_stations bs").
 		LeftJoin("bus_stations_bus_lines bsl ON bsl.bus_station_id = bs.id").
 		LeftJoin("bus_lines bl ON bl.id = bsl.bus_line_id").
-		GroupBy("bs.id").
-		Limit(uint64(limit)).
-		Offset(uint64(offset)).
-		OrderBy("bs.name")
+		LeftJoin("station_attributes sa ON sa.station_id = bs.id").
+		LeftJoin("station_images si ON si.station_id = bs.id").
+		GroupBy("bs.id", "sa.station_id", "si.station_id")
 
 	if opts != nil {
 		if opts.Line != "" {
@@ -75,51 +143,98 @@ func (store *PostgresBusStationStore) ListBusStations(limit, offset int, opts *B
 		if opts.Name != "" {
 			builder = builder.Where(sq.ILike{"bs.name": opts.Name + "%"})
 		}
+		if len(opts.Attributes) > 0 {
+			builder = builder.Where(hasAttributes("sa", opts.Attributes))
+		}
 	}
 
-	query, args, err := builder.ToSql()
+	query, args, err := pagination.Query(Qb, builder, page, keyType).ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("sql build error: %w", err)
 	}
 
-	rows, err := store.db.Query(query, args...)
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query execution error: %w", err)
 	}
 	defer rows.Close()
 
 	stations := make([]BusStation, 0)
+	var keys []string
 	for rows.Next() {
 		var s BusStation
+		var rawCodes pq.Int64Array
 		var rawLines pq.StringArray
-		if err := rows.Scan(&s.ID, &s.Name, &s.ImageURL, &s.Lat, &s.Lon, &rawLines); err != nil {
+		var lineDetails []byte
+		var image imageScan
+		var dist sql.NullFloat64
+		var key string
+		dest := append(append([]any{&s.ID, &s.Name, &s.Lat, &s.Lon, &rawCodes, &rawLines, &lineDetails}, image.dest()...), s.Attributes.scanDest()...)
+		if err := rows.Scan(append(dest, &dist, &key)...); err != nil {
 			return nil, err
 		}
+		s.Codes = make([]int, len(rawCodes))
+		for i, val := range rawCodes {
+			s.Codes[i] = int(val)
+		}
 		s.Lines = rawLines
+		if err := s.setLineDetails(lineDetails); err != nil {
+			return nil, err
+		}
+		s.setImage(image)
+		if dist.Valid {
+			s.Distance = &dist.Float64
+		}
 		stations = append(stations, s)
+		keys = append(keys, key)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return stations, nil
+	result := pagination.NewPage(stations, page, func(i int) (string, int) {
+		return keys[i], stations[i].ID
+	})
+
+	if page.WithTotal {
+		query, args, err := pagination.CountQuery(Qb, builder).ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("sql build error: %w", err)
+		}
+
+		traceQuery(span, query)
+		var total int
+		if err := store.db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+			return nil, fmt.Errorf("count query error: %w", err)
+		}
+		result.Total = &total
+	}
+
+	return result, nil
 }
 
-func (store *PostgresBusStationStore) FindBusStationByID(id int) (*BusStation, error) {
+func (store *PostgresBusStationStore) FindBusStationByID(ctx context.Context, id int) (_ *BusStation, err error) {
+	ctx, span := startSpan(ctx, "FindBusStationByID")
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	queryBuilder := Qb.
 		Select(
 			"bs.id",
 			"bs.name",
-			"bs.image_url",
 			"bs.lat",
 			"bs.lng",
 			"COALESCE(array_agg(sc.code ORDER BY sc.code), '{}') AS codes",
 		).
+		Columns(imageColumns()...).
+		Columns(attributeColumns("sa")...).
 		From("bus_stations bs").
 		LeftJoin("station_codes sc ON sc.station_id = bs.id").
+		LeftJoin("station_attributes sa ON sa.station_id = bs.id").
+		LeftJoin("station_images si ON si.station_id = bs.id").
 		Where(sq.Eq{"bs.id": id}).
-		GroupBy("bs.id")
+		GroupBy("bs.id", "sa.station_id", "si.station_id")
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -128,15 +243,17 @@ func (store *PostgresBusStationStore) FindBusStationByID(id int) (*BusStation, e
 
 	var station BusStation
 	var rawCodes pq.Int64Array
+	var image imageScan
 
-	err = store.db.QueryRow(query, args...).Scan(
+	traceQuery(span, query)
+	dest := append(append([]any{
 		&station.ID,
 		&station.Name,
-		&station.ImageURL,
 		&station.Lat,
 		&station.Lon,
 		&rawCodes,
-	)
+	}, image.dest()...), station.Attributes.scanDest()...)
+	err = store.db.QueryRowContext(ctx, query, args...).Scan(dest...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -148,11 +265,15 @@ func (store *PostgresBusStationStore) FindBusStationByID(id int) (*BusStation, e
 	for i, val := range rawCodes {
 		station.Codes[i] = int(val)
 	}
+	station.setImage(image)
 
 	return &station, nil
 }
 
-func (store *PostgresBusStationStore) FindBusStationIDByCode(code string) (*StationCode, error) {
+func (store *PostgresBusStationStore) FindBusStationIDByCode(ctx context.Context, code string) (_ *StationCode, err error) {
+	ctx, span := startSpan(ctx, "FindBusStationIDByCode")
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	queryBuilder := Qb.Select("id", "station_id", "code").
 		From("station_codes").
 		Where(sq.Eq{"code": code})
@@ -163,7 +284,8 @@ func (store *PostgresBusStationStore) FindBusStationIDByCode(code string) (*Stat
 	}
 
 	var stationCode StationCode
-	err = store.db.QueryRow(query, args...).Scan(&stationCode.ID, &stationCode.StationID, &stationCode.Code)
+	traceQuery(span, query)
+	err = store.db.QueryRowContext(ctx, query, args...).Scan(&stationCode.ID, &stationCode.StationID, &stationCode.Code)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -173,3 +295,316 @@ func (store *PostgresBusStationStore) FindBusStationIDByCode(code string) (*Stat
 
 	return &stationCode, nil
 }
+
+func (store *PostgresBusStationStore) FindBusStationIDsByLine(ctx context.Context, line string) (_ []int, err error) {
+	ctx, span := startSpan(ctx, "FindBusStationIDsByLine")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	queryBuilder := Qb.Select("bsl.bus_station_id").
+		From("bus_stations_bus_lines bsl").
+		Join("bus_lines bl ON bl.id = bsl.bus_line_id").
+		Where(sq.Eq{"bl.name": line})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// ListAllBusStations returns every bus station matching opts, with codes and
+// lines, without pagination. Unlike ListBusStations the line filter is an
+// exact match and does not narrow the returned lines.
+func (store *PostgresBusStationStore) ListAllBusStations(ctx context.Context, opts *BusStationFilterOptions) (_ []BusStation, err error) {
+	ctx, span := startSpan(ctx, "ListAllBusStations")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	builder := Qb.Select(
+		"bs.id",
+		"bs.name",
+		"bs.lat",
+		"bs.lng",
+		"COALESCE((SELECT array_agg(sc.code ORDER BY sc.code) FROM station_codes sc WHERE sc.station_id = bs.id), '{}') AS codes",
+		"COALESCE((SELECT array_agg(bl.name ORDER BY bl.name) FROM bus_stations_bus_lines bsl JOIN bus_lines bl ON bl.id = bsl.bus_line_id WHERE bsl.bus_station_id = bs.id), '{}') AS lines",
+		stationLinesColumn(),
+	).
+		Columns(imageColumns()...).
+		Columns(attributeColumns("sa")...).
+		From("bus_stations bs").
+		LeftJoin("station_attributes sa ON sa.station_id = bs.id").
+		LeftJoin("station_images si ON si.station_id = bs.id").
+		OrderBy("bs.name")
+
+	if opts != nil {
+		if opts.Name != "" {
+			builder = builder.Where(sq.ILike{"bs.name": opts.Name + "%"})
+		}
+		if opts.Line != "" {
+			builder = builder.Where(`
+				EXISTS (
+					SELECT 1 FROM bus_stations_bus_lines bsl
+					JOIN bus_lines bl ON bl.id = bsl.bus_line_id
+					WHERE bsl.bus_station_id = bs.id AND bl.name = ?
+				)
+			`, opts.Line)
+		}
+		if opts.BBox != nil {
+			builder = builder.Where(sq.And{
+				sq.GtOrEq{"bs.lng": opts.BBox.MinLon},
+				sq.LtOrEq{"bs.lng": opts.BBox.MaxLon},
+				sq.GtOrEq{"bs.lat": opts.BBox.MinLat},
+				sq.LtOrEq{"bs.lat": opts.BBox.MaxLat},
+			})
+		}
+		if len(opts.Attributes) > 0 {
+			builder = builder.Where(hasAttributes("sa", opts.Attributes))
+		}
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("sql build error: %w", err)
+	}
+
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query execution error: %w", err)
+	}
+	defer rows.Close()
+
+	stations := make([]BusStation, 0)
+	for rows.Next() {
+		var s BusStation
+		var rawCodes pq.Int64Array
+		var rawLines pq.StringArray
+		var lineDetails []byte
+		var image imageScan
+		dest := append(append([]any{&s.ID, &s.Name, &s.Lat, &s.Lon, &rawCodes, &rawLines, &lineDetails}, image.dest()...), s.Attributes.scanDest()...)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		s.Codes = make([]int, len(rawCodes))
+		for i, val := range rawCodes {
+			s.Codes[i] = int(val)
+		}
+		s.Lines = rawLines
+		if err := s.setLineDetails(lineDetails); err != nil {
+			return nil, err
+		}
+		s.setImage(image)
+		stations = append(stations, s)
+	}
+
+	return stations, rows.Err()
+}
+
+// FindBusStationsByIDs returns the stations with the given ids, with codes and
+// lines. Unknown ids are skipped.
+func (store *PostgresBusStationStore) FindBusStationsByIDs(ctx context.Context, ids []int) (_ []BusStation, err error) {
+	ctx, span := startSpan(ctx, "FindBusStationsByIDs")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	queryBuilder := Qb.Select(
+		"bs.id",
+		"bs.name",
+		"bs.lat",
+		"bs.lng",
+		"COALESCE((SELECT array_agg(sc.code ORDER BY sc.code) FROM station_codes sc WHERE sc.station_id = bs.id), '{}') AS codes",
+		"COALESCE((SELECT array_agg(bl.name ORDER BY bl.name) FROM bus_stations_bus_lines bsl JOIN bus_lines bl ON bl.id = bsl.bus_line_id WHERE bsl.bus_station_id = bs.id), '{}') AS lines",
+		stationLinesColumn(),
+	).
+		Columns(imageColumns()...).
+		Columns(attributeColumns("sa")...).
+		From("bus_stations bs").
+		LeftJoin("station_attributes sa ON sa.station_id = bs.id").
+		LeftJoin("station_images si ON si.station_id = bs.id").
+		Where(sq.Eq{"bs.id": ids})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	stations := make([]BusStation, 0, len(ids))
+	for rows.Next() {
+		var s BusStation
+		var rawCodes pq.Int64Array
+		var rawLines pq.StringArray
+		var lineDetails []byte
+		var image imageScan
+		dest := append(append([]any{&s.ID, &s.Name, &s.Lat, &s.Lon, &rawCodes, &rawLines, &lineDetails}, image.dest()...), s.Attributes.scanDest()...)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		s.Codes = make([]int, len(rawCodes))
+		for i, val := range rawCodes {
+			s.Codes[i] = int(val)
+		}
+		s.Lines = rawLines
+		if err := s.setLineDetails(lineDetails); err != nil {
+			return nil, err
+		}
+		s.setImage(image)
+		stations = append(stations, s)
+	}
+
+	return stations, rows.Err()
+}
+
+// FindStationCodesByStationIDs returns the codes of the given stations,
+// ordered by station and code.
+func (store *PostgresBusStationStore) FindStationCodesByStationIDs(ctx context.Context, stationIDs []int) (_ []StationCode, err error) {
+	ctx, span := startSpan(ctx, "FindStationCodesByStationIDs")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	queryBuilder := Qb.Select(stationCodeColumns("sc")...).
+		From("station_codes sc").
+		Where(sq.Eq{"sc.station_id": stationIDs}).
+		OrderBy("sc.station_id", "sc.code")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	codes := make([]StationCode, 0)
+	for rows.Next() {
+		var code StationCode
+		if err := rows.Scan(code.scanDest()...); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+
+	return codes, rows.Err()
+}
+
+// FindStopsByStationID returns the stops of a station ordered by code, each
+// with the lines and directions of its departures on any schedule.
+func (store *PostgresBusStationStore) FindStopsByStationID(ctx context.Context, stationID int) (_ []Stop, err error) {
+	ctx, span := startSpan(ctx, "FindStopsByStationID")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	queryBuilder := Qb.Select(stationCodeColumns("sc")...).
+		Columns(
+			"COALESCE(array_agg(DISTINCT bl.name ORDER BY bl.name) FILTER (WHERE bl.name IS NOT NULL), '{}') AS lines",
+			"COALESCE(array_agg(DISTINCT dir.name ORDER BY dir.name) FILTER (WHERE dir.name IS NOT NULL), '{}') AS directions",
+		).
+		From("station_codes sc").
+		LeftJoin("departures d ON d.code_id = sc.id").
+		LeftJoin("bus_lines bl ON bl.id = d.line_id").
+		LeftJoin("directions dir ON dir.id = d.direction_id").
+		Where(sq.Eq{"sc.station_id": stationID}).
+		GroupBy("sc.id").
+		OrderBy("sc.code")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	stops := make([]Stop, 0)
+	for rows.Next() {
+		var s Stop
+		var lines, directions pq.StringArray
+		if err := rows.Scan(append(s.scanDest(), &lines, &directions)...); err != nil {
+			return nil, err
+		}
+		s.Lines = lines
+		s.Directions = directions
+		stops = append(stops, s)
+	}
+
+	return stops, rows.Err()
+}
+
+// stationCodeColumns selects the station_codes row alias in scanDest order.
+func stationCodeColumns(alias string) []string {
+	return []string{
+		alias + ".id",
+		alias + ".station_id",
+		alias + ".code",
+		"COALESCE(" + alias + ".platform, '')",
+		alias + ".lat",
+		alias + ".lng",
+	}
+}
+
+func (c *StationCode) scanDest() []any {
+	return []any{&c.ID, &c.StationID, &c.Code, &c.Platform, &c.Lat, &c.Lon}
+}
+
+// FindBusStationIDsByLineIDs returns the ids of the stations served by each of
+// the given lines.
+func (store *PostgresBusStationStore) FindBusStationIDsByLineIDs(ctx context.Context, lineIDs []int) (_ map[int][]int, err error) {
+	ctx, span := startSpan(ctx, "FindBusStationIDsByLineIDs")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	queryBuilder := Qb.Select("bsl.bus_line_id", "bsl.bus_station_id").
+		From("bus_stations_bus_lines bsl").
+		Join("bus_stations bs ON bs.id = bsl.bus_station_id").
+		Where(sq.Eq{"bsl.bus_line_id": lineIDs}).
+		OrderBy("bsl.bus_line_id", "bs.name")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[int][]int, len(lineIDs))
+	for rows.Next() {
+		var lineID, stationID int
+		if err := rows.Scan(&lineID, &stationID); err != nil {
+			return nil, err
+		}
+		ids[lineID] = append(ids[lineID], stationID)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+type DataVersion struct {
+	Generation int64     `json:"generation"`
+	Reason     string    `json:"reason"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+} // @name DataVersion
+
+type DataVersionStore interface {
+	GetDataVersion(ctx context.Context) (*DataVersion, error)
+	BumpDataVersion(ctx context.Context, reason string) (*DataVersion, error)
+	CurrentVersion(ctx context.Context) (int64, time.Time, error)
+}
+
+type PostgresDataVersionStore struct {
+	db *sql.DB
+}
+
+func NewPostgresDataVersionStore(db *sql.DB) *PostgresDataVersionStore {
+	return &PostgresDataVersionStore{db: db}
+}
+
+func (store *PostgresDataVersionStore) GetDataVersion(ctx context.Context) (_ *DataVersion, err error) {
+	ctx, span := startSpan(ctx, "GetDataVersion")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	query, args, err := Qb.Select("generation", "COALESCE(reason, '')", "updated_at").
+		From("data_versions").
+		Where("id = 1").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	traceQuery(span, query)
+	var version DataVersion
+	err = store.db.QueryRowContext(ctx, query, args...).Scan(&version.Generation, &version.Reason, &version.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	version.UpdatedAt = utils.InLocation(version.UpdatedAt)
+	return &version, nil
+}
+
+func (store *PostgresDataVersionStore) BumpDataVersion(ctx context.Context, reason string) (_ *DataVersion, err error) {
+	ctx, span := startSpan(ctx, "BumpDataVersion")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	query, args, err := Qb.Update("data_versions").
+		Set("generation", sq.Expr("generation + 1")).
+		Set("reason", reason).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where("id = 1").
+		Suffix("RETURNING generation, reason, updated_at").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	traceQuery(span, query)
+	var version DataVersion
+	err = store.db.QueryRowContext(ctx, query, args...).Scan(&version.Generation, &version.Reason, &version.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	version.UpdatedAt = utils.InLocation(version.UpdatedAt)
+	return &version, nil
+}
+
+func (store *PostgresDataVersionStore) CurrentVersion(ctx context.Context) (int64, time.Time, error) {
+	version, err := store.GetDataVersion(ctx)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return version.Generation, version.UpdatedAt, nil
+}
@@ -1,8 +1,11 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	sq "github.com/Masterminds/squirrel"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
 	"time"
 )
 
@@ -14,22 +17,77 @@ const (
 	ScheduleTypeSunday   ScheduleType = "sunday"
 )
 
+// Label is the English name of the schedule as printed on timetables.
+func (t ScheduleType) Label() string {
+	switch t {
+	case ScheduleTypeSaturday:
+		return "Saturday"
+	case ScheduleTypeSunday:
+		return "Sunday and holidays"
+	default:
+		return "Weekday"
+	}
+}
+
 type Departure struct {
 	ID            int
 	StationCodeID int
 	LineID        int
 	Line          BusLine
 	Direction     string
-	DepartureTime string
+	DepartureTime utils.ServiceTime
 	ScheduleType  ScheduleType
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
 
+// LineStopTime is the first departure of a line at one stop in one direction.
+type LineStopTime struct {
+	DirectionID    int
+	Code           int
+	Lat            float64
+	Lon            float64
+	FirstDeparture utils.ServiceTime
+}
+
+// DepartureFilter narrows the departures returned for a station. Zero values
+// disable the respective filter.
+type DepartureFilter struct {
+	Lines     []string
+	Direction string // case-insensitive substring of the direction name
+	After     *utils.ServiceTime
+	Before    *utils.ServiceTime
+	Limit     int
+}
+
+func (f *DepartureFilter) apply(builder sq.SelectBuilder, alias string) sq.SelectBuilder {
+	if f == nil {
+		return builder
+	}
+	if len(f.Lines) > 0 {
+		builder = builder.Where(sq.Eq{"bl.name": f.Lines})
+	}
+	if f.Direction != "" {
+		builder = builder.Where(sq.ILike{"dir.name": "%" + f.Direction + "%"})
+	}
+	if f.After != nil {
+		builder = builder.Where(sq.GtOrEq{alias + ".departure_time": *f.After})
+	}
+	if f.Before != nil {
+		builder = builder.Where(sq.LtOrEq{alias + ".departure_time": *f.Before})
+	}
+	if f.Limit > 0 {
+		builder = builder.OrderBy(alias + ".departure_time").Limit(uint64(f.Limit))
+	}
+	return builder
+}
+
 type DepartureStore interface {
-	FindDeparturesByStationCode(stationCode int, scheduleType ScheduleType) ([]Departure, error)
-	FindDepartures(fromCode, toCode int, scheduleType ScheduleType) ([]Departure, error)
-	FindDeparturesByStationCodeAndDirection(stationCode int, direction string, scheduleType ScheduleType) ([]Departure, error)
+	FindDeparturesByStationCode(ctx context.Context, stationCode int, scheduleType ScheduleType) ([]Departure, error)
+	FindDepartures(ctx context.Context, fromCode, toCode int, scheduleType ScheduleType, filter *DepartureFilter) ([]Departure, error)
+	FindDeparturesByStationCodeAndDirection(ctx context.Context, stationCode int, direction string, scheduleType ScheduleType, filter *DepartureFilter) ([]Departure, error)
+	FindLineStopTimes(ctx context.Context, line string, scheduleType ScheduleType) ([]LineStopTime, error)
+	FindDeparturesByStationCodes(ctx context.Context, stationCodes []int, scheduleType ScheduleType) (map[int][]Departure, error)
 }
 
 type PostgresDepartureStore struct {
@@ -56,7 +114,10 @@ func ScheduleTyp(dateStr string) ScheduleType {
 	}
 }
 
-func (store *PostgresDepartureStore) FindDeparturesByStationCode(stationCode int, scheduleType ScheduleType) ([]Departure, error) {
+func (store *PostgresDepartureStore) FindDeparturesByStationCode(ctx context.Context, stationCode int, scheduleType ScheduleType) (_ []Departure, err error) {
+	ctx, span := startSpan(ctx, "FindDeparturesByStationCode")
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	queryBuilder := Qb.Select(
 		"d.id",
 		"d.code_id",
@@ -83,7 +144,8 @@ func (store *PostgresDepartureStore) FindDeparturesByStationCode(stationCode int
 		return nil, err
 	}
 
-	rows, err := store.db.Query(query, args...)
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -112,7 +174,10 @@ func (store *PostgresDepartureStore) FindDeparturesByStationCode(stationCode int
 	return departures, rows.Err()
 }
 
-func (store *PostgresDepartureStore) FindDepartures(fromCode, toCode int, scheduleType ScheduleType) ([]Departure, error) {
+func (store *PostgresDepartureStore) FindDepartures(ctx context.Context, fromCode, toCode int, scheduleType ScheduleType, filter *DepartureFilter) (_ []Departure, err error) {
+	ctx, span := startSpan(ctx, "FindDepartures")
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	queryBuilder := Qb.Select(
 		"d1.id",
 		"d1.code_id",
@@ -140,13 +205,15 @@ func (store *PostgresDepartureStore) FindDepartures(fromCode, toCode int, schedu
 				WHERE sc2.code = ? AND d2.schedule_type = d1.schedule_type AND d2.direction_id = d1.direction_id
 			)
 		`, toCode)
+	queryBuilder = filter.apply(queryBuilder, "d1")
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := store.db.Query(query, args...)
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -175,7 +242,10 @@ func (store *PostgresDepartureStore) FindDepartures(fromCode, toCode int, schedu
 	return departures, rows.Err()
 }
 
-func (store *PostgresDepartureStore) FindDeparturesByStationCodeAndDirection(stationCode int, direction string, scheduleType ScheduleType) ([]Departure, error) {
+func (store *PostgresDepartureStore) FindDeparturesByStationCodeAndDirection(ctx context.Context, stationCode int, direction string, scheduleType ScheduleType, filter *DepartureFilter) (_ []Departure, err error) {
+	ctx, span := startSpan(ctx, "FindDeparturesByStationCodeAndDirection")
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	queryBuilder := Qb.Select(
 		"d.id",
 		"d.code_id",
@@ -197,13 +267,15 @@ func (store *PostgresDepartureStore) FindDeparturesByStationCodeAndDirection(sta
 			"dir.name":        direction,
 			"d.schedule_type": scheduleType,
 		})
+	queryBuilder = filter.apply(queryBuilder, "d")
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := store.db.Query(query, args...)
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -231,3 +303,114 @@ func (store *PostgresDepartureStore) FindDeparturesByStationCodeAndDirection(sta
 
 	return departures, rows.Err()
 }
+
+func (store *PostgresDepartureStore) FindLineStopTimes(ctx context.Context, line string, scheduleType ScheduleType) (_ []LineStopTime, err error) {
+	ctx, span := startSpan(ctx, "FindLineStopTimes")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	queryBuilder := Qb.Select(
+		"d.direction_id",
+		"sc.code",
+		"sc.lat",
+		"sc.lng",
+		"MIN(d.departure_time) AS first_departure",
+	).
+		From("departures d").
+		Join("station_codes sc ON d.code_id = sc.id").
+		Join("bus_lines bl ON d.line_id = bl.id").
+		Where(sq.Eq{
+			"bl.name":         line,
+			"d.schedule_type": scheduleType,
+		}).
+		GroupBy("d.direction_id", "sc.code", "sc.lat", "sc.lng").
+		OrderBy("d.direction_id", "first_departure")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stopTimes []LineStopTime
+	for rows.Next() {
+		var st LineStopTime
+		if err := rows.Scan(&st.DirectionID, &st.Code, &st.Lat, &st.Lon, &st.FirstDeparture); err != nil {
+			return nil, err
+		}
+		stopTimes = append(stopTimes, st)
+	}
+
+	return stopTimes, rows.Err()
+}
+
+// FindDeparturesByStationCodes returns the departures from each of the given
+// station codes, ordered by departure time.
+func (store *PostgresDepartureStore) FindDeparturesByStationCodes(ctx context.Context, stationCodes []int, scheduleType ScheduleType) (_ map[int][]Departure, err error) {
+	ctx, span := startSpan(ctx, "FindDeparturesByStationCodes")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	queryBuilder := Qb.Select(
+		"sc.code",
+		"d.id",
+		"d.code_id",
+		"d.line_id",
+		"bl.id AS bus_line_id",
+		"bl.name AS bus_line_name",
+		"dir.name AS direction",
+		"d.departure_time",
+		"d.schedule_type",
+		"d.created_at",
+		"d.updated_at",
+	).
+		From("departures d").
+		Join("station_codes sc ON d.code_id = sc.id").
+		Join("bus_lines bl ON d.line_id = bl.id").
+		Join("directions dir ON d.direction_id = dir.id").
+		Where(sq.Eq{
+			"sc.code":         stationCodes,
+			"d.schedule_type": scheduleType,
+		}).
+		OrderBy("sc.code", "d.departure_time")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	departures := make(map[int][]Departure, len(stationCodes))
+	for rows.Next() {
+		var code int
+		var dep Departure
+		if err := rows.Scan(
+			&code,
+			&dep.ID,
+			&dep.StationCodeID,
+			&dep.LineID,
+			&dep.Line.ID,
+			&dep.Line.Name,
+			&dep.Direction,
+			&dep.DepartureTime,
+			&dep.ScheduleType,
+			&dep.CreatedAt,
+			&dep.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		departures[code] = append(departures[code], dep)
+	}
+
+	return departures, rows.Err()
+}
@@ -1,8 +1,10 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	sq "github.com/Masterminds/squirrel"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
 )
 
 type Direction struct {
@@ -11,8 +13,9 @@ type Direction struct {
 }
 
 type DirectionStore interface {
-	FindSharedDirectionsByCodes(fromCode, toCode int) ([]string, error)
-	FindDirectionsByStationCode(stationCode int) ([]Direction, error)
+	FindSharedDirectionsByCodes(ctx context.Context, fromCode, toCode int) ([]string, error)
+	FindDirectionsByStationCode(ctx context.Context, stationCode int) ([]Direction, error)
+	FindDirectionsByStationCodes(ctx context.Context, stationCodes []int) (map[int][]Direction, error)
 }
 
 type PostgresDirectionStore struct {
@@ -23,7 +26,10 @@ func NewPostgresDirectionStore(db *sql.DB) *PostgresDirectionStore {
 	return &PostgresDirectionStore{db: db}
 }
 
-func (store *PostgresDirectionStore) FindSharedDirectionsByCodes(fromCode, toCode int) ([]string, error) {
+func (store *PostgresDirectionStore) FindSharedDirectionsByCodes(ctx context.Context, fromCode, toCode int) (_ []string, err error) {
+	ctx, span := startSpan(ctx, "FindSharedDirectionsByCodes")
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	queryBuilder := Qb.Select("DISTINCT dir.name").
 		From("departures d1").
 		Join("station_codes sc1 ON d1.code_id = sc1.id").
@@ -40,7 +46,8 @@ func (store *PostgresDirectionStore) FindSharedDirectionsByCodes(fromCode, toCod
 		return nil, err
 	}
 
-	rows, err := store.db.Query(query, args...)
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -58,7 +65,10 @@ func (store *PostgresDirectionStore) FindSharedDirectionsByCodes(fromCode, toCod
 	return directions, rows.Err()
 }
 
-func (store *PostgresDirectionStore) FindDirectionsByStationCode(stationCode int) ([]Direction, error) {
+func (store *PostgresDirectionStore) FindDirectionsByStationCode(ctx context.Context, stationCode int) (_ []Direction, err error) {
+	ctx, span := startSpan(ctx, "FindDirectionsByStationCode")
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	queryBuilder := Qb.Select("d.id", "d.name").
 		From("directions d").
 		Join("departures dep ON dep.direction_id = d.id").
@@ -71,7 +81,8 @@ func (store *PostgresDirectionStore) FindDirectionsByStationCode(stationCode int
 		return nil, err
 	}
 
-	rows, err := store.db.Query(query, args...)
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -88,3 +99,42 @@ func (store *PostgresDirectionStore) FindDirectionsByStationCode(stationCode int
 
 	return directions, rows.Err()
 }
+
+// FindDirectionsByStationCodes returns the directions departing from each of
+// the given station codes.
+func (store *PostgresDirectionStore) FindDirectionsByStationCodes(ctx context.Context, stationCodes []int) (_ map[int][]Direction, err error) {
+	ctx, span := startSpan(ctx, "FindDirectionsByStationCodes")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	queryBuilder := Qb.Select("sc.code", "d.id", "d.name").
+		From("directions d").
+		Join("departures dep ON dep.direction_id = d.id").
+		Join("station_codes sc ON dep.code_id = sc.id").
+		Where(sq.Eq{"sc.code": stationCodes}).
+		GroupBy("sc.code", "d.id", "d.name").
+		OrderBy("sc.code", "d.name")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	directions := make(map[int][]Direction, len(stationCodes))
+	for rows.Next() {
+		var code int
+		var dir Direction
+		if err := rows.Scan(&code, &dir.ID, &dir.Name); err != nil {
+			return nil, err
+		}
+		directions[code] = append(directions[code], dir)
+	}
+
+	return directions, rows.Err()
+}
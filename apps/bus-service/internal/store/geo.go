@@ -0,0 +1,31 @@
+package store
+
+import sq "github.com/Masterminds/squirrel"
+
+// Point is a WGS84 coordinate.
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+// BBox is a bounding box in WGS84 coordinates.
+type BBox struct {
+	MinLon float64
+	MinLat float64
+	MaxLon float64
+	MaxLat float64
+}
+
+// Contains reports whether the point lies inside the box, edges included.
+func (b *BBox) Contains(lat, lon float64) bool {
+	return lon >= b.MinLon && lon <= b.MaxLon && lat >= b.MinLat && lat <= b.MaxLat
+}
+
+// distanceKm is the great-circle distance in kilometres between origin and
+// the coordinates in the given columns.
+func distanceKm(origin Point, latCol, lonCol string) sq.Sqlizer {
+	return sq.Expr(`6371 * 2 * ASIN(SQRT(
+		POWER(SIN(RADIANS(`+latCol+` - ?) / 2), 2) +
+		COS(RADIANS(?)) * COS(RADIANS(`+latCol+`)) * POWER(SIN(RADIANS(`+lonCol+` - ?) / 2), 2)
+	))`, origin.Lat, origin.Lat, origin.Lon)
+}
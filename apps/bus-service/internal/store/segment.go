@@ -0,0 +1,209 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+)
+
+// LineStop is a stop served by a line in one direction. Stops are returned in
+// travel order, derived from the first departure of the day at each stop.
+type LineStop struct {
+	LineID      int
+	Line        string
+	DirectionID int
+	Direction   string
+	CodeID      int
+	Code        int
+	Lat         float64
+	Lon         float64
+}
+
+// Segment is the stretch between two consecutive stops of a line.
+type Segment struct {
+	LineID      int
+	DirectionID int
+	Sequence    int
+	FromCodeID  int
+	FromCode    int
+	ToCodeID    int
+	ToCode      int
+	DistanceKm  float64
+	DurationMin float64
+	Provider    string
+}
+
+type SegmentStore interface {
+	ListLineStops(ctx context.Context, scheduleType ScheduleType) ([]LineStop, error)
+	ReplaceSegments(ctx context.Context, lineID, directionID int, segments []Segment) error
+	FindSegments(ctx context.Context, line, direction string) ([]Segment, error)
+}
+
+type PostgresSegmentStore struct {
+	db *sql.DB
+}
+
+func NewPostgresSegmentStore(db *sql.DB) *PostgresSegmentStore {
+	return &PostgresSegmentStore{db: db}
+}
+
+func (store *PostgresSegmentStore) ListLineStops(ctx context.Context, scheduleType ScheduleType) (_ []LineStop, err error) {
+	ctx, span := startSpan(ctx, "ListLineStops")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	queryBuilder := Qb.Select(
+		"bl.id",
+		"bl.name",
+		"dir.id",
+		"dir.name",
+		"sc.id",
+		"sc.code",
+		"sc.lat",
+		"sc.lng",
+	).
+		From("departures d").
+		Join("station_codes sc ON d.code_id = sc.id").
+		Join("bus_lines bl ON d.line_id = bl.id").
+		Join("directions dir ON d.direction_id = dir.id").
+		Where(sq.Eq{"d.schedule_type": scheduleType}).
+		GroupBy("bl.id", "bl.name", "dir.id", "dir.name", "sc.id", "sc.code", "sc.lat", "sc.lng").
+		OrderBy("bl.id", "dir.id", "MIN(d.departure_time)", "sc.code")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stops []LineStop
+	for rows.Next() {
+		var s LineStop
+		if err := rows.Scan(&s.LineID, &s.Line, &s.DirectionID, &s.Direction, &s.CodeID, &s.Code, &s.Lat, &s.Lon); err != nil {
+			return nil, err
+		}
+		stops = append(stops, s)
+	}
+
+	return stops, rows.Err()
+}
+
+// ReplaceSegments atomically swaps all segments of a line in one direction.
+func (store *PostgresSegmentStore) ReplaceSegments(ctx context.Context, lineID, directionID int, segments []Segment) (err error) {
+	ctx, span := startSpan(ctx, "ReplaceSegments")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	query, args, err := Qb.Delete("segments").
+		Where(sq.Eq{"line_id": lineID, "direction_id": directionID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	traceQuery(span, query)
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("delete segments: %w", err)
+	}
+
+	if len(segments) > 0 {
+		insert := Qb.Insert("segments").
+			Columns("line_id", "direction_id", "sequence", "from_code_id", "to_code_id", "distance_km", "duration_min", "provider")
+		for _, s := range segments {
+			insert = insert.Values(lineID, directionID, s.Sequence, s.FromCodeID, s.ToCodeID, s.DistanceKm, s.DurationMin, s.Provider)
+		}
+
+		query, args, err = insert.ToSql()
+		if err != nil {
+			return err
+		}
+
+		traceQuery(span, query)
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("insert segments: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// FindSegments returns the segments of a line in one direction, ordered by
+// sequence.
+func (store *PostgresSegmentStore) FindSegments(ctx context.Context, line, direction string) (_ []Segment, err error) {
+	ctx, span := startSpan(ctx, "FindSegments")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	queryBuilder := Qb.Select(
+		"s.line_id",
+		"s.direction_id",
+		"s.sequence",
+		"s.from_code_id",
+		"sc1.code",
+		"s.to_code_id",
+		"sc2.code",
+		"s.distance_km",
+		"s.duration_min",
+		"s.provider",
+	).
+		From("segments s").
+		Join("bus_lines bl ON s.line_id = bl.id").
+		Join("directions dir ON s.direction_id = dir.id").
+		Join("station_codes sc1 ON s.from_code_id = sc1.id").
+		Join("station_codes sc2 ON s.to_code_id = sc2.id").
+		Where(sq.Eq{
+			"bl.name":  line,
+			"dir.name": direction,
+		}).
+		OrderBy("s.sequence")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var segments []Segment
+	for rows.Next() {
+		var s Segment
+		if err := rows.Scan(
+			&s.LineID,
+			&s.DirectionID,
+			&s.Sequence,
+			&s.FromCodeID,
+			&s.FromCode,
+			&s.ToCodeID,
+			&s.ToCode,
+			&s.DistanceKm,
+			&s.DurationMin,
+			&s.Provider,
+		); err != nil {
+			return nil, err
+		}
+		segments = append(segments, s)
+	}
+
+	return segments, rows.Err()
+}
@@ -0,0 +1,192 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Station attribute names, as used in filters and CSV headers.
+const (
+	AttrWheelchairAccessible = "wheelchairAccessible"
+	AttrShelter              = "shelter"
+	AttrBench                = "bench"
+	AttrRealtimeDisplay      = "realtimeDisplay"
+	AttrTicketMachine        = "ticketMachine"
+	AttrTactilePaving        = "tactilePaving"
+)
+
+// StationAttributeNames lists the attributes in column order.
+var StationAttributeNames = []string{
+	AttrWheelchairAccessible,
+	AttrShelter,
+	AttrBench,
+	AttrRealtimeDisplay,
+	AttrTicketMachine,
+	AttrTactilePaving,
+}
+
+var stationAttributeColumns = map[string]string{
+	AttrWheelchairAccessible: "wheelchair_accessible",
+	AttrShelter:              "shelter",
+	AttrBench:                "bench",
+	AttrRealtimeDisplay:      "realtime_display",
+	AttrTicketMachine:        "ticket_machine",
+	AttrTactilePaving:        "tactile_paving",
+}
+
+// StationAttributes describe the accessibility and amenities of a station. A
+// nil field is unknown.
+type StationAttributes struct {
+	WheelchairAccessible *bool `json:"wheelchairAccessible"`
+	Shelter              *bool `json:"shelter"`
+	Bench                *bool `json:"bench"`
+	RealtimeDisplay      *bool `json:"realtimeDisplay"`
+	TicketMachine        *bool `json:"ticketMachine"`
+	TactilePaving        *bool `json:"tactilePaving"`
+} // @name StationAttributes
+
+// StepFree reports whether the station is known to be wheelchair accessible.
+func (a StationAttributes) StepFree() bool {
+	return a.WheelchairAccessible != nil && *a.WheelchairAccessible
+}
+
+// Field returns the attribute called name, one of StationAttributeNames.
+func (a *StationAttributes) Field(name string) **bool {
+	switch name {
+	case AttrWheelchairAccessible:
+		return &a.WheelchairAccessible
+	case AttrShelter:
+		return &a.Shelter
+	case AttrBench:
+		return &a.Bench
+	case AttrRealtimeDisplay:
+		return &a.RealtimeDisplay
+	case AttrTicketMachine:
+		return &a.TicketMachine
+	case AttrTactilePaving:
+		return &a.TactilePaving
+	}
+	return nil
+}
+
+func (a *StationAttributes) values(names []string) []any {
+	values := make([]any, len(names))
+	for i, name := range names {
+		values[i] = *a.Field(name)
+	}
+	return values
+}
+
+func (a *StationAttributes) scanDest() []any {
+	dest := make([]any, len(StationAttributeNames))
+	for i, name := range StationAttributeNames {
+		dest[i] = a.Field(name)
+	}
+	return dest
+}
+
+// attributeColumns selects the attributes of the station_attributes row
+// joined as alias, in StationAttributeNames order.
+func attributeColumns(alias string) []string {
+	columns := make([]string, len(StationAttributeNames))
+	for i, name := range StationAttributeNames {
+		columns[i] = alias + "." + stationAttributeColumns[name]
+	}
+	return columns
+}
+
+// hasAttributes requires each of the named attributes to be true.
+func hasAttributes(alias string, names []string) sq.Sqlizer {
+	conds := sq.And{}
+	for _, name := range names {
+		conds = append(conds, sq.Expr(alias+"."+stationAttributeColumns[name]+" IS TRUE"))
+	}
+	return conds
+}
+
+// StationAttributesRow assigns attributes to a station in an import.
+type StationAttributesRow struct {
+	StationID  int
+	Attributes StationAttributes
+}
+
+type StationAttributesStore interface {
+	UpsertStationAttributes(ctx context.Context, stationID int, attrs StationAttributes) error
+	ImportStationAttributes(ctx context.Context, names []string, rows []StationAttributesRow) error
+}
+
+type PostgresStationAttributesStore struct {
+	db *sql.DB
+}
+
+func NewPostgresStationAttributesStore(db *sql.DB) *PostgresStationAttributesStore {
+	return &PostgresStationAttributesStore{db: db}
+}
+
+// UpsertStationAttributes replaces all attributes of a station.
+func (store *PostgresStationAttributesStore) UpsertStationAttributes(ctx context.Context, stationID int, attrs StationAttributes) (err error) {
+	ctx, span := startSpan(ctx, "UpsertStationAttributes")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	return upsertStationAttributes(ctx, span, store.db, StationAttributeNames, []StationAttributesRow{{StationID: stationID, Attributes: attrs}})
+}
+
+// ImportStationAttributes sets the named attributes of every station in rows
+// in one transaction. Other attributes and stations not in rows keep their
+// values.
+func (store *PostgresStationAttributesStore) ImportStationAttributes(ctx context.Context, names []string, rows []StationAttributesRow) (err error) {
+	ctx, span := startSpan(ctx, "ImportStationAttributes")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	const batchSize = 500
+	for start := 0; start < len(rows); start += batchSize {
+		if err := upsertStationAttributes(ctx, span, tx, names, rows[start:min(start+batchSize, len(rows))]); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// upsertStationAttributes writes the named attributes of rows.
+func upsertStationAttributes(ctx context.Context, span trace.Span, db execer, names []string, rows []StationAttributesRow) error {
+	columns := make([]string, len(names))
+	updates := make([]string, len(names))
+	for i, name := range names {
+		columns[i] = stationAttributeColumns[name]
+		updates[i] = fmt.Sprintf("%[1]s = EXCLUDED.%[1]s", columns[i])
+	}
+
+	insert := Qb.Insert("station_attributes").
+		Columns(append([]string{"station_id"}, columns...)...)
+	for _, row := range rows {
+		insert = insert.Values(append([]any{row.StationID}, row.Attributes.values(names)...)...)
+	}
+
+	query, args, err := insert.
+		Suffix("ON CONFLICT (station_id) DO UPDATE SET " + strings.Join(updates, ", ") + ", updated_at = CURRENT_TIMESTAMP").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	traceQuery(span, query)
+	_, err = db.ExecContext(ctx, query, args...)
+	return err
+}
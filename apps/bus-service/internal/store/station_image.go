@@ -0,0 +1,176 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+// ImagePath is the path a stored image is served from. size is empty for the
+// original.
+func ImagePath(hash, size string) string {
+	if size == "" {
+		return "/api/images/" + hash
+	}
+	return "/api/images/" + hash + "?size=" + size
+}
+
+// StationImagePath is the path that fetches the image of a station on first
+// use and redirects to its ImagePath.
+func StationImagePath(id int) string {
+	return fmt.Sprintf("/api/images/stations/%d", id)
+}
+
+// StationImage records the copy of a station's photo. An empty Hash means
+// SourceURL could not be fetched or is not an image.
+type StationImage struct {
+	StationID   int
+	SourceURL   string
+	Hash        string
+	ContentType string
+	Width       int
+	Height      int
+	FetchedAt   time.Time
+}
+
+// imageColumns select the operator URL of the station row bs and the stored
+// copy from the station_images row si, as read by setImage.
+func imageColumns() []string {
+	return []string{
+		"COALESCE(bs.image_url, '')",
+		"COALESCE(si.hash, '')",
+		"COALESCE(si.source_url = bs.image_url, false) AS image_checked",
+	}
+}
+
+// imageScan receives the imageColumns of a station row.
+type imageScan struct {
+	source  string
+	hash    string
+	checked bool
+}
+
+func (i *imageScan) dest() []any {
+	return []any{&i.source, &i.hash, &i.checked}
+}
+
+// setImage points ImageURL at our copy of the image: its content-addressed
+// path once it is stored, or the station path that fetches it. Stations
+// whose image turned out to be missing get no URL.
+func (s *BusStation) setImage(i imageScan) {
+	s.SourceImageURL = i.source
+	switch {
+	case i.source == "":
+	case !i.checked:
+		s.ImageURL = StationImagePath(s.ID)
+	case i.hash != "":
+		s.ImageHash = i.hash
+		s.ImageURL = ImagePath(i.hash, "")
+	}
+}
+
+// ImageSizeURL returns ImageURL scaled down to one of the thumbnail sizes,
+// or an empty string when the station has no image.
+func (s *BusStation) ImageSizeURL(size string) string {
+	if s.ImageURL == "" {
+		return ""
+	}
+	return s.ImageURL + "?size=" + size
+}
+
+type StationImageStore interface {
+	FindStationImage(ctx context.Context, stationID int) (*StationImage, error)
+	UpsertStationImage(ctx context.Context, img *StationImage) error
+}
+
+type PostgresStationImageStore struct {
+	db *sql.DB
+}
+
+func NewPostgresStationImageStore(db *sql.DB) *PostgresStationImageStore {
+	return &PostgresStationImageStore{db: db}
+}
+
+func (store *PostgresStationImageStore) FindStationImage(ctx context.Context, stationID int) (_ *StationImage, err error) {
+	ctx, span := startSpan(ctx, "FindStationImage")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	query, args, err := Qb.Select(
+		"station_id",
+		"source_url",
+		"COALESCE(hash, '')",
+		"COALESCE(content_type, '')",
+		"COALESCE(width, 0)",
+		"COALESCE(height, 0)",
+		"fetched_at",
+	).
+		From("station_images").
+		Where(sq.Eq{"station_id": stationID}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var img StationImage
+	traceQuery(span, query)
+	err = store.db.QueryRowContext(ctx, query, args...).Scan(
+		&img.StationID, &img.SourceURL, &img.Hash, &img.ContentType, &img.Width, &img.Height, &img.FetchedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	img.FetchedAt = utils.InLocation(img.FetchedAt)
+	return &img, nil
+}
+
+// UpsertStationImage records the outcome of fetching a station's image,
+// replacing the previous one.
+func (store *PostgresStationImageStore) UpsertStationImage(ctx context.Context, img *StationImage) (err error) {
+	ctx, span := startSpan(ctx, "UpsertStationImage")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	query, args, err := Qb.Insert("station_images").
+		Columns("station_id", "source_url", "hash", "content_type", "width", "height").
+		Values(img.StationID, img.SourceURL, nullIfEmpty(img.Hash), nullIfEmpty(img.ContentType), nullIfZero(img.Width), nullIfZero(img.Height)).
+		Suffix(`ON CONFLICT (station_id) DO UPDATE SET
+			source_url = EXCLUDED.source_url,
+			hash = EXCLUDED.hash,
+			content_type = EXCLUDED.content_type,
+			width = EXCLUDED.width,
+			height = EXCLUDED.height,
+			fetched_at = CURRENT_TIMESTAMP
+		RETURNING fetched_at`).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	traceQuery(span, query)
+	if err := store.db.QueryRowContext(ctx, query, args...).Scan(&img.FetchedAt); err != nil {
+		return err
+	}
+	img.FetchedAt = utils.InLocation(img.FetchedAt)
+	return nil
+}
+
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nullIfZero(n int) any {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
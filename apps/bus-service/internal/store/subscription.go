@@ -0,0 +1,301 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+// Weekdays are the day names a subscription repeats on, indexed by
+// time.Weekday.
+var Weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// WeekdayName returns the name a subscription uses for d.
+func WeekdayName(d time.Weekday) string {
+	return Weekdays[d]
+}
+
+// Subscription is a saved trip whose departures inside the time window are
+// reminded LeadMinutes ahead through Channel. Subscriptions created with a
+// ConfirmationHash get no reminders until they are confirmed.
+type Subscription struct {
+	ID            int
+	Name          string
+	FromStationID int
+	ToStationID   int
+	Lines         []string
+	Weekdays      []string
+	WindowStart   utils.ServiceTime
+	WindowEnd     utils.ServiceTime
+	LeadMinutes   int
+	Channel       string
+	Target        string
+	Lang          string
+	TokenHash     []byte
+	CreatedAt     time.Time
+	// ConfirmationHash is the hash of the code sent to Target while the
+	// subscription is unconfirmed.
+	ConfirmationHash []byte
+	ConfirmedAt      *time.Time
+}
+
+type SubscriptionStore interface {
+	CreateSubscription(ctx context.Context, s *Subscription) error
+	FindSubscriptionByID(ctx context.Context, id int) (*Subscription, error)
+	DeleteSubscription(ctx context.Context, id int) (bool, error)
+	ConfirmSubscription(ctx context.Context, id int) (*time.Time, error)
+	ListSubscriptionsForWeekday(ctx context.Context, weekday string) ([]Subscription, error)
+	ClaimReminder(ctx context.Context, subscriptionID int, serviceDate string, departureID int) (bool, error)
+	ReleaseReminder(ctx context.Context, subscriptionID int, serviceDate string, departureID int) error
+}
+
+type PostgresSubscriptionStore struct {
+	db *sql.DB
+}
+
+func NewPostgresSubscriptionStore(db *sql.DB) *PostgresSubscriptionStore {
+	return &PostgresSubscriptionStore{db: db}
+}
+
+var subscriptionColumns = []string{
+	"id", "name", "from_station_id", "to_station_id", "lines", "weekdays",
+	"window_start", "window_end", "lead_minutes", "channel", "target", "lang",
+	"token_hash", "created_at", "confirmation_hash", "confirmed_at",
+}
+
+// CreateSubscription inserts s and sets its ID and CreatedAt. Without a
+// ConfirmationHash it is confirmed right away and ConfirmedAt is set too.
+func (store *PostgresSubscriptionStore) CreateSubscription(ctx context.Context, s *Subscription) (err error) {
+	ctx, span := startSpan(ctx, "CreateSubscription")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	// A nil []byte would be written as an empty bytea, not NULL.
+	var confirmationHash any = s.ConfirmationHash
+	confirmedAt := sq.Expr("NULL")
+	if s.ConfirmationHash == nil {
+		confirmationHash = nil
+		confirmedAt = sq.Expr("CURRENT_TIMESTAMP")
+	}
+
+	query, args, err := Qb.Insert("subscriptions").
+		Columns("name", "from_station_id", "to_station_id", "lines", "weekdays",
+			"window_start", "window_end", "lead_minutes", "channel", "target", "lang", "token_hash",
+			"confirmation_hash", "confirmed_at").
+		Values(s.Name, s.FromStationID, s.ToStationID, pq.StringArray(s.Lines), pq.StringArray(s.Weekdays),
+			int(s.WindowStart), int(s.WindowEnd), s.LeadMinutes, s.Channel, s.Target, s.Lang, s.TokenHash,
+			confirmationHash, confirmedAt).
+		Suffix("RETURNING id, created_at, confirmed_at").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	traceQuery(span, query)
+	var confirmed sql.NullTime
+	if err := store.db.QueryRowContext(ctx, query, args...).Scan(&s.ID, &s.CreatedAt, &confirmed); err != nil {
+		return err
+	}
+	s.CreatedAt = utils.InLocation(s.CreatedAt)
+	s.ConfirmedAt = confirmedTime(confirmed)
+	return nil
+}
+
+func (store *PostgresSubscriptionStore) FindSubscriptionByID(ctx context.Context, id int) (_ *Subscription, err error) {
+	ctx, span := startSpan(ctx, "FindSubscriptionByID")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	query, args, err := Qb.Select(subscriptionColumns...).
+		From("subscriptions").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	traceQuery(span, query)
+	s, err := scanSubscription(store.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (store *PostgresSubscriptionStore) DeleteSubscription(ctx context.Context, id int) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "DeleteSubscription")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	query, args, err := Qb.Delete("subscriptions").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	traceQuery(span, query)
+	res, err := store.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ConfirmSubscription marks the subscription as confirmed and forgets its
+// confirmation code. It returns when the subscription was confirmed, or nil
+// when it does not exist or was already confirmed.
+func (store *PostgresSubscriptionStore) ConfirmSubscription(ctx context.Context, id int) (_ *time.Time, err error) {
+	ctx, span := startSpan(ctx, "ConfirmSubscription")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	query, args, err := Qb.Update("subscriptions").
+		Set("confirmed_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Set("confirmation_hash", nil).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": id, "confirmed_at": nil}).
+		Suffix("RETURNING confirmed_at").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	traceQuery(span, query)
+	var confirmed sql.NullTime
+	err = store.db.QueryRowContext(ctx, query, args...).Scan(&confirmed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return confirmedTime(confirmed), nil
+}
+
+// ListSubscriptionsForWeekday returns the confirmed subscriptions repeating
+// on weekday, one of Weekdays.
+func (store *PostgresSubscriptionStore) ListSubscriptionsForWeekday(ctx context.Context, weekday string) (_ []Subscription, err error) {
+	ctx, span := startSpan(ctx, "ListSubscriptionsForWeekday")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	query, args, err := Qb.Select(subscriptionColumns...).
+		From("subscriptions").
+		Where("? = ANY(weekdays)", weekday).
+		Where(sq.NotEq{"confirmed_at": nil}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	traceQuery(span, query)
+	rows, err := store.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []Subscription
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, *s)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// ClaimReminder records that the reminder for a departure on serviceDate is
+// being sent. It reports false when it was already claimed.
+func (store *PostgresSubscriptionStore) ClaimReminder(ctx context.Context, subscriptionID int, serviceDate string, departureID int) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "ClaimReminder")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	query, args, err := Qb.Insert("subscription_reminders").
+		Columns("subscription_id", "service_date", "departure_id").
+		Values(subscriptionID, serviceDate, departureID).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	traceQuery(span, query)
+	res, err := store.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ReleaseReminder undoes ClaimReminder so a failed reminder is retried.
+func (store *PostgresSubscriptionStore) ReleaseReminder(ctx context.Context, subscriptionID int, serviceDate string, departureID int) (err error) {
+	ctx, span := startSpan(ctx, "ReleaseReminder")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	query, args, err := Qb.Delete("subscription_reminders").
+		Where(sq.Eq{"subscription_id": subscriptionID, "service_date": serviceDate, "departure_id": departureID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	traceQuery(span, query)
+	_, err = store.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSubscription(row rowScanner) (*Subscription, error) {
+	var s Subscription
+	var lines, weekdays pq.StringArray
+	var confirmed sql.NullTime
+	err := row.Scan(
+		&s.ID,
+		&s.Name,
+		&s.FromStationID,
+		&s.ToStationID,
+		&lines,
+		&weekdays,
+		&s.WindowStart,
+		&s.WindowEnd,
+		&s.LeadMinutes,
+		&s.Channel,
+		&s.Target,
+		&s.Lang,
+		&s.TokenHash,
+		&s.CreatedAt,
+		&s.ConfirmationHash,
+		&confirmed,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Lines = lines
+	s.Weekdays = weekdays
+	s.CreatedAt = utils.InLocation(s.CreatedAt)
+	s.ConfirmedAt = confirmedTime(confirmed)
+	return &s, nil
+}
+
+func confirmedTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	confirmed := utils.InLocation(t.Time)
+	return &confirmed
+}
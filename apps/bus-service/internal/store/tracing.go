@@ -0,0 +1,17 @@
+package store
+
+import (
+	"context"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return telemetry.StartSpan(ctx, "store."+name, attribute.String("db.system", "postgresql"))
+}
+
+func traceQuery(span trace.Span, query string) {
+	span.SetAttributes(attribute.String("db.statement", query))
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/perkzen/mbus/apps/bus-service/internal/i18n"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+)
+
+// Entity types with translatable fields.
+const (
+	EntityBusLine = "bus_line"
+	EntityAlert   = "alert"
+)
+
+// TranslatableFields lists the fields of each entity type that can be
+// localized.
+var TranslatableFields = map[string][]string{
+	EntityBusLine: {"description"},
+	EntityAlert:   {"title", "body"},
+}
+
+// IsTranslatable reports whether field of entityType can be localized.
+func IsTranslatable(entityType, field string) bool {
+	return slices.Contains(TranslatableFields[entityType], field)
+}
+
+type Translation struct {
+	EntityType string `json:"entityType"`
+	EntityID   int    `json:"entityId"`
+	Field      string `json:"field"`
+	Lang       string `json:"lang"`
+	Value      string `json:"value"`
+} // @name Translation
+
+type TranslationStore interface {
+	UpsertTranslation(ctx context.Context, t Translation) error
+	DeleteTranslation(ctx context.Context, t Translation) (bool, error)
+}
+
+type PostgresTranslationStore struct {
+	db *sql.DB
+}
+
+func NewPostgresTranslationStore(db *sql.DB) *PostgresTranslationStore {
+	return &PostgresTranslationStore{db: db}
+}
+
+func (store *PostgresTranslationStore) UpsertTranslation(ctx context.Context, t Translation) (err error) {
+	ctx, span := startSpan(ctx, "UpsertTranslation")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	query, args, err := Qb.Insert("translations").
+		Columns("entity_type", "entity_id", "field", "lang", "value").
+		Values(t.EntityType, t.EntityID, t.Field, t.Lang, t.Value).
+		Suffix("ON CONFLICT (entity_type, entity_id, field, lang) DO UPDATE SET value = EXCLUDED.value, updated_at = CURRENT_TIMESTAMP").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	traceQuery(span, query)
+	_, err = store.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (store *PostgresTranslationStore) DeleteTranslation(ctx context.Context, t Translation) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "DeleteTranslation")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	query, args, err := Qb.Delete("translations").
+		Where(sq.Eq{"entity_type": t.EntityType, "entity_id": t.EntityID, "field": t.Field, "lang": t.Lang}).
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	traceQuery(span, query)
+	res, err := store.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// translated selects a localized field of the entity whose id is in
+// idColumn. Missing translations fall back to the Slovenian source text and
+// then to an empty string.
+func translated(entityType, field, idColumn string, lang i18n.Lang) sq.Sqlizer {
+	return translatedOr(entityType, field, idColumn, "''", lang)
+}
+
+// translatedOr is translated for entities that keep their Slovenian source
+// text in sourceColumn rather than in translations.
+func translatedOr(entityType, field, idColumn, sourceColumn string, lang i18n.Lang) sq.Sqlizer {
+	return sq.Expr(`COALESCE((
+		SELECT t.value FROM translations t
+		WHERE t.entity_type = ? AND t.entity_id = `+idColumn+` AND t.field = ? AND t.lang IN (?, ?)
+		ORDER BY t.lang = ? DESC
+		LIMIT 1
+	), `+sourceColumn+`)`, entityType, field, string(lang), string(i18n.Slovenian), string(lang))
+}
@@ -3,39 +3,256 @@ package utils
 import (
 	"context"
 	"encoding/json"
+	"log/slog"
+	"reflect"
 	"time"
 
-	"github.com/redis/go-redis/v9"
+	"github.com/perkzen/mbus/apps/bus-service/internal/cache"
+	"github.com/perkzen/mbus/apps/bus-service/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/singleflight"
 )
 
-func TryGetFromCache[T any](ctx context.Context, client *redis.Client, key string) (*T, bool) {
-	result, err := client.Get(ctx, key).Result()
-	if err != nil || result == "" {
+// loadGroup coalesces concurrent loads of the same key within this process.
+var loadGroup singleflight.Group
+
+// loadTimeout bounds a load detached from the request that started it: a
+// shared load, which serves every waiting caller, and a background refresh,
+// which outlives the request that found the stale entry.
+const loadTimeout = 30 * time.Second
+
+// lockPollInterval is how often a caller waiting on another instance's lock
+// checks whether the value has appeared in the cache.
+const lockPollInterval = 50 * time.Millisecond
+
+type cacheOptions struct {
+	staleTTL    time.Duration
+	negativeTTL time.Duration
+	lockTTL     time.Duration
+	degradedTTL time.Duration
+	degraded    func(value any) bool
+}
+
+type CacheOption func(*cacheOptions)
+
+// WithStaleWhileRevalidate keeps entries for d after they expire. During
+// that window the stale value is served immediately while a single
+// background refresh replaces it.
+func WithStaleWhileRevalidate(d time.Duration) CacheOption {
+	return func(o *cacheOptions) {
+		o.staleTTL = d
+	}
+}
+
+// WithNegativeTTL caches empty results (nil, or empty slices and maps) for
+// ttl instead of the regular TTL.
+func WithNegativeTTL(ttl time.Duration) CacheOption {
+	return func(o *cacheOptions) {
+		o.negativeTTL = ttl
+	}
+}
+
+// WithDegradedTTL caches values for which degraded reports true, e.g. ones
+// built from a fallback, for at most ttl instead of the regular TTL, so they
+// are replaced soon after the primary source recovers.
+func WithDegradedTTL(ttl time.Duration, degraded func(value any) bool) CacheOption {
+	return func(o *cacheOptions) {
+		o.degradedTTL = ttl
+		o.degraded = degraded
+	}
+}
+
+// WithDistributedLock makes only one instance run the loader for a key when
+// the backend implements cache.Locker. Other instances wait up to ttl for the
+// value to appear and then fall back to loading themselves.
+func WithDistributedLock(ttl time.Duration) CacheOption {
+	return func(o *cacheOptions) {
+		o.lockTTL = ttl
+	}
+}
+
+// cacheEnvelope wraps a cached value with the moment it stops being fresh.
+// The entry itself lives longer when stale-while-revalidate is enabled.
+type cacheEnvelope[T any] struct {
+	Value      T         `json:"v"`
+	FreshUntil time.Time `json:"f"`
+}
+
+func TryGetFromCache[T any](ctx context.Context, c cache.Cache, key string) (*T, bool) {
+	ctx, span := telemetry.StartSpan(ctx, "cache.get", attribute.String("cache.key", key))
+	defer span.End()
+
+	result, err := c.Get(ctx, key)
+	if err != nil || len(result) == 0 {
+		span.SetAttributes(attribute.Bool("cache.hit", false))
 		return nil, false
 	}
 
 	var value T
-	if err := json.Unmarshal([]byte(result), &value); err != nil {
+	if err := json.Unmarshal(result, &value); err != nil {
+		span.RecordError(err)
 		return nil, false
 	}
+	span.SetAttributes(attribute.Bool("cache.hit", true))
 	return &value, true
 }
 
-func SaveToCache(ctx context.Context, client *redis.Client, key string, value any, ttl time.Duration) {
+func SaveToCache(ctx context.Context, c cache.Cache, key string, value any, ttl time.Duration) {
+	ctx, span := telemetry.StartSpan(ctx, "cache.set", attribute.String("cache.key", key))
+	defer span.End()
+
 	if data, err := json.Marshal(value); err == nil {
-		_ = client.Set(ctx, key, data, ttl).Err()
+		if err := c.Set(ctx, key, data, ttl); err != nil {
+			span.RecordError(err)
+		}
+	}
+}
+
+// WithCache returns the cached value for key or loads and caches it.
+// Concurrent misses for the same key in this process share one loader call.
+// The shared load runs detached from the caller that started it, with its own
+// deadline, so that caller's cancellation or shorter deadline never fails the
+// others; each caller stops waiting when its own context is done. The loader
+// must use the context it is given.
+func WithCache[T any](ctx context.Context, c cache.Cache, key string, ttl time.Duration, loader func(ctx context.Context) (T, error), opts ...CacheOption) (T, error) {
+	o := &cacheOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if entry, ok := TryGetFromCache[cacheEnvelope[T]](ctx, c, key); ok {
+		if time.Now().Before(entry.FreshUntil) {
+			return entry.Value, nil
+		}
+		if o.staleTTL > 0 {
+			go revalidate(context.WithoutCancel(ctx), c, key, ttl, loader, o)
+			return entry.Value, nil
+		}
+	}
+
+	loadCtx := context.WithoutCancel(ctx)
+	results := loadGroup.DoChan(key, func() (any, error) {
+		ctx, cancel := context.WithTimeout(loadCtx, loadTimeout)
+		defer cancel()
+		return load(ctx, c, key, ttl, loader, o)
+	})
+
+	select {
+	case res := <-results:
+		if res.Err != nil {
+			var zero T
+			return zero, res.Err
+		}
+		return res.Val.(T), nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
 	}
 }
 
-func WithCache[T any](ctx context.Context, cache *redis.Client, key string, ttl time.Duration, loader func() (T, error)) (T, error) {
-	if cached, ok := TryGetFromCache[T](ctx, cache, key); ok {
-		return *cached, nil
+func load[T any](ctx context.Context, c cache.Cache, key string, ttl time.Duration, loader func(ctx context.Context) (T, error), o *cacheOptions) (T, error) {
+	if locker, ok := c.(cache.Locker); ok && o.lockTTL > 0 {
+		release, acquired, err := locker.TryLock(ctx, lockKey(key), o.lockTTL)
+		switch {
+		case err != nil:
+			slog.Warn("cache lock failed, loading without it", slog.String("key", key), slog.Any("error", err))
+		case acquired:
+			defer release()
+		default:
+			if value, ok := waitForValue[T](ctx, c, key, o.lockTTL); ok {
+				return value, nil
+			}
+		}
 	}
-	data, err := loader()
+
+	data, err := loader(ctx)
 	if err != nil {
 		var zero T
 		return zero, err
 	}
-	SaveToCache(ctx, cache, key, data, ttl)
+
+	// Keep the computed value even if the load ran up to its deadline.
+	store(context.WithoutCancel(ctx), c, key, data, ttl, o)
 	return data, nil
 }
+
+// revalidate refreshes a stale entry in the background. ctx is detached from
+// the request and gets its own deadline. The singleflight key differs from
+// the foreground one so a refresh never blocks a cold load.
+func revalidate[T any](ctx context.Context, c cache.Cache, key string, ttl time.Duration, loader func(ctx context.Context) (T, error), o *cacheOptions) {
+	ctx, cancel := context.WithTimeout(ctx, loadTimeout)
+	defer cancel()
+
+	_, _, _ = loadGroup.Do("revalidate:"+key, func() (any, error) {
+		if locker, ok := c.(cache.Locker); ok && o.lockTTL > 0 {
+			release, acquired, err := locker.TryLock(ctx, lockKey(key), o.lockTTL)
+			if err != nil || !acquired {
+				// Another instance is already refreshing this key.
+				return nil, err
+			}
+			defer release()
+		}
+
+		data, err := loader(ctx)
+		if err != nil {
+			slog.Warn("cache revalidation failed", slog.String("key", key), slog.Any("error", err))
+			return nil, err
+		}
+
+		store(ctx, c, key, data, ttl, o)
+		return nil, nil
+	})
+}
+
+func store[T any](ctx context.Context, c cache.Cache, key string, data T, ttl time.Duration, o *cacheOptions) {
+	if o.negativeTTL > 0 && isEmpty(data) {
+		ttl = o.negativeTTL
+	}
+	if o.degraded != nil && o.degraded(data) {
+		ttl = min(ttl, o.degradedTTL)
+	}
+
+	SaveToCache(ctx, c, key, cacheEnvelope[T]{
+		Value:      data,
+		FreshUntil: time.Now().Add(ttl),
+	}, ttl+o.staleTTL)
+}
+
+func waitForValue[T any](ctx context.Context, c cache.Cache, key string, timeout time.Duration) (T, bool) {
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	deadline := time.After(timeout)
+	for {
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, false
+		case <-deadline:
+			var zero T
+			return zero, false
+		case <-ticker.C:
+			if entry, ok := TryGetFromCache[cacheEnvelope[T]](ctx, c, key); ok {
+				return entry.Value, true
+			}
+		}
+	}
+}
+
+func lockKey(key string) string {
+	return key + ":lock"
+}
+
+func isEmpty(value any) bool {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
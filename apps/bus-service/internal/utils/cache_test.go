@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/cache"
+)
+
+func TestWithCacheRevalidatesAfterRequestEnds(t *testing.T) {
+	c := cache.NewMemoryCache(10)
+	SaveToCache(context.Background(), c, "key", cacheEnvelope[string]{
+		Value:      "stale",
+		FreshUntil: time.Now().Add(-time.Second),
+	}, time.Minute)
+
+	refreshed := make(chan error, 1)
+	loader := func(ctx context.Context) (string, error) {
+		// Give the request time to finish before the refresh does its work.
+		time.Sleep(10 * time.Millisecond)
+		refreshed <- ctx.Err()
+		return "fresh", ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	got, err := WithCache(ctx, c, "key", time.Minute, loader, WithStaleWhileRevalidate(time.Minute))
+	cancel()
+	if err != nil || got != "stale" {
+		t.Fatalf("WithCache() = %q, %v; want the stale value", got, err)
+	}
+
+	select {
+	case err := <-refreshed:
+		if err != nil {
+			t.Fatalf("refresh ran with a done context: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stale entry was not refreshed")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if entry, ok := TryGetFromCache[cacheEnvelope[string]](context.Background(), c, "key"); ok && entry.Value == "fresh" {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("refreshed value was not stored")
+}
+
+func TestWithCacheLoadsWithCallerContext(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+
+	got, err := WithCache(ctx, cache.NewMemoryCache(10), "key", time.Minute, func(ctx context.Context) (string, error) {
+		return ctx.Value(ctxKey{}).(string), nil
+	})
+	if err != nil || got != "request" {
+		t.Fatalf("WithCache() = %q, %v; want the caller's context", got, err)
+	}
+}
+
+func TestWithCacheShortensDegradedTTL(t *testing.T) {
+	c := cache.NewMemoryCache(10)
+	degraded := WithDegradedTTL(time.Minute, func(value any) bool { return value.(string) == "estimated" })
+
+	for _, value := range []string{"routed", "estimated"} {
+		start := time.Now()
+		if _, err := WithCache(context.Background(), c, value, 24*time.Hour, func(context.Context) (string, error) {
+			return value, nil
+		}, degraded); err != nil {
+			t.Fatalf("WithCache(%s) error = %v", value, err)
+		}
+
+		entry, ok := TryGetFromCache[cacheEnvelope[string]](context.Background(), c, value)
+		if !ok {
+			t.Fatalf("%s value was not cached", value)
+		}
+		want := 24 * time.Hour
+		if value == "estimated" {
+			want = time.Minute
+		}
+		if ttl := entry.FreshUntil.Sub(start); ttl < want || ttl > want+time.Second {
+			t.Errorf("%s value is fresh for %v, want %v", value, ttl, want)
+		}
+	}
+}
+
+func TestWithCacheSharedLoadOutlivesFirstCallerDeadline(t *testing.T) {
+	c := cache.NewMemoryCache(10)
+	started := make(chan struct{})
+	loader := func(ctx context.Context) (string, error) {
+		close(started)
+		select {
+		case <-time.After(50 * time.Millisecond):
+			return "loaded", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	first := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := WithCache(ctx, c, "key", time.Minute, loader)
+		first <- err
+	}()
+	<-started
+
+	// The waiter joins the load the first caller started and has a longer
+	// budget.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	got, err := WithCache(ctx, c, "key", time.Minute, loader)
+	if err != nil || got != "loaded" {
+		t.Fatalf("WithCache() = %q, %v; want the shared value", got, err)
+	}
+	if err := <-first; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("first caller error = %v, want its own deadline", err)
+	}
+}
@@ -1,9 +1,66 @@
 package utils
 
-import "time"
+import (
+	"sync/atomic"
+	"time"
 
+	// Embed the timezone database; the runtime image ships without one.
+	_ "time/tzdata"
+)
+
+// DefaultTimezone is the timezone of the transit agency. Dates and times in
+// timetables are wall-clock times in this zone.
+const DefaultTimezone = "Europe/Ljubljana"
+
+var location atomic.Pointer[time.Location]
+
+// clock is the source of Now, replaced in tests.
+var clock = time.Now
+
+func init() {
+	loc, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		panic(err)
+	}
+	location.Store(loc)
+}
+
+// SetLocation changes the agency timezone used by Now and the date helpers.
+func SetLocation(loc *time.Location) {
+	location.Store(loc)
+}
+
+// Location returns the agency timezone.
+func Location() *time.Location {
+	return location.Load()
+}
+
+// Now returns the current time in the agency timezone.
+func Now() time.Time {
+	return clock().In(Location())
+}
+
+// InLocation converts a timestamp read from the database to the agency
+// timezone. Columns are TIMESTAMP WITHOUT TIME ZONE written by a server
+// running in UTC, so the driver returns them as UTC.
+func InLocation(t time.Time) time.Time {
+	return t.In(Location())
+}
+
+// Today returns the current date in the agency timezone, independent of the
+// server's local clock.
 func Today() string {
-	return time.Now().Format("2006-01-02")
+	return Now().Format("2006-01-02")
+}
+
+// ServiceDate returns the date of the current service day. Until
+// ServiceDayStart it is still the previous day, matching NowServiceTime.
+func ServiceDate() string {
+	now := Now()
+	if now.Hour()*60+now.Minute() < int(ServiceDayStart) {
+		now = now.AddDate(0, 0, -1)
+	}
+	return now.Format("2006-01-02")
 }
 
 func ValidateDate(dateStr string) bool {
@@ -11,12 +68,42 @@ func ValidateDate(dateStr string) bool {
 	return err == nil
 }
 
+// ServiceDateTime returns the instant a service time refers to on the given
+// service date. Times skipped when the clocks spring forward move on by the
+// size of the gap, e.g. 02:30 becomes 03:30. Times repeated when the clocks
+// fall back refer to their first occurrence, so departures keep their
+// timetable order.
+func ServiceDateTime(date string, t ServiceTime) (time.Time, error) {
+	day, err := time.ParseInLocation("2006-01-02", date, Location())
+	if err != nil {
+		return time.Time{}, err
+	}
+	minutes := int(t) % MinutesPerDay
+	at := time.Date(day.Year(), day.Month(), day.Day()+t.DayOffset(), minutes/60, minutes%60, 0, 0, Location())
+	if earlier := at.Add(-time.Hour); earlier.Format("2006-01-02 15:04") == at.Format("2006-01-02 15:04") {
+		at = earlier
+	}
+	return at, nil
+}
+
+// NowServiceTime returns the current wall-clock time as a service time of
+// today's service day.
+func NowServiceTime() ServiceTime {
+	now := Now()
+	t := ServiceTime(now.Hour()*60 + now.Minute())
+	if t < ServiceDayStart {
+		t += MinutesPerDay
+	}
+	return t
+}
+
 func nextOrTodayMatchingDate(match func(time.Weekday) bool) string {
-	now := time.Now()
+	now := Now()
 	for {
 		if match(now.Weekday()) {
 			return now.Format("2006-01-02")
 		}
+		// AddDate keeps the wall clock, so a DST switch cannot skip a day.
 		now = now.AddDate(0, 0, 1)
 	}
 }
@@ -0,0 +1,175 @@
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perkzen/mbus/apps/bus-service/internal/store"
+	"github.com/perkzen/mbus/apps/bus-service/internal/utils"
+)
+
+// In 2025 Europe/Ljubljana springs forward on 30 March at 02:00 CET and falls
+// back on 26 October at 03:00 CEST.
+
+func useLjubljana(t *testing.T) {
+	t.Helper()
+	loc, err := time.LoadLocation("Europe/Ljubljana")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevLoc, prevLocal := utils.Location(), time.Local
+	utils.SetLocation(loc)
+	// The server runs in UTC; results must not depend on it.
+	time.Local = time.UTC
+	t.Cleanup(func() {
+		utils.SetLocation(prevLoc)
+		time.Local = prevLocal
+	})
+}
+
+func TestServiceDateTimeAcrossDST(t *testing.T) {
+	useLjubljana(t)
+
+	tests := []struct {
+		name string
+		date string
+		time string
+		want string // UTC instant
+	}{
+		{"before spring forward", "2025-03-30", "01:59", "2025-03-30T00:59:00Z"},
+		{"in the spring gap", "2025-03-30", "02:30", "2025-03-30T01:30:00Z"},
+		{"after spring forward", "2025-03-30", "03:00", "2025-03-30T01:00:00Z"},
+		{"past midnight into spring forward", "2025-03-29", "24:30", "2025-03-29T23:30:00Z"},
+		{"night trip into the spring gap", "2025-03-29", "26:30", "2025-03-30T01:30:00Z"},
+		{"day after spring forward", "2025-03-30", "22:00", "2025-03-30T20:00:00Z"},
+		{"before fall back", "2025-10-26", "01:59", "2025-10-25T23:59:00Z"},
+		{"in the repeated hour", "2025-10-26", "02:30", "2025-10-26T00:30:00Z"},
+		{"after fall back", "2025-10-26", "03:00", "2025-10-26T02:00:00Z"},
+		{"past midnight into fall back", "2025-10-25", "24:30", "2025-10-25T22:30:00Z"},
+		{"night trip past fall back", "2025-10-25", "27:00", "2025-10-26T02:00:00Z"},
+		{"day after fall back", "2025-10-26", "22:00", "2025-10-26T21:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, err := utils.ParseServiceTime(tt.time)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := utils.ServiceDateTime(tt.date, st)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := got.UTC().Format(time.RFC3339); got != tt.want {
+				t.Errorf("ServiceDateTime(%s, %s) = %s, want %s", tt.date, tt.time, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceDateTimeDurationAcrossDST(t *testing.T) {
+	useLjubljana(t)
+
+	tests := []struct {
+		date, from, to string
+		want           time.Duration
+	}{
+		// The clocks skip an hour, so two hours on the timetable take one.
+		{"2025-03-30", "01:30", "03:30", time.Hour},
+		// The clocks repeat an hour, so the trip takes three.
+		{"2025-10-26", "01:30", "03:30", 3 * time.Hour},
+		{"2025-03-29", "23:50", "24:10", 20 * time.Minute},
+	}
+	for _, tt := range tests {
+		from, _ := utils.ParseServiceTime(tt.from)
+		to, _ := utils.ParseServiceTime(tt.to)
+		dep, err := utils.ServiceDateTime(tt.date, from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		arr, err := utils.ServiceDateTime(tt.date, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := arr.Sub(dep); got != tt.want {
+			t.Errorf("%s %s-%s took %s, want %s", tt.date, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestTodayLateEveningAroundDST(t *testing.T) {
+	useLjubljana(t)
+
+	tests := []struct {
+		name        string
+		now         string // UTC instant
+		today       string
+		schedule    store.ScheduleType
+		serviceDate string
+	}{
+		{"Saturday 22:30 CET", "2025-03-29T21:30:00Z", "2025-03-29", store.ScheduleTypeSaturday, "2025-03-29"},
+		{"Saturday 23:59 CET", "2025-03-29T22:59:00Z", "2025-03-29", store.ScheduleTypeSaturday, "2025-03-29"},
+		{"Sunday 00:00 CET, still Saturday in UTC", "2025-03-29T23:00:00Z", "2025-03-30", store.ScheduleTypeSunday, "2025-03-29"},
+		{"Sunday 22:00 CEST", "2025-03-30T20:00:00Z", "2025-03-30", store.ScheduleTypeSunday, "2025-03-30"},
+		{"Sunday 23:59 CEST", "2025-03-30T21:59:00Z", "2025-03-30", store.ScheduleTypeSunday, "2025-03-30"},
+		{"Monday 00:30 CEST, still Sunday in UTC", "2025-03-30T22:30:00Z", "2025-03-31", store.ScheduleTypeWeekday, "2025-03-30"},
+		{"Saturday 22:00 CEST", "2025-10-25T20:00:00Z", "2025-10-25", store.ScheduleTypeSaturday, "2025-10-25"},
+		{"Saturday 23:59 CEST", "2025-10-25T21:59:00Z", "2025-10-25", store.ScheduleTypeSaturday, "2025-10-25"},
+		{"Sunday 00:10 CEST, still Saturday in UTC", "2025-10-25T22:10:00Z", "2025-10-26", store.ScheduleTypeSunday, "2025-10-25"},
+		{"Sunday 22:30 CET", "2025-10-26T21:30:00Z", "2025-10-26", store.ScheduleTypeSunday, "2025-10-26"},
+		{"Sunday 23:59 CET", "2025-10-26T22:59:00Z", "2025-10-26", store.ScheduleTypeSunday, "2025-10-26"},
+		{"Monday 00:00 CET, still Sunday in UTC", "2025-10-26T23:00:00Z", "2025-10-27", store.ScheduleTypeWeekday, "2025-10-26"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now, err := time.Parse(time.RFC3339, tt.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer utils.SetClock(func() time.Time { return now })()
+
+			if got := utils.Today(); got != tt.today {
+				t.Errorf("Today() = %s, want %s", got, tt.today)
+			}
+			if got := store.ScheduleTyp(utils.Today()); got != tt.schedule {
+				t.Errorf("ScheduleTyp(Today()) = %s, want %s", got, tt.schedule)
+			}
+			if got := utils.ServiceDate(); got != tt.serviceDate {
+				t.Errorf("ServiceDate() = %s, want %s", got, tt.serviceDate)
+			}
+		})
+	}
+}
+
+// Between midnight and the service day start, "now" still belongs to the
+// previous service day, so requests defaulting the date to ServiceDate and
+// the window to NowServiceTime see tonight's night departures.
+func TestNowAtHalfPastOne(t *testing.T) {
+	useLjubljana(t)
+
+	// Tuesday 01:30 CEST.
+	now := time.Date(2025, 6, 10, 1, 30, 0, 0, utils.Location())
+	defer utils.SetClock(func() time.Time { return now })()
+
+	date, at := utils.ServiceDate(), utils.NowServiceTime()
+	if date != "2025-06-09" {
+		t.Errorf("ServiceDate() = %s, want 2025-06-09", date)
+	}
+	if at != utils.ServiceTime(25*60+30) {
+		t.Errorf("NowServiceTime() = %s, want 25:30", at)
+	}
+	if got, err := utils.ServiceDateTime(date, at); err != nil || !got.Equal(now) {
+		t.Errorf("ServiceDateTime(%s, %s) = %v, %v, want %v", date, at, got, err, now)
+	}
+
+	// A night departure at 01:45 is on the timetable of Monday's service day.
+	night, err := utils.ParseServiceTime("25:45")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if night < at {
+		t.Errorf("night departure %s sorts before now %s", night, at)
+	}
+	if got, _ := utils.ServiceDateTime(date, night); got.Sub(now) != 15*time.Minute {
+		t.Errorf("night departure leaves in %v, want 15m", got.Sub(now))
+	}
+}
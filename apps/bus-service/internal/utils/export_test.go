@@ -0,0 +1,9 @@
+package utils
+
+import "time"
+
+// SetClock makes Now return the time reported by now until restore is called.
+func SetClock(now func() time.Time) (restore func()) {
+	clock = now
+	return func() { clock = time.Now }
+}
@@ -5,17 +5,13 @@ import (
 )
 
 type HasDepartureAt interface {
-	GetDepartureAt() string
+	GetDepartureAt() ServiceTime
 }
 
+// SortByDepartureAtAsc orders items by service time, so departures after
+// midnight come after the evening ones of the same service day.
 func SortByDepartureAtAsc[T HasDepartureAt](items []T) {
 	sort.SliceStable(items, func(i, j int) bool {
-		t1, err1 := ParseClock(items[i].GetDepartureAt())
-		t2, err2 := ParseClock(items[j].GetDepartureAt())
-
-		if err1 != nil || err2 != nil {
-			return false
-		}
-		return t1.Before(t2)
+		return items[i].GetDepartureAt() < items[j].GetDepartureAt()
 	})
 }
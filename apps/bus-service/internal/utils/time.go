@@ -1,15 +1,132 @@
 package utils
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 )
 
-func ParseClock(s string) (time.Time, error) {
-	return time.Parse("15:04", s)
+const MinutesPerDay = 24 * 60
+
+// ServiceDayStart is the wall-clock time at which a new service day begins.
+// Departures before it belong to the night service of the previous day.
+const ServiceDayStart ServiceTime = 3 * 60
+
+// maxServiceTime bounds service times to two days, enough for any trip that
+// starts before midnight.
+const maxServiceTime ServiceTime = 2 * MinutesPerDay
+
+// ServiceTime is a time of day in minutes since midnight at the start of the
+// service day. Like GTFS, it keeps counting past midnight, so a bus leaving at
+// 23:50 arrives at 24:10 rather than 00:10.
+type ServiceTime int
+
+// ParseServiceTime parses "HH:MM", allowing hours of 24 and above.
+func ParseServiceTime(s string) (ServiceTime, error) {
+	h, m, ok := strings.Cut(s, ":")
+	if !ok || len(m) != 2 || len(h) < 2 {
+		return 0, fmt.Errorf("invalid service time %q", s)
+	}
+
+	hours, err := strconv.Atoi(h)
+	if err != nil {
+		return 0, fmt.Errorf("invalid service time %q", s)
+	}
+	minutes, err := strconv.Atoi(m)
+	if err != nil || minutes > 59 {
+		return 0, fmt.Errorf("invalid service time %q", s)
+	}
+
+	t := ServiceTime(hours*60 + minutes)
+	if hours < 0 || minutes < 0 || t >= maxServiceTime {
+		return 0, fmt.Errorf("invalid service time %q", s)
+	}
+	return t, nil
+}
+
+// ServiceTimeFromClock parses a wall-clock "HH:MM" as printed in timetables.
+// Times before ServiceDayStart are moved past midnight of the service day.
+func ServiceTimeFromClock(s string) (ServiceTime, error) {
+	t, err := ParseServiceTime(s)
+	if err != nil {
+		return 0, err
+	}
+	if t >= MinutesPerDay {
+		return 0, fmt.Errorf("invalid clock time %q", s)
+	}
+	if t < ServiceDayStart {
+		t += MinutesPerDay
+	}
+	return t, nil
+}
+
+// AddMinutes returns t moved by a fractional number of minutes, rounded.
+func (t ServiceTime) AddMinutes(minutes float64) ServiceTime {
+	return t + ServiceTime(math.Round(minutes))
+}
+
+// Clock returns the wall-clock time, e.g. "00:10" for 24:10.
+func (t ServiceTime) Clock() string {
+	return formatHoursMinutes(int(t) % MinutesPerDay)
+}
+
+// DayOffset is the number of midnights between the start of the service day
+// and t.
+func (t ServiceTime) DayOffset() int {
+	return int(t) / MinutesPerDay
+}
+
+// String returns the GTFS-style representation, e.g. "24:10".
+func (t ServiceTime) String() string {
+	return formatHoursMinutes(int(t))
+}
+
+func (t ServiceTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+func (t *ServiceTime) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseServiceTime(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// FormatDuration returns the time between two service times as "HH:MM".
+func FormatDuration(from, to ServiceTime) string {
+	return formatHoursMinutes(max(int(to-from), 0))
+}
+
+// ISODuration formats d as an ISO 8601 duration, e.g. "PT1H5M". Seconds are
+// only included when d is not a whole number of minutes.
+func ISODuration(d time.Duration) string {
+	if d <= 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	b.WriteString("PT")
+	if h := d / time.Hour; h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+	}
+	if m := d % time.Hour / time.Minute; m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+	}
+	if s := d % time.Minute / time.Second; s > 0 {
+		fmt.Fprintf(&b, "%dS", s)
+	}
+	return b.String()
 }
 
-func FormatDuration(from, to time.Time) string {
-	dur := to.Sub(from)
-	return fmt.Sprintf("%02d:%02d", int(dur.Hours()), int(dur.Minutes())%60)
+func formatHoursMinutes(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
 }
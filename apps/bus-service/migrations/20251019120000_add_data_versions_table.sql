@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Single-row table holding the data generation. Every seed, sync or admin
+-- write bumps it, which moves all cache keys to a fresh namespace.
+CREATE TABLE IF NOT EXISTS data_versions
+(
+    id         SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    generation BIGINT NOT NULL DEFAULT 1,
+    reason     TEXT,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO data_versions (id, reason)
+VALUES (1, 'init')
+ON CONFLICT (id) DO NOTHING;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS data_versions;
+
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Travel time and distance between consecutive stops of a line in one
+-- direction, filled in by cmd/compute-segments.
+CREATE TABLE IF NOT EXISTS segments
+(
+    id           SERIAL PRIMARY KEY,
+    line_id      INTEGER          NOT NULL REFERENCES bus_lines (id) ON DELETE CASCADE,
+    direction_id INTEGER          NOT NULL REFERENCES directions (id) ON DELETE CASCADE,
+    sequence     INTEGER          NOT NULL,
+    from_code_id INTEGER          NOT NULL REFERENCES station_codes (id) ON DELETE CASCADE,
+    to_code_id   INTEGER          NOT NULL REFERENCES station_codes (id) ON DELETE CASCADE,
+    distance_km  DOUBLE PRECISION NOT NULL,
+    duration_min DOUBLE PRECISION NOT NULL,
+    provider     TEXT             NOT NULL,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE (line_id, direction_id, sequence)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS segments;
+
+-- +goose StatementEnd
@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Store departure times as minutes since the start of the service day. Times
+-- before 03:00 belong to the night service of the previous day and are stored
+-- past midnight (e.g. 00:10 becomes 24:10 = 1450), GTFS-style.
+ALTER TABLE departures
+    ADD COLUMN departure_minutes INTEGER;
+
+UPDATE departures
+SET departure_minutes = split_part(departure_time, ':', 1)::INTEGER * 60
+                            + split_part(departure_time, ':', 2)::INTEGER;
+
+UPDATE departures
+SET departure_minutes = departure_minutes + 1440
+WHERE departure_minutes < 180;
+
+ALTER TABLE departures
+    DROP CONSTRAINT IF EXISTS uq_departure;
+ALTER TABLE departures
+    DROP COLUMN departure_time;
+ALTER TABLE departures
+    RENAME COLUMN departure_minutes TO departure_time;
+
+ALTER TABLE departures
+    ALTER COLUMN departure_time SET NOT NULL;
+ALTER TABLE departures
+    ADD CONSTRAINT departure_time_range CHECK (departure_time >= 0 AND departure_time < 2880);
+ALTER TABLE departures
+    ADD CONSTRAINT uq_departure
+        UNIQUE (code_id, line_id, direction_id, departure_time, schedule_type);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE departures
+    ADD COLUMN departure_clock VARCHAR(5);
+
+UPDATE departures
+SET departure_clock = lpad(((departure_time % 1440) / 60)::TEXT, 2, '0') || ':' ||
+                      lpad((departure_time % 60)::TEXT, 2, '0');
+
+ALTER TABLE departures
+    DROP CONSTRAINT IF EXISTS uq_departure;
+ALTER TABLE departures
+    DROP COLUMN departure_time;
+ALTER TABLE departures
+    RENAME COLUMN departure_clock TO departure_time;
+
+ALTER TABLE departures
+    ALTER COLUMN departure_time SET NOT NULL;
+ALTER TABLE departures
+    ADD CONSTRAINT departure_time_format CHECK (departure_time ~ '^\d{2}:\d{2}$');
+ALTER TABLE departures
+    ADD CONSTRAINT uq_departure
+        UNIQUE (code_id, line_id, direction_id, departure_time, schedule_type);
+
+-- +goose StatementEnd
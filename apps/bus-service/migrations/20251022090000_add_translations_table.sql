@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Localized values of free-text fields, e.g. bus line descriptions. Agency
+-- data is Slovenian, so the 'sl' row is the source text and other languages
+-- fall back to it.
+CREATE TABLE IF NOT EXISTS translations
+(
+    entity_type TEXT    NOT NULL,
+    entity_id   INTEGER NOT NULL,
+    field       TEXT    NOT NULL,
+    lang        TEXT    NOT NULL,
+    value       TEXT    NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (entity_type, entity_id, field, lang)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS translations;
+
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Saved trips whose departures are reminded through a notification channel.
+-- Window bounds are service minutes like departures.departure_time; only the
+-- hash of the management token is kept. Email addresses must opt in before
+-- they are sent reminders: while a subscription is unconfirmed only the hash
+-- of the code sent to its target is kept.
+CREATE TABLE IF NOT EXISTS subscriptions
+(
+    id                SERIAL PRIMARY KEY,
+    name              TEXT     NOT NULL,
+    from_station_id   INTEGER  NOT NULL REFERENCES bus_stations (id) ON DELETE CASCADE,
+    to_station_id     INTEGER  NOT NULL REFERENCES bus_stations (id) ON DELETE CASCADE,
+    lines             TEXT[]   NOT NULL DEFAULT '{}',
+    weekdays          TEXT[]   NOT NULL,
+    window_start      INTEGER  NOT NULL,
+    window_end        INTEGER  NOT NULL CHECK (window_end >= window_start),
+    lead_minutes      INTEGER  NOT NULL DEFAULT 5,
+    channel           TEXT     NOT NULL,
+    target            TEXT     NOT NULL,
+    lang              TEXT     NOT NULL DEFAULT 'en',
+    token_hash        BYTEA    NOT NULL,
+    confirmation_hash BYTEA,
+    confirmed_at      TIMESTAMP,
+    created_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per reminded departure. Inserting it claims the reminder, so
+-- several scheduler instances never send the same one twice.
+CREATE TABLE IF NOT EXISTS subscription_reminders
+(
+    subscription_id INTEGER NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    service_date    DATE    NOT NULL,
+    departure_id    INTEGER NOT NULL,
+    sent_at         TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (subscription_id, service_date, departure_id)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS subscription_reminders;
+DROP TABLE IF EXISTS subscriptions;
+
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Accessibility and amenities of a bus station. NULL means unknown; filters
+-- and the step-free preference only trust an explicit TRUE.
+CREATE TABLE IF NOT EXISTS station_attributes
+(
+    station_id            INTEGER PRIMARY KEY REFERENCES bus_stations (id) ON DELETE CASCADE,
+    wheelchair_accessible BOOLEAN,
+    shelter               BOOLEAN,
+    bench                 BOOLEAN,
+    realtime_display      BOOLEAN,
+    ticket_machine        BOOLEAN,
+    tactile_paving        BOOLEAN,
+    created_at            TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at            TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS station_attributes;
+
+-- +goose StatementEnd
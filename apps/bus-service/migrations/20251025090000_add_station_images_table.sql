@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Station photos copied from the operator. The image itself lives in the
+-- object store under its content hash; a NULL hash records that source_url
+-- could not be used, so it is not fetched again on every request.
+CREATE TABLE IF NOT EXISTS station_images
+(
+    station_id   INTEGER PRIMARY KEY REFERENCES bus_stations (id) ON DELETE CASCADE,
+    source_url   VARCHAR(255) NOT NULL,
+    hash         CHAR(64),
+    content_type VARCHAR(64),
+    width        INTEGER,
+    height       INTEGER,
+    fetched_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS station_images;
+
+-- +goose StatementEnd
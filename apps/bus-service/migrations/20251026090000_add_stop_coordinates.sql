@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Station codes are the stops of a station, e.g. one per side of the road,
+-- each with its own position. Until the next seed they share the position of
+-- their station.
+ALTER TABLE station_codes
+    ADD COLUMN lat      DECIMAL(9, 6),
+    ADD COLUMN lng      DECIMAL(9, 6),
+    ADD COLUMN platform VARCHAR(8);
+
+UPDATE station_codes sc
+SET lat = bs.lat,
+    lng = bs.lng
+FROM bus_stations bs
+WHERE bs.id = sc.station_id;
+
+ALTER TABLE station_codes
+    ALTER COLUMN lat SET NOT NULL,
+    ALTER COLUMN lng SET NOT NULL;
+
+-- Platforms are lettered in code order within their station: A, B, ...
+UPDATE station_codes sc
+SET platform = p.platform
+FROM (SELECT id,
+             CASE
+                 WHEN n <= 26 THEN chr(64 + n::int)
+                 ELSE n::text
+                 END AS platform
+      FROM (SELECT id, row_number() OVER (PARTITION BY station_id ORDER BY code) AS n
+            FROM station_codes) numbered) p
+WHERE p.id = sc.id;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE station_codes
+    DROP COLUMN IF EXISTS lat,
+    DROP COLUMN IF EXISTS lng,
+    DROP COLUMN IF EXISTS platform;
+
+-- +goose StatementEnd
@@ -0,0 +1,59 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Display metadata of a line as derived by the seeder. Colours are #RRGGBB;
+-- sort_order ranks lines in lists before their natural order.
+ALTER TABLE bus_lines
+    ADD COLUMN category   TEXT    NOT NULL DEFAULT 'other' CHECK (category IN ('city', 'suburban', 'other')),
+    ADD COLUMN color      CHAR(7) CHECK (color ~ '^#[0-9A-F]{6}$'),
+    ADD COLUMN text_color CHAR(7) CHECK (text_color ~ '^#[0-9A-F]{6}$'),
+    ADD COLUMN long_name  TEXT,
+    ADD COLUMN operator   TEXT,
+    ADD COLUMN sort_order INTEGER NOT NULL DEFAULT 0 CHECK (sort_order >= 0);
+
+-- G-lines run in the city, P-lines to the suburbs. Colours and long names
+-- follow with the next seed.
+UPDATE bus_lines
+SET category   = CASE
+                     WHEN name ~ '^G[0-9]' THEN 'city'
+                     WHEN name ~ '^P[0-9]' THEN 'suburban'
+                     ELSE 'other'
+    END,
+    operator   = 'Marprom',
+    sort_order = o.n
+FROM (SELECT id,
+             row_number() OVER (ORDER BY lpad(regexp_replace(name, '[^0-9]', '', 'g'), 4, '0') || name) AS n
+      FROM bus_lines) o
+WHERE o.id = bus_lines.id;
+
+-- Values set by an admin. They survive reseeding; NULL keeps the seeded
+-- value.
+CREATE TABLE IF NOT EXISTS bus_line_overrides
+(
+    bus_line_id INTEGER PRIMARY KEY REFERENCES bus_lines (id) ON DELETE CASCADE,
+    category    TEXT CHECK (category IN ('city', 'suburban', 'other')),
+    color       CHAR(7) CHECK (color ~ '^#[0-9A-F]{6}$'),
+    text_color  CHAR(7) CHECK (text_color ~ '^#[0-9A-F]{6}$'),
+    long_name   TEXT,
+    operator    TEXT,
+    sort_order  INTEGER CHECK (sort_order >= 0),
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS bus_line_overrides;
+
+ALTER TABLE bus_lines
+    DROP COLUMN IF EXISTS category,
+    DROP COLUMN IF EXISTS color,
+    DROP COLUMN IF EXISTS text_color,
+    DROP COLUMN IF EXISTS long_name,
+    DROP COLUMN IF EXISTS operator,
+    DROP COLUMN IF EXISTS sort_order;
+
+-- +goose StatementEnd
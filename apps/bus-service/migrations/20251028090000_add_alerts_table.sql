@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Service notices such as detours or stop closures. Title and body are the
+-- Slovenian source text; other languages are rows in translations with
+-- entity_type 'alert'. An alert without lines applies to the whole network.
+CREATE TABLE IF NOT EXISTS alerts
+(
+    id           SERIAL PRIMARY KEY,
+    title        TEXT        NOT NULL,
+    body         TEXT        NOT NULL DEFAULT '',
+    lines        TEXT[]      NOT NULL DEFAULT '{}',
+    active_from  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    active_until TIMESTAMPTZ CHECK (active_until > active_from),
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_alerts_active ON alerts (active_from, active_until);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DELETE FROM translations WHERE entity_type = 'alert';
+DROP TABLE IF EXISTS alerts;
+
+-- +goose StatementEnd